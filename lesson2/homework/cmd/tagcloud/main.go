@@ -0,0 +1,192 @@
+// Command tagcloud counts words of files or stdin and prints the most frequent of them
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"lecture02_homework/tagcloud"
+	"os"
+	"strconv"
+)
+
+type Options struct {
+	N             int
+	JSON          bool
+	CSV           bool
+	StopWords     string
+	CaseSensitive bool
+	Files         []string
+}
+
+func (o *Options) Validate() error {
+	if o.N <= 0 {
+		return fmt.Errorf("-n must be positive, got %d", o.N)
+	}
+	if o.JSON && o.CSV {
+		return fmt.Errorf("only one of -json and -csv can be used")
+	}
+	return nil
+}
+
+func parseFlags(args []string, stderr io.Writer) (*Options, error) {
+	var opts Options
+	flags := flag.NewFlagSet("tagcloud", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: tagcloud [flags] [file ...]\ncounts words of files, stdin if no file is given")
+		flags.PrintDefaults()
+	}
+	flags.IntVar(&opts.N, "n", 20, "number of most frequent words to print. by default - 20")
+	flags.BoolVar(&opts.JSON, "json", false, "print words as JSON array of {\"tag\", \"count\"} objects. by default - false")
+	flags.BoolVar(&opts.CSV, "csv", false, "print words as csv with word,count header. by default - false")
+	flags.StringVar(&opts.StopWords, "stopwords", "", "file with words to skip separated by whitespace")
+	flags.BoolVar(&opts.CaseSensitive, "case-sensitive", false, "count words differing in case separately. by default - false")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	opts.Files = flags.Args()
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return &opts, nil
+}
+
+// readStopWords returns whitespace separated words of the file
+func readStopWords(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var words []string
+	scanner := bufio.NewScanner(file)
+	scanner.Split(bufio.ScanWords)
+	for scanner.Scan() {
+		words = append(words, scanner.Text())
+	}
+	return words, scanner.Err()
+}
+
+func newCloud(opts *Options) (*tagcloud.TagCloud, error) {
+	var options []tagcloud.Option
+	if !opts.CaseSensitive {
+		options = append(options, tagcloud.WithCaseInsensitive())
+	}
+	if opts.StopWords != "" {
+		words, err := readStopWords(opts.StopWords)
+		if err != nil {
+			return nil, fmt.Errorf("can not read stop words: %v", err)
+		}
+		options = append(options, tagcloud.WithStopWords(words...))
+	}
+	return tagcloud.New(options...), nil
+}
+
+// countFiles adds words of every file to the cloud, an unreadable file is reported and skipped.
+// it returns false if any file failed
+func countFiles(cloud *tagcloud.TagCloud, files []string, stderr io.Writer) bool {
+	ok := true
+	for _, path := range files {
+		err := countFile(cloud, path)
+		if err != nil {
+			fmt.Fprintf(stderr, "can not read %s: %v\n", path, err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+func countFile(cloud *tagcloud.TagCloud, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = cloud.AddFromReader(file)
+	return err
+}
+
+func writeText(w io.Writer, stats []tagcloud.TagStat) error {
+	width := 0
+	if len(stats) > 0 {
+		width = len(strconv.Itoa(stats[0].OccurrenceCount))
+	}
+	for _, stat := range stats {
+		if _, err := fmt.Fprintf(w, "%*d %s\n", width, stat.OccurrenceCount, stat.Tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type jsonStat struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+func writeJSON(w io.Writer, stats []tagcloud.TagStat) error {
+	result := make([]jsonStat, len(stats))
+	for i, stat := range stats {
+		result[i] = jsonStat{Tag: stat.Tag, Count: stat.OccurrenceCount}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+func writeCSV(w io.Writer, stats []tagcloud.TagStat) error {
+	writer := csv.NewWriter(w)
+	_ = writer.Write([]string{"word", "count"})
+	for _, stat := range stats {
+		_ = writer.Write([]string{stat.Tag, strconv.Itoa(stat.OccurrenceCount)})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// run is main with injected arguments and streams, it returns the exit code
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	opts, err := parseFlags(args, stderr)
+	if err == flag.ErrHelp {
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "can not parse flags:", err)
+		return 2
+	}
+	cloud, err := newCloud(opts)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	code := 0
+	if len(opts.Files) == 0 {
+		if _, err := cloud.AddFromReader(stdin); err != nil {
+			fmt.Fprintln(stderr, "can not read stdin:", err)
+			code = 1
+		}
+	} else if !countFiles(cloud, opts.Files, stderr) {
+		code = 1
+	}
+
+	write := writeText
+	switch {
+	case opts.JSON:
+		write = writeJSON
+	case opts.CSV:
+		write = writeCSV
+	}
+	if err := write(stdout, cloud.TopN(opts.N)); err != nil {
+		fmt.Fprintln(stderr, "can not write result:", err)
+		return 1
+	}
+	return code
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
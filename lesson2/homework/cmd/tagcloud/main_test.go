@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testFiles = []string{"testdata/first.txt", "testdata/second.txt"}
+
+func assertGolden(t *testing.T, path string, actual string) {
+	t.Helper()
+	expected, err := os.ReadFile(path)
+	if assert.NoError(t, err) {
+		assert.Equal(t, string(expected), actual)
+	}
+}
+
+func runCommand(args []string, stdin string) (int, string, string) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	code := run(args, strings.NewReader(stdin), stdout, stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun(t *testing.T) {
+	for name, format := range map[string]string{"text": "", "json": "-json", "csv": "-csv"} {
+		t.Run("ok, "+name+" output", func(t *testing.T) {
+			args := []string{"-n", "5", "-stopwords", "testdata/stopwords.txt"}
+			if format != "" {
+				args = append(args, format)
+			}
+			extension := map[string]string{"text": "txt", "json": "json", "csv": "csv"}[name]
+
+			code, stdout, stderr := runCommand(append(args, testFiles...), "")
+
+			assert.Equal(t, 0, code)
+			assert.Empty(t, stderr)
+			assertGolden(t, "testdata/golden/top."+extension, stdout)
+		})
+	}
+
+	t.Run("ok, stdin with aligned counts", func(t *testing.T) {
+		code, stdout, stderr := runCommand([]string{"-n", "2"}, strings.Repeat("go ", 12)+"Rust rust")
+
+		assert.Equal(t, 0, code)
+		assert.Empty(t, stderr)
+		assert.Equal(t, "12 go\n 2 rust\n", stdout)
+	})
+
+	t.Run("ok, case sensitive", func(t *testing.T) {
+		code, stdout, _ := runCommand([]string{"-case-sensitive"}, "Go go Go")
+
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "2 Go\n1 go\n", stdout)
+	})
+
+	t.Run("ok, empty input", func(t *testing.T) {
+		code, stdout, _ := runCommand([]string{"-json"}, "")
+
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "[]\n", stdout)
+	})
+
+	t.Run("error, unreadable file doesn't stop others", func(t *testing.T) {
+		code, stdout, stderr := runCommand([]string{"-n", "1", "testdata/missing.txt", "testdata/first.txt"}, "")
+
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, "testdata/missing.txt")
+		assert.Equal(t, "4 go\n", stdout)
+	})
+
+	t.Run("error, invalid flags", func(t *testing.T) {
+		for _, args := range [][]string{{"-n", "0"}, {"-json", "-csv"}, {"-unknown"}} {
+			code, stdout, stderr := runCommand(args, "go")
+
+			assert.Equal(t, 2, code, args)
+			assert.Empty(t, stdout)
+			assert.NotEmpty(t, stderr)
+		}
+	})
+
+	t.Run("error, missing stop words file", func(t *testing.T) {
+		code, _, stderr := runCommand([]string{"-stopwords", "testdata/missing.txt"}, "go")
+
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, "can not read stop words")
+	})
+}
@@ -1,8 +1,11 @@
 module lecture02_homework
 
-go 1.19
+go 1.24
 
-require github.com/stretchr/testify v1.8.2
+require (
+	github.com/stretchr/testify v1.8.2
+	golang.org/x/text v0.15.0
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
@@ -0,0 +1,37 @@
+package tagcloud
+
+// AddTags adds every tag to the cloud, repeated tags are counted as many times as they occur
+func (cloud *TagCloud) AddTags(tags ...string) {
+	cloud.lock()
+	defer cloud.unlock()
+	for _, tag := range tags {
+		cloud.addTagN(tag, 1)
+	}
+}
+
+// AddTagN works the same as calling AddTag n times.
+// non-positive n is clamped to zero, so such call doesn't change the cloud
+func (cloud *TagCloud) AddTagN(tag string, n int) {
+	cloud.lock()
+	defer cloud.unlock()
+	cloud.addTagN(tag, n)
+}
+
+// AddFromMap adds precomputed counts, non-positive counts are ignored as in AddTagN
+func (cloud *TagCloud) AddFromMap(m map[string]int) {
+	cloud.lock()
+	defer cloud.unlock()
+	for tag, n := range m {
+		cloud.addTagN(tag, n)
+	}
+}
+
+// addTagN normalizes the tag, so it's used by all methods adding tags from outside
+func (cloud *TagCloud) addTagN(tag string, n int) {
+	key, ok := cloud.key(tag)
+	if n <= 0 || !ok {
+		return
+	}
+	cloud.setCount(key, cloud.tags.Count(key)+n)
+	cloud.noteSurface(tag, key, n)
+}
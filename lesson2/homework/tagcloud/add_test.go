@@ -0,0 +1,159 @@
+package tagcloud_test
+
+import (
+	"fmt"
+	"lecture02_homework/tagcloud"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddTags(t *testing.T) {
+	bulk := tagcloud.New()
+	bulk.AddTags("a", "b", "a", "c", "a")
+	bulk.AddTags()
+
+	single := tagcloud.New()
+	for _, tag := range []string{"a", "b", "a", "c", "a"} {
+		single.AddTag(tag)
+	}
+
+	assert.Equal(t, single.TopN(10), bulk.TopN(10))
+}
+
+func TestAddTagN(t *testing.T) {
+	t.Run("ok, same as repeated AddTag", func(t *testing.T) {
+		bulk := tagcloud.New()
+		single := tagcloud.New()
+		for i := 0; i < 50; i++ {
+			tag := fmt.Sprintf("t%d", i%7)
+			bulk.AddTagN(tag, i)
+			for j := 0; j < i; j++ {
+				single.AddTag(tag)
+			}
+		}
+
+		assert.Equal(t, single.TopN(100), bulk.TopN(100))
+	})
+
+	t.Run("ok, non-positive n is ignored", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("t1", 2)
+		tc.AddTagN("t1", -1)
+		tc.AddTagN("t2", 0)
+		tc.AddTagN("t3", -5)
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "t1", OccurrenceCount: 2}}, tc.TopN(10))
+	})
+}
+
+func TestAddFromMap(t *testing.T) {
+	tc := tagcloud.New()
+	tc.AddTag("a")
+	tc.AddFromMap(map[string]int{"a": 2, "b": 3, "c": 0, "d": -1})
+
+	assert.Equal(t, []tagcloud.TagStat{{Tag: "a", OccurrenceCount: 3}, {Tag: "b", OccurrenceCount: 3}}, tc.TopN(10))
+}
+
+func BenchmarkAddTagN(b *testing.B) {
+	for _, n := range []int{1, 1000, 1000000} {
+		b.Run(fmt.Sprintf("bulk n=%d", n), func(b *testing.B) {
+			tc := tagcloud.New()
+			for i := 0; i < b.N; i++ {
+				tc.AddTagN("tag", n)
+			}
+		})
+	}
+	for _, n := range []int{1, 1000} {
+		b.Run(fmt.Sprintf("loop n=%d", n), func(b *testing.B) {
+			tc := tagcloud.New()
+			for i := 0; i < b.N; i++ {
+				for j := 0; j < n; j++ {
+					tc.AddTag("tag")
+				}
+			}
+		})
+	}
+}
+
+func TestAddTagAllocs(t *testing.T) {
+	clouds := map[string]*tagcloud.TagCloud{
+		"plain":      tagcloud.New(),
+		"concurrent": tagcloud.NewConcurrent(),
+		"ordered":    tagcloud.NewOrdered(),
+		"stop words": tagcloud.New(tagcloud.WithStopWords("the")),
+		"ttl":        tagcloud.New(tagcloud.WithTTL(time.Hour)),
+		"lower case": tagcloud.New(tagcloud.WithCaseInsensitive()),
+	}
+	for name, tc := range clouds {
+		t.Run("ok, existing tag, "+name, func(t *testing.T) {
+			if raceEnabled {
+				t.Skip("allocations are unstable with the race detector")
+			}
+			tc.AddTag("go")
+
+			assert.Zero(t, testing.AllocsPerRun(100, func() { tc.AddTag("go") }))
+			assert.Zero(t, testing.AllocsPerRun(100, func() { tc.AddTagN("go", 2) }))
+			assert.Zero(t, testing.AllocsPerRun(100, func() { tc.AddTag("the") }))
+		})
+	}
+
+	t.Run("ok, reader allocations don't grow with repeated tokens", func(t *testing.T) {
+		if raceEnabled {
+			t.Skip("allocations are unstable with the race detector")
+		}
+		tc := tagcloud.New()
+		shortText, longText := strings.Repeat("go rust ", 10), strings.Repeat("go rust ", 10000)
+		short, long := strings.NewReader(shortText), strings.NewReader(longText)
+
+		shortAllocs := testing.AllocsPerRun(10, func() {
+			short.Reset(shortText)
+			_, _ = tc.AddFromReader(short)
+		})
+		longAllocs := testing.AllocsPerRun(10, func() {
+			long.Reset(longText)
+			_, _ = tc.AddFromReader(long)
+		})
+
+		assert.Equal(t, shortAllocs, longAllocs)
+	})
+}
+
+func BenchmarkAddTagNew(b *testing.B) {
+	tags := make([]string, 1<<20)
+	for i := range tags {
+		tags[i] = "tag-" + strconv.Itoa(i)
+	}
+	tc := tagcloud.New()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if i%len(tags) == 0 {
+			b.StopTimer()
+			tc = tagcloud.New()
+			b.StartTimer()
+		}
+		tc.AddTag(tags[i%len(tags)])
+	}
+}
+
+func BenchmarkAddTagExisting(b *testing.B) {
+	for name, options := range map[string][]tagcloud.Option{
+		"plain":      nil,
+		"lower case": {tagcloud.WithCaseInsensitive()},
+		"stop words": {tagcloud.WithStopWords("the", "a", "an")},
+	} {
+		b.Run(name, func(b *testing.B) {
+			tc := tagcloud.New(options...)
+			tc.AddTag("go")
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				tc.AddTag("go")
+			}
+		})
+	}
+}
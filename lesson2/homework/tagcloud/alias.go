@@ -0,0 +1,114 @@
+package tagcloud
+
+import (
+	"errors"
+	"fmt"
+)
+
+// AliasOption changes how AddAlias treats existing counts
+type AliasOption int
+
+const (
+	// FoldExisting moves existing counts of the alias to the canonical tag
+	FoldExisting AliasOption = iota + 1
+)
+
+// ErrAliasCycle is returned by AddAlias if the canonical tag resolves back to the alias
+var ErrAliasCycle = errors.New("alias cycle")
+
+// AddAlias makes the alias count as the canonical tag, so TopN shows only canonical tags.
+// aliases may form chains: with a→b and b→c both a and b count as c.
+// an alias which already has counts is an error unless FoldExisting is passed
+func (cloud *TagCloud) AddAlias(alias, canonical string, options ...AliasOption) error {
+	alias, canonical = cloud.clean(alias), cloud.clean(canonical)
+	if alias == "" || canonical == "" {
+		return fmt.Errorf("%w in alias %q -> %q", ErrEmptyTag, alias, canonical)
+	}
+	fold := false
+	for _, option := range options {
+		fold = fold || option == FoldExisting
+	}
+
+	cloud.lock()
+	defer cloud.unlock()
+	cloud.aliasLock()
+	defer cloud.aliasUnlock()
+	target := canonical
+	for {
+		if target == alias {
+			return fmt.Errorf("%w: %q already resolves to %q", ErrAliasCycle, canonical, alias)
+		}
+		next, ok := cloud.aliases[target]
+		if !ok {
+			break
+		}
+		target = next
+	}
+	if count, ok := cloud.tags.get(alias); ok {
+		if !fold {
+			return fmt.Errorf("alias %q already has %d occurrences, pass FoldExisting to move them to %q", alias, count, target)
+		}
+		weight := cloud.extraWeights[alias]
+		cloud.setCount(alias, 0)
+		cloud.setCount(target, cloud.tags.Count(target)+count)
+		if weight != 0 {
+			cloud.addExtraWeight(target, weight)
+		}
+	}
+	if cloud.aliases == nil {
+		cloud.aliases = map[string]string{}
+	}
+	cloud.aliases[alias] = canonical
+	return nil
+}
+
+// ResolveAlias returns the canonical tag the tag is counted as, that's the tag itself if it isn't an alias
+func (cloud *TagCloud) ResolveAlias(tag string) string {
+	return cloud.normalize(tag)
+}
+
+// resolve follows the alias chain of a cleaned tag
+func (cloud *TagCloud) resolve(tag string) string {
+	if cloud.concurrent {
+		cloud.aliasMu.RLock()
+		defer cloud.aliasMu.RUnlock()
+	}
+	return cloud.resolveLocked(tag)
+}
+
+func (cloud *TagCloud) resolveLocked(tag string) string {
+	for {
+		canonical, ok := cloud.aliases[tag]
+		if !ok {
+			return tag
+		}
+		tag = canonical
+	}
+}
+
+func (cloud *TagCloud) aliasLock() {
+	if cloud.concurrent {
+		cloud.aliasMu.Lock()
+	}
+}
+
+func (cloud *TagCloud) aliasUnlock() {
+	if cloud.concurrent {
+		cloud.aliasMu.Unlock()
+	}
+}
+
+func (cloud *TagCloud) copyAliases() map[string]string {
+	if cloud.concurrent {
+		cloud.aliasMu.RLock()
+		defer cloud.aliasMu.RUnlock()
+	}
+	if len(cloud.aliases) == 0 {
+		return nil
+	}
+	aliases := make(map[string]string, len(cloud.aliases))
+	for alias, canonical := range cloud.aliases {
+		aliases[alias] = canonical
+	}
+	return aliases
+}
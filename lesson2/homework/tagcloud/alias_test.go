@@ -0,0 +1,107 @@
+package tagcloud_test
+
+import (
+	"lecture02_homework/tagcloud"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddAlias(t *testing.T) {
+	t.Run("ok, alias counts as canonical tag", func(t *testing.T) {
+		tc := tagcloud.New()
+		assert.NoError(t, tc.AddAlias("golang", "go"))
+		tc.AddTags("golang", "go", " golang ")
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "go", OccurrenceCount: 3}}, tc.TopN(10))
+		assert.Equal(t, 3, tc.Count("golang"))
+		assert.Equal(t, "go", tc.ResolveAlias("golang"))
+		assert.Equal(t, "rust", tc.ResolveAlias("rust"))
+
+		tc.RemoveTag("golang")
+		assert.Equal(t, 2, tc.Count("go"))
+	})
+
+	t.Run("ok, chains", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithCaseInsensitive())
+		assert.NoError(t, tc.AddAlias("a", "b"))
+		assert.NoError(t, tc.AddAlias("B", "c"))
+		tc.AddTags("a", "b", "c")
+
+		assert.Equal(t, "c", tc.ResolveAlias("A"))
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "c", OccurrenceCount: 3}}, tc.TopN(10))
+	})
+
+	t.Run("ok, fold existing counts", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("golang", 2)
+		tc.AddTagN("go", 3)
+		assert.NoError(t, tc.AddAlias("go", "lang-go", tagcloud.FoldExisting))
+		assert.NoError(t, tc.AddWeighted("golang", 5))
+
+		assert.NoError(t, tc.AddAlias("golang", "go", tagcloud.FoldExisting))
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "lang-go", OccurrenceCount: 6}}, tc.TopN(10))
+		assert.Equal(t, 10.0, tc.Weight("golang"))
+		assert.Equal(t, 6, tc.Total())
+	})
+
+	t.Run("ok, clone keeps aliases", func(t *testing.T) {
+		tc := tagcloud.New()
+		assert.NoError(t, tc.AddAlias("golang", "go"))
+		clone := tc.Clone()
+		assert.NoError(t, clone.AddAlias("rustlang", "rust"))
+
+		clone.AddTags("golang", "rustlang")
+		tc.AddTag("rustlang")
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "go", OccurrenceCount: 1}, {Tag: "rust", OccurrenceCount: 1}}, clone.TopN(10))
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "rustlang", OccurrenceCount: 1}}, tc.TopN(10))
+	})
+
+	t.Run("ok, concurrent aliases", func(t *testing.T) {
+		tc := tagcloud.NewConcurrent()
+		var wg sync.WaitGroup
+		for w := 0; w < 10; w++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				tc.AddTag("golang")
+				tc.Count("golang")
+			}()
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, tc.AddAlias("golang", "go", tagcloud.FoldExisting))
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "go", OccurrenceCount: 10}}, tc.TopN(10))
+	})
+
+	t.Run("error, cycles", func(t *testing.T) {
+		tc := tagcloud.New()
+		assert.NoError(t, tc.AddAlias("a", "b"))
+		assert.NoError(t, tc.AddAlias("b", "c"))
+
+		assert.ErrorIs(t, tc.AddAlias("b", "a"), tagcloud.ErrAliasCycle)
+		assert.ErrorIs(t, tc.AddAlias("c", "a"), tagcloud.ErrAliasCycle)
+		assert.ErrorIs(t, tc.AddAlias("d", "d"), tagcloud.ErrAliasCycle)
+		assert.Equal(t, "c", tc.ResolveAlias("a"))
+	})
+
+	t.Run("error, alias with counts", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag("golang")
+
+		err := tc.AddAlias("golang", "go")
+
+		assert.EqualError(t, err, `alias "golang" already has 1 occurrences, pass FoldExisting to move them to "go"`)
+		assert.Equal(t, "golang", tc.ResolveAlias("golang"))
+	})
+
+	t.Run("error, empty tags", func(t *testing.T) {
+		assert.ErrorIs(t, tagcloud.New().AddAlias(" ", "go"), tagcloud.ErrEmptyTag)
+	})
+}
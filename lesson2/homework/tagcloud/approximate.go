@@ -0,0 +1,158 @@
+package tagcloud
+
+import (
+	"fmt"
+	"hash/maphash"
+	"math"
+	"math/bits"
+	"slices"
+)
+
+// DefaultApproximateTopK is the number of top candidates tracked by an approximate cloud,
+// TopN of the approximate cloud never returns more tags
+const DefaultApproximateTopK = 100
+
+// hllPrecision gives 4096 HyperLogLog registers for Len with standard error about 1.6%
+const hllPrecision = 12
+
+// ApproximateTagCloud counts tags in a count-min sketch of fixed size instead of a map, so memory
+// doesn't grow with the number of distinct tags.
+// with probability 1-delta an estimated count exceeds the real one by at most epsilon*Total,
+// estimates are never less than real counts unless tags are removed.
+// only DefaultApproximateTopK most frequent candidates are remembered by name for TopN.
+// the cloud isn't safe for concurrent use
+type ApproximateTagCloud struct {
+	config *TagCloud
+	seed   maphash.Seed
+	width  uint64
+	// rows of counters, row i of tag hash h uses cell (h1 + i*h2) mod width
+	rows  [][]int
+	total int
+	top   *candidateHeap
+	// registers of HyperLogLog counting distinct tags
+	registers [1 << hllPrecision]uint8
+}
+
+// NewApproximate creates an approximate cloud with the given error bounds,
+// both epsilon and delta must be in (0, 1)
+func NewApproximate(epsilon, delta float64, options ...Option) (*ApproximateTagCloud, error) {
+	if !(epsilon > 0 && epsilon < 1) || !(delta > 0 && delta < 1) {
+		return nil, fmt.Errorf("epsilon and delta must be in (0, 1), got %v and %v", epsilon, delta)
+	}
+	width := uint64(math.Ceil(math.E / epsilon))
+	depth := int(math.Ceil(math.Log(1 / delta)))
+	cloud := &ApproximateTagCloud{
+		config: newTagCloud(options),
+		seed:   maphash.MakeSeed(),
+		width:  width,
+		rows:   make([][]int, depth),
+		top:    newCandidateHeap(DefaultApproximateTopK),
+	}
+	for i := range cloud.rows {
+		cloud.rows[i] = make([]int, width)
+	}
+	return cloud, nil
+}
+
+// cells returns the counter index in every row for the tag
+func (cloud *ApproximateTagCloud) cells(tag string, fn func(row []int, cell uint64)) {
+	cloud.cellsOfHash(maphash.String(cloud.seed, tag), fn)
+}
+
+func (cloud *ApproximateTagCloud) cellsOfHash(hash uint64, fn func(row []int, cell uint64)) {
+	h1, h2 := hash&math.MaxUint32, hash>>32|1
+	for i, row := range cloud.rows {
+		fn(row, (h1+uint64(i)*h2)%cloud.width)
+	}
+}
+
+// EstimatedCount returns estimated occurrence count of the tag, see ApproximateTagCloud for error bounds
+func (cloud *ApproximateTagCloud) EstimatedCount(tag string) int {
+	return cloud.estimate(cloud.config.normalize(tag))
+}
+
+func (cloud *ApproximateTagCloud) estimate(tag string) int {
+	estimate := math.MaxInt
+	cloud.cells(tag, func(row []int, cell uint64) {
+		estimate = min(estimate, row[cell])
+	})
+	return estimate
+}
+
+// AddTag increases estimated occurrence count of the tag
+func (cloud *ApproximateTagCloud) AddTag(tag string) {
+	cloud.AddTagN(tag, 1)
+}
+
+// AddTagN works the same as calling AddTag n times, non-positive n does nothing
+func (cloud *ApproximateTagCloud) AddTagN(tag string, n int) {
+	tag, ok := cloud.config.key(tag)
+	if n <= 0 || !ok {
+		return
+	}
+	cloud.add(tag, n)
+}
+
+// RemoveTag decreases estimated occurrence count, a tag with zero estimate does nothing
+func (cloud *ApproximateTagCloud) RemoveTag(tag string) {
+	tag = cloud.config.normalize(tag)
+	if cloud.estimate(tag) > 0 {
+		cloud.add(tag, -1)
+	}
+}
+
+func (cloud *ApproximateTagCloud) add(tag string, n int) {
+	cloud.total += n
+	hash := maphash.String(cloud.seed, tag)
+	cloud.cellsOfHash(hash, func(row []int, cell uint64) {
+		row[cell] = max(0, row[cell]+n)
+	})
+	register := hash >> (64 - hllPrecision)
+	rank := uint8(bits.LeadingZeros64(hash<<hllPrecision|1<<(hllPrecision-1))) + 1
+	cloud.registers[register] = max(cloud.registers[register], rank)
+	cloud.top.Update(tag, cloud.estimate(tag))
+}
+
+// Count is the same as EstimatedCount
+func (cloud *ApproximateTagCloud) Count(tag string) int {
+	return cloud.EstimatedCount(tag)
+}
+
+// Contains reports whether the estimated count is positive, so it may be true for an absent tag
+func (cloud *ApproximateTagCloud) Contains(tag string) bool {
+	return cloud.EstimatedCount(tag) > 0
+}
+
+// Len estimates the number of distinct tags ever added using HyperLogLog with standard error about 1.6%,
+// removed tags are still counted
+func (cloud *ApproximateTagCloud) Len() int {
+	m := float64(len(cloud.registers))
+	sum, empty := 0.0, 0
+	for _, register := range cloud.registers {
+		sum += math.Ldexp(1, -int(register))
+		if register == 0 {
+			empty++
+		}
+	}
+	estimate := 0.7213 / (1 + 1.079/m) * m * m / sum
+	if estimate <= 2.5*m && empty > 0 {
+		// linear counting is more precise for small cardinalities
+		estimate = m * math.Log(m/float64(empty))
+	}
+	return int(math.Round(estimate))
+}
+
+// Total returns the exact sum of all occurrence counts
+func (cloud *ApproximateTagCloud) Total() int {
+	return cloud.total
+}
+
+// TopN returns at most DefaultApproximateTopK tags with the greatest estimated counts in TopN order
+func (cloud *ApproximateTagCloud) TopN(n int) []TagStat {
+	stats := append([]TagStat{}, cloud.top.stats...)
+	slices.SortFunc(stats, compareTagStats)
+	if len(stats) < n {
+		n = len(stats)
+	}
+	return stats[:max(n, 0)]
+}
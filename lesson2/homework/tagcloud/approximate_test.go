@@ -0,0 +1,71 @@
+package tagcloud_test
+
+import (
+	"fmt"
+	"lecture02_homework/tagcloud"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApproximateTagCloud(t *testing.T) {
+	t.Run("ok, zipfian stream", func(t *testing.T) {
+		epsilon := 0.0002
+		approximate, err := tagcloud.NewApproximate(epsilon, 0.01)
+		assert.NoError(t, err)
+		exact := tagcloud.New()
+		zipf := rand.NewZipf(rand.New(rand.NewSource(5)), 1.1, 1, 100000)
+		for i := 0; i < 1000000; i++ {
+			tag := fmt.Sprintf("url-%d", zipf.Uint64())
+			approximate.AddTag(tag)
+			exact.AddTag(tag)
+		}
+
+		// the hash seed is random, so only guaranteed properties are checked exactly:
+		// estimates never go below real counts and the top holds frequent tags in some order
+		actual := approximate.TopN(10)
+		assert.Len(t, actual, 10)
+		exactTop := map[string]bool{}
+		for _, stat := range exact.TopN(20) {
+			exactTop[stat.Tag] = true
+		}
+		approximateTop := map[string]bool{}
+		for _, stat := range actual {
+			approximateTop[stat.Tag] = true
+			assert.True(t, exactTop[stat.Tag], "tag %s isn't in the exact top 20", stat.Tag)
+			assert.GreaterOrEqual(t, stat.OccurrenceCount, exact.Count(stat.Tag), "tag %s", stat.Tag)
+		}
+		for _, stat := range exact.TopN(3) {
+			assert.True(t, approximateTop[stat.Tag], "tag %s isn't in the approximate top 10", stat.Tag)
+		}
+		assert.Equal(t, exact.Total(), approximate.Total())
+		assert.InEpsilon(t, exact.Len(), approximate.Len(), 0.1)
+		assert.Len(t, approximate.TopN(1000), tagcloud.DefaultApproximateTopK)
+	})
+
+	t.Run("ok, small exact counts", func(t *testing.T) {
+		tc, err := tagcloud.NewApproximate(0.01, 0.01, tagcloud.WithCaseInsensitive())
+		assert.NoError(t, err)
+		tc.AddTagN("Go", 3)
+		tc.AddTag("rust")
+		tc.AddTagN("go", 2)
+		tc.RemoveTag("RUST")
+		tc.RemoveTag("missing")
+
+		assert.Equal(t, 5, tc.EstimatedCount("GO"))
+		assert.Equal(t, 5, tc.Count("go"))
+		assert.False(t, tc.Contains("rust"))
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "go", OccurrenceCount: 5}}, tc.TopN(10))
+		assert.Equal(t, 5, tc.Total())
+		assert.Equal(t, 2, tc.Len())
+		assert.Empty(t, tc.TopN(-1))
+	})
+
+	t.Run("error, invalid bounds", func(t *testing.T) {
+		for _, bounds := range [][2]float64{{0, 0.1}, {0.1, 0}, {1, 0.1}, {0.1, -1}} {
+			_, err := tagcloud.NewApproximate(bounds[0], bounds[1])
+			assert.Error(t, err, "%v", bounds)
+		}
+	})
+}
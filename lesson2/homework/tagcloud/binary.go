@@ -0,0 +1,269 @@
+package tagcloud
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"time"
+)
+
+// binaryMagic starts every saved cloud, binaryVersion is changed on every format change
+const (
+	binaryMagic   = "TGCL"
+	binaryVersion = 1
+	// binaryTimestampsVersion is written by clouds with WithTimestamps
+	binaryTimestampsVersion = 2
+	// binaryFlagsVersion is written by clouds with WithHighWaterMarks, the version byte is followed by flags
+	binaryFlagsVersion   = 3
+	binaryTimestampsFlag = 1
+	binaryPeaksFlag      = 2
+	// maxBinaryTag protects Load from huge allocations on corrupted lengths
+	maxBinaryTag = 1 << 20
+)
+
+// Save writes the cloud in a compact binary format: magic header, version byte, number of tags
+// and then every tag sorted by name as uvarint length, tag bytes and uvarint count.
+// a cloud with WithTimestamps writes version 2, where the count is followed by uvarint 1 and varint
+// unix nanoseconds of the first and the last addition, or by uvarint 0 if the tag has no times.
+// a cloud with WithHighWaterMarks writes version 3 with a flags byte of timestamps (1) and peaks (2),
+// every tag ends with uvarint peak and retired peaks are written as tags with zero count
+func (cloud *TagCloud) Save(w io.Writer) error {
+	cloud.rlock()
+	stats := make([]TagStatFull, 0, cloud.tags.Len())
+	for tag, count := range cloud.tags.All() {
+		times := cloud.seen[tag]
+		stats = append(stats, TagStatFull{TagStat: TagStat{Tag: tag, OccurrenceCount: count}, FirstSeen: times.first, LastSeen: times.last, Peak: cloud.peaks[tag]})
+	}
+	timestamps, peaks := cloud.timestamps, cloud.highWaterMarks
+	if peaks {
+		stats = append(stats, cloud.retiredStats()...)
+	}
+	cloud.runlock()
+	slices.SortFunc(stats, func(a, b TagStatFull) int {
+		return cmp.Compare(a.Tag, b.Tag)
+	})
+
+	writer := bufio.NewWriter(w)
+	writer.WriteString(binaryMagic)
+	switch {
+	case peaks && timestamps:
+		writer.Write([]byte{binaryFlagsVersion, binaryTimestampsFlag | binaryPeaksFlag})
+	case peaks:
+		writer.Write([]byte{binaryFlagsVersion, binaryPeaksFlag})
+	case timestamps:
+		writer.WriteByte(binaryTimestampsVersion)
+	default:
+		writer.WriteByte(binaryVersion)
+	}
+	var buf [binary.MaxVarintLen64]byte
+	writeUvarint := func(value uint64) {
+		writer.Write(buf[:binary.PutUvarint(buf[:], value)])
+	}
+	writeVarint := func(value int64) {
+		writer.Write(buf[:binary.PutVarint(buf[:], value)])
+	}
+	writeUvarint(uint64(len(stats)))
+	for _, stat := range stats {
+		writeUvarint(uint64(len(stat.Tag)))
+		writer.WriteString(stat.Tag)
+		writeUvarint(uint64(stat.OccurrenceCount))
+		switch {
+		case !timestamps:
+		case stat.FirstSeen.IsZero():
+			writeUvarint(0)
+		default:
+			writeUvarint(1)
+			writeVarint(stat.FirstSeen.UnixNano())
+			writeVarint(stat.LastSeen.UnixNano())
+		}
+		if peaks {
+			writeUvarint(uint64(stat.Peak))
+		}
+	}
+	return writer.Flush()
+}
+
+// Load reads a cloud written by Save.
+// unknown header or version, truncated data and invalid tags are reported as errors.
+// a cloud saved with timestamps is loaded with WithTimestamps using time.Now,
+// a cloud saved with peaks is loaded with WithHighWaterMarks
+func Load(r io.Reader) (*TagCloud, error) {
+	tags, seen, peaks, _, err := loadTags(r)
+	if err != nil {
+		return nil, err
+	}
+	var options []Option
+	if seen != nil {
+		options = append(options, WithTimestamps(nil))
+	}
+	if peaks != nil {
+		options = append(options, WithHighWaterMarks())
+	}
+	cloud := New(options...)
+	cloud.replaceTags(tags)
+	cloud.restoreSeen(seen)
+	cloud.restorePeaks(peaks)
+	return cloud, nil
+}
+
+// MarshalBinary encodes the cloud in the format written by Save
+func (cloud *TagCloud) MarshalBinary() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := cloud.Save(buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary replaces content of the cloud with tags encoded by MarshalBinary or Save,
+// a zero value TagCloud can be used as the target. timestamps are kept only by a cloud with WithTimestamps
+// and peaks only by a cloud with WithHighWaterMarks
+func (cloud *TagCloud) UnmarshalBinary(data []byte) error {
+	tags, seen, peaks, _, err := loadTags(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	cloud.lock()
+	defer cloud.unlock()
+	cloud.replaceTags(tags)
+	cloud.restoreSeen(seen)
+	cloud.restorePeaks(peaks)
+	return nil
+}
+
+// loadTags reads tags written by Save, their times, peaks and total count.
+// times are nil for a cloud saved without timestamps and peaks are nil for a cloud saved without peaks
+func loadTags(r io.Reader) (map[string]int, map[string]seenTimes, map[string]int, int, error) {
+	reader := bufio.NewReader(r)
+	header := make([]byte, len(binaryMagic)+1)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, nil, nil, 0, binaryError(err)
+	}
+	if !bytes.Equal(header[:len(binaryMagic)], []byte(binaryMagic)) {
+		return nil, nil, nil, 0, fmt.Errorf("not a saved tag cloud: unknown header %q", header[:len(binaryMagic)])
+	}
+	flags, err := binaryFlags(reader, header[len(binaryMagic)])
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	size, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, nil, nil, 0, binaryError(err)
+	}
+	tags := map[string]int{}
+	var seen map[string]seenTimes
+	if flags&binaryTimestampsFlag != 0 {
+		seen = map[string]seenTimes{}
+	}
+	var peaks map[string]int
+	if flags&binaryPeaksFlag != 0 {
+		peaks = map[string]int{}
+	}
+	total := 0
+	for i := uint64(0); i < size; i++ {
+		length, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return nil, nil, nil, 0, binaryError(err)
+		}
+		if length > maxBinaryTag {
+			return nil, nil, nil, 0, fmt.Errorf("tag %d is too long: %d bytes", i, length)
+		}
+		tag := make([]byte, length)
+		if _, err := io.ReadFull(reader, tag); err != nil {
+			return nil, nil, nil, 0, binaryError(err)
+		}
+		count, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return nil, nil, nil, 0, binaryError(err)
+		}
+		// only retired peaks are written with zero count
+		if count == 0 && peaks == nil || count > math.MaxInt || count > uint64(math.MaxInt-total) {
+			return nil, nil, nil, 0, fmt.Errorf("tag %q has invalid count %d", tag, count)
+		}
+		_, ok := tags[string(tag)]
+		if _, retired := peaks[string(tag)]; ok || retired {
+			return nil, nil, nil, 0, fmt.Errorf("duplicate tag %q", tag)
+		}
+		if count > 0 {
+			tags[string(tag)] = int(count)
+			total += int(count)
+		}
+		if seen != nil {
+			times, ok, err := loadTimes(reader)
+			if err != nil {
+				return nil, nil, nil, 0, err
+			}
+			if ok {
+				seen[string(tag)] = times
+			}
+		}
+		if peaks != nil {
+			peak, err := binary.ReadUvarint(reader)
+			if err != nil {
+				return nil, nil, nil, 0, binaryError(err)
+			}
+			if peak == 0 && count == 0 || peak > math.MaxInt {
+				return nil, nil, nil, 0, fmt.Errorf("tag %q has invalid peak %d", tag, peak)
+			}
+			peaks[string(tag)] = int(peak)
+		}
+	}
+	return tags, seen, peaks, total, nil
+}
+
+// binaryFlags returns the flags of the version, version 3 reads them after the version byte
+func binaryFlags(reader *bufio.Reader, version byte) (byte, error) {
+	switch version {
+	case binaryVersion:
+		return 0, nil
+	case binaryTimestampsVersion:
+		return binaryTimestampsFlag, nil
+	case binaryFlagsVersion:
+		flags, err := reader.ReadByte()
+		if err != nil {
+			return 0, binaryError(err)
+		}
+		if flags&^(binaryTimestampsFlag|binaryPeaksFlag) != 0 {
+			return 0, fmt.Errorf("unknown tag cloud format flags %#x", flags)
+		}
+		return flags, nil
+	}
+	return 0, fmt.Errorf("unsupported tag cloud format version %d", version)
+}
+
+// loadTimes reads the optional times of a tag written by Save
+func loadTimes(reader *bufio.Reader) (seenTimes, bool, error) {
+	present, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return seenTimes{}, false, binaryError(err)
+	}
+	if present == 0 {
+		return seenTimes{}, false, nil
+	}
+	if present != 1 {
+		return seenTimes{}, false, fmt.Errorf("invalid timestamps marker %d", present)
+	}
+	first, err := binary.ReadVarint(reader)
+	if err != nil {
+		return seenTimes{}, false, binaryError(err)
+	}
+	last, err := binary.ReadVarint(reader)
+	if err != nil {
+		return seenTimes{}, false, binaryError(err)
+	}
+	return seenTimes{first: time.Unix(0, first), last: time.Unix(0, last)}, true, nil
+}
+
+func binaryError(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("saved tag cloud is truncated: %w", io.ErrUnexpectedEOF)
+	}
+	return err
+}
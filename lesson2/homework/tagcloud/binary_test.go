@@ -0,0 +1,140 @@
+package tagcloud_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"lecture02_homework/tagcloud"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveLoad(t *testing.T) {
+	t.Run("ok, bytes buffer", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"go": 3, "large": 1 << 50, "тег": 1, "a b": 1})
+		buf := &bytes.Buffer{}
+
+		assert.NoError(t, tc.Save(buf))
+		restored, err := tagcloud.Load(buf)
+
+		assert.NoError(t, err)
+		assert.Equal(t, tc.TopN(10), restored.TopN(10))
+		assert.Equal(t, tc.Total(), restored.Total())
+	})
+
+	t.Run("ok, temp file", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"go": 3, "large": 1 << 50, "тег": 1, "a b": 1})
+		path := filepath.Join(t.TempDir(), "cloud.bin")
+		file, err := os.Create(path)
+		assert.NoError(t, err)
+		assert.NoError(t, tc.Save(file))
+		assert.NoError(t, file.Close())
+
+		file, err = os.Open(path)
+		assert.NoError(t, err)
+		defer file.Close()
+		restored, err := tagcloud.Load(file)
+
+		assert.NoError(t, err)
+		assert.Equal(t, tc.TopN(10), restored.TopN(10))
+	})
+
+	t.Run("ok, empty cloud", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+
+		assert.NoError(t, tagcloud.New().Save(buf))
+		restored, err := tagcloud.Load(buf)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, restored.Len())
+	})
+
+	t.Run("ok, output is stable", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"go": 3, "large": 1 << 50, "тег": 1, "a b": 1})
+		first, second := &bytes.Buffer{}, &bytes.Buffer{}
+
+		assert.NoError(t, tc.Save(first))
+		assert.NoError(t, tc.Save(second))
+
+		assert.Equal(t, first.Bytes(), second.Bytes())
+	})
+
+	t.Run("error, truncated data", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"go": 3, "large": 1 << 50, "тег": 1, "a b": 1})
+		buf := &bytes.Buffer{}
+		assert.NoError(t, tc.Save(buf))
+		data := buf.Bytes()
+
+		for size := 0; size < len(data); size++ {
+			_, err := tagcloud.Load(bytes.NewReader(data[:size]))
+			assert.ErrorIs(t, err, io.ErrUnexpectedEOF, "size %d", size)
+		}
+	})
+
+	t.Run("error, invalid header", func(t *testing.T) {
+		_, err := tagcloud.Load(bytes.NewReader([]byte("JSON\x01\x00")))
+		assert.EqualError(t, err, `not a saved tag cloud: unknown header "JSON"`)
+
+		_, err = tagcloud.Load(bytes.NewReader([]byte("TGCL\x04\x00")))
+		assert.EqualError(t, err, "unsupported tag cloud format version 4")
+	})
+
+	t.Run("error, invalid tags", func(t *testing.T) {
+		_, err := tagcloud.Load(bytes.NewReader([]byte("TGCL\x01\x01\x01a\x00")))
+		assert.EqualError(t, err, `tag "a" has invalid count 0`)
+
+		_, err = tagcloud.Load(bytes.NewReader([]byte("TGCL\x01\x02\x01a\x01\x01a\x02")))
+		assert.EqualError(t, err, `duplicate tag "a"`)
+	})
+}
+
+func BenchmarkPersistence(b *testing.B) {
+	tc := tagcloud.New()
+	for i := 0; i < 1000000; i++ {
+		tc.AddTagN(fmt.Sprintf("tag-%d", i), i%1000+1)
+	}
+	binaryData := &bytes.Buffer{}
+	if err := tc.Save(binaryData); err != nil {
+		b.Fatal(err)
+	}
+	jsonData, err := json.Marshal(tc)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("binary save", func(b *testing.B) {
+		b.ReportMetric(float64(binaryData.Len()), "bytes")
+		for i := 0; i < b.N; i++ {
+			if err := tc.Save(io.Discard); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("json marshal", func(b *testing.B) {
+		b.ReportMetric(float64(len(jsonData)), "bytes")
+		for i := 0; i < b.N; i++ {
+			if _, err := json.Marshal(tc); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("binary load", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := tagcloud.Load(bytes.NewReader(binaryData.Bytes())); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("json unmarshal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var restored tagcloud.TagCloud
+			if err := json.Unmarshal(jsonData, &restored); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,27 @@
+package tagcloud
+
+import "cmp"
+
+// BottomN returns n least frequent tags ordered in ascending order by occurrence count,
+// tags with the same occurrence count are ordered lexicographically.
+// if n is greater than TagCloud size then all elements are returned, n <= 0 gives an empty slice
+func (cloud *TagCloud) BottomN(n int) []TagStat {
+	cloud.rlock()
+	defer cloud.runlock()
+	stats := cloud.selectStats(n, 0, func(a, b TagStat) int {
+		if c := cmp.Compare(a.OccurrenceCount, b.OccurrenceCount); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Tag, b.Tag)
+	})
+	cloud.fillErrorBounds(stats)
+	return stats
+}
+
+// TopNWithMin works as TopN, but tags with occurrence count less than minCount are skipped.
+// n <= 0 gives an empty slice
+func (cloud *TagCloud) TopNWithMin(n, minCount int) []TagStat {
+	cloud.rlock()
+	defer cloud.runlock()
+	return cloud.topStats(n, minCount)
+}
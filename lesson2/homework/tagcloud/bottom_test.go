@@ -0,0 +1,71 @@
+package tagcloud_test
+
+import (
+	"lecture02_homework/tagcloud"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBottomN(t *testing.T) {
+	t.Run("ok, ascending with ties by tag", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"a": 3, "b": 1, "c": 2, "d": 1, "e": 2})
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "b", OccurrenceCount: 1},
+			{Tag: "d", OccurrenceCount: 1},
+			{Tag: "c", OccurrenceCount: 2},
+		}, tc.BottomN(3))
+	})
+
+	t.Run("ok, n greater than cloud size", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"a": 3, "b": 1, "c": 2, "d": 1, "e": 2})
+		bottom := tc.BottomN(100)
+
+		assert.Len(t, bottom, 5)
+		assert.Equal(t, "a", bottom[4].Tag)
+	})
+
+	t.Run("ok, non-positive n", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"a": 3, "b": 1, "c": 2, "d": 1, "e": 2})
+
+		assert.NotNil(t, tc.BottomN(0))
+		assert.Empty(t, tc.BottomN(0))
+		assert.Empty(t, tc.BottomN(-1))
+		assert.NotNil(t, tagcloud.New().BottomN(10))
+	})
+}
+
+func TestTopNWithMin(t *testing.T) {
+	t.Run("ok, ties at the threshold are kept", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"a": 3, "b": 1, "c": 2, "d": 1, "e": 2})
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "a", OccurrenceCount: 3},
+			{Tag: "c", OccurrenceCount: 2},
+			{Tag: "e", OccurrenceCount: 2},
+		}, tc.TopNWithMin(10, 2))
+	})
+
+	t.Run("ok, threshold applied before n", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"a": 3, "b": 1, "c": 2, "d": 1, "e": 2})
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "a", OccurrenceCount: 3},
+			{Tag: "c", OccurrenceCount: 2},
+		}, tc.TopNWithMin(2, 2))
+	})
+
+	t.Run("ok, threshold eliminates all tags", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"a": 3, "b": 1, "c": 2, "d": 1, "e": 2})
+		top := tc.TopNWithMin(10, 4)
+
+		assert.NotNil(t, top)
+		assert.Empty(t, top)
+	})
+
+	t.Run("ok, non-positive n", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"a": 3, "b": 1, "c": 2, "d": 1, "e": 2})
+		top := tc.TopNWithMin(-3, 1)
+
+		assert.NotNil(t, top)
+		assert.Empty(t, top)
+	})
+}
@@ -0,0 +1,35 @@
+package tagcloud
+
+// WithMaxTags makes the cloud keep at most n distinct tags using the Space-Saving algorithm:
+// a new tag replaces the least frequent one and inherits its count, which becomes the tag's ErrorBound.
+// frequent tags survive eviction and their OccurrenceCount never underestimates the real count,
+// while the count minus ErrorBound never overestimates it.
+// non-positive n means no limit
+func WithMaxTags(n int) Option {
+	return func(cloud *TagCloud) {
+		cloud.maxTags = n
+		if n > 0 {
+			cloud.bounded = newCandidateHeap(0)
+		}
+	}
+}
+
+// evict removes the least frequent tag to give place to the new tag and returns the inherited count
+func (cloud *TagCloud) evict(tag string) int {
+	victim := cloud.bounded.stats[0]
+	cloud.setCount(victim.Tag, 0)
+	if cloud.errorBounds == nil {
+		cloud.errorBounds = map[string]int{}
+	}
+	cloud.errorBounds[tag] = victim.OccurrenceCount
+	return victim.OccurrenceCount
+}
+
+func (cloud *TagCloud) fillErrorBounds(stats []TagStat) {
+	if cloud.errorBounds == nil {
+		return
+	}
+	for i := range stats {
+		stats[i].ErrorBound = cloud.errorBounds[stats[i].Tag]
+	}
+}
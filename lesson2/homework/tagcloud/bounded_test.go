@@ -0,0 +1,88 @@
+package tagcloud_test
+
+import (
+	"fmt"
+	"lecture02_homework/tagcloud"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithMaxTags(t *testing.T) {
+	t.Run("ok, heavy hitters survive eviction", func(t *testing.T) {
+		bounded := tagcloud.New(tagcloud.WithMaxTags(50))
+		exact := tagcloud.New()
+		rnd := rand.New(rand.NewSource(9))
+		zipf := rand.NewZipf(rnd, 1.3, 1, 10000)
+		for i := 0; i < 200000; i++ {
+			tag := fmt.Sprintf("t%d", zipf.Uint64())
+			bounded.AddTag(tag)
+			exact.AddTag(tag)
+			if i%1000 == 0 {
+				assert.LessOrEqual(t, bounded.Len(), 50)
+			}
+		}
+
+		assert.Equal(t, 50, bounded.Len())
+		assert.Equal(t, exact.Total(), bounded.Total())
+		top := bounded.TopN(5)
+		for i, expected := range exact.TopN(5) {
+			assert.Equal(t, expected.Tag, top[i].Tag)
+			assert.GreaterOrEqual(t, top[i].OccurrenceCount, expected.OccurrenceCount)
+			assert.LessOrEqual(t, top[i].OccurrenceCount-top[i].ErrorBound, expected.OccurrenceCount)
+		}
+		// the long tail is evicted
+		tail := exact.BottomN(exact.Len() - 50)
+		evicted := 0
+		for _, stat := range tail {
+			if !bounded.Contains(stat.Tag) {
+				evicted++
+			}
+		}
+		assert.Greater(t, evicted, len(tail)*9/10)
+	})
+
+	t.Run("ok, new tag inherits minimum count", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithMaxTags(2))
+		tc.AddTagN("a", 5)
+		tc.AddTagN("b", 2)
+		tc.AddTag("c")
+
+		assert.False(t, tc.Contains("b"))
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "a", OccurrenceCount: 5},
+			{Tag: "c", OccurrenceCount: 3, ErrorBound: 2},
+		}, tc.TopN(10))
+		assert.Equal(t, tc.TopN(10), tc.TopNFrom(0, 10))
+		assert.Equal(t, 2, tc.BottomN(1)[0].ErrorBound)
+
+		tc.DeleteTag("c")
+		tc.AddTag("d")
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "a", OccurrenceCount: 5}, {Tag: "d", OccurrenceCount: 1}}, tc.TopN(10))
+	})
+
+	t.Run("ok, ordered cloud pages keep error bounds", func(t *testing.T) {
+		tc := tagcloud.NewOrdered(tagcloud.WithMaxTags(2))
+		tc.AddTags("a", "a", "b", "c", "c", "c")
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "c", OccurrenceCount: 4, ErrorBound: 1}}, tc.TopN(1))
+		assert.Equal(t, tc.TopN(10), tc.TopNFrom(0, 10))
+	})
+
+	t.Run("ok, clone and replaced tags stay bounded", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithMaxTags(2))
+		tc.AddTagN("a", 3)
+		tc.AddTagN("b", 2)
+		clone := tc.Clone()
+		clone.AddTag("c")
+		assert.Equal(t, 2, clone.Len())
+		assert.Equal(t, 2, clone.TopN(2)[1].ErrorBound)
+		assert.True(t, tc.Contains("b"))
+
+		assert.NoError(t, tc.UnmarshalJSON([]byte(`[{"tag":"x","count":1},{"tag":"y","count":3},{"tag":"z","count":2}]`)))
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "y", OccurrenceCount: 3}, {Tag: "z", OccurrenceCount: 2}}, tc.TopN(10))
+		tc.AddTag("w")
+		assert.Equal(t, 2, tc.Len())
+	})
+}
@@ -0,0 +1,59 @@
+package tagcloud
+
+import (
+	"fmt"
+	"math"
+)
+
+// Scale defines how occurrence counts are mapped to buckets
+type Scale int
+
+const (
+	// LinearScale splits the range between the smallest and the greatest count into equal parts
+	LinearScale Scale = iota
+	// LogScale does the same for logarithms of counts, which suits power-law distributions of tags
+	LogScale
+)
+
+// Buckets assigns every tag a bucket from 1 to n, e.g. to choose a font size when rendering the cloud.
+// the least frequent tags get bucket 1 and the most frequent get bucket n,
+// if all tags have the same count they get the middle bucket.
+// n <= 0 and unknown scale are errors
+func (cloud *TagCloud) Buckets(n int, scale Scale) (map[string]int, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("number of buckets must be positive, got %d", n)
+	}
+	value := func(count int) float64 {
+		return float64(count)
+	}
+	switch scale {
+	case LinearScale:
+	case LogScale:
+		value = func(count int) float64 {
+			return math.Log(float64(count))
+		}
+	default:
+		return nil, fmt.Errorf("unknown scale %d", scale)
+	}
+
+	cloud.rlock()
+	defer cloud.runlock()
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, count := range cloud.tags.All() {
+		low = math.Min(low, value(count))
+		high = math.Max(high, value(count))
+	}
+	buckets := make(map[string]int, cloud.tags.Len())
+	for tag, count := range cloud.tags.All() {
+		if high == low {
+			buckets[tag] = (n + 1) / 2
+			continue
+		}
+		bucket := 1 + int((value(count)-low)/(high-low)*float64(n))
+		if bucket > n {
+			bucket = n
+		}
+		buckets[tag] = bucket
+	}
+	return buckets, nil
+}
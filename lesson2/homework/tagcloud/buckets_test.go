@@ -0,0 +1,87 @@
+package tagcloud_test
+
+import (
+	"fmt"
+	"lecture02_homework/tagcloud"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuckets(t *testing.T) {
+	t.Run("ok, linear scale", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("a", 1)
+		tc.AddTagN("b", 5)
+		tc.AddTagN("c", 10)
+		tc.AddTagN("d", 7)
+
+		buckets, err := tc.Buckets(3, tagcloud.LinearScale)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int{"a": 1, "b": 2, "c": 3, "d": 3}, buckets)
+	})
+
+	t.Run("ok, log scale", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("a", 1)
+		tc.AddTagN("b", 10)
+		tc.AddTagN("c", 100)
+		tc.AddTagN("d", 1000)
+
+		buckets, err := tc.Buckets(4, tagcloud.LogScale)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int{"a": 1, "b": 2, "c": 3, "d": 4}, buckets)
+	})
+
+	t.Run("ok, equal counts collapse to the middle bucket", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTags("a", "b", "c")
+
+		for n, middle := range map[int]int{1: 1, 4: 2, 5: 3} {
+			for _, scale := range []tagcloud.Scale{tagcloud.LinearScale, tagcloud.LogScale} {
+				buckets, err := tc.Buckets(n, scale)
+
+				assert.NoError(t, err)
+				assert.Equal(t, map[string]int{"a": middle, "b": middle, "c": middle}, buckets)
+			}
+		}
+	})
+
+	t.Run("ok, log scale spreads power law better", func(t *testing.T) {
+		tc := tagcloud.New()
+		for i := 1; i <= 100; i++ {
+			tc.AddTagN(fmt.Sprintf("t%d", i), 10000/(i*i))
+		}
+		used := func(scale tagcloud.Scale) int {
+			buckets, err := tc.Buckets(5, scale)
+			assert.NoError(t, err)
+			distinct := map[int]bool{}
+			for _, bucket := range buckets {
+				distinct[bucket] = true
+			}
+			return len(distinct)
+		}
+
+		assert.Equal(t, 5, used(tagcloud.LogScale))
+		assert.Less(t, used(tagcloud.LinearScale), used(tagcloud.LogScale))
+	})
+
+	t.Run("ok, empty cloud", func(t *testing.T) {
+		buckets, err := tagcloud.New().Buckets(3, tagcloud.LogScale)
+
+		assert.NoError(t, err)
+		assert.Empty(t, buckets)
+	})
+
+	t.Run("error, invalid arguments", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag("a")
+
+		_, err := tc.Buckets(0, tagcloud.LinearScale)
+		assert.EqualError(t, err, "number of buckets must be positive, got 0")
+		_, err = tc.Buckets(3, tagcloud.Scale(7))
+		assert.EqualError(t, err, "unknown scale 7")
+	})
+}
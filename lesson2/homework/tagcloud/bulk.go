@@ -0,0 +1,106 @@
+package tagcloud
+
+import (
+	"context"
+	"slices"
+)
+
+// DefaultChunkSize is used by MergeIntoCtx and PruneCtx for non-positive chunk sizes
+const DefaultChunkSize = 1024
+
+// MergeIntoCtx works like MergeInto, but takes the lock of the receiver for at most chunkSize tags at a time,
+// so writers of a concurrent cloud aren't blocked for the whole merge of a big cloud.
+// pairs of other are merged after all of its tags.
+// cancellation of ctx is checked before every chunk, MergeIntoCtx returns ctx.Err() and leaves the receiver
+// partially merged: every tag is either merged with its weight and times or not merged at all,
+// and no pairs are merged unless all the tags are
+func (cloud *TagCloud) MergeIntoCtx(ctx context.Context, other *TagCloud, chunkSize int) error {
+	if other == nil {
+		return nil
+	}
+	data := other.snapshot()
+	tags := make([]string, 0, len(data.tags))
+	for tag := range data.tags {
+		tags = append(tags, tag)
+	}
+	err := cloud.chunked(ctx, len(tags), chunkSize, func(i int) {
+		cloud.mergeTag(data, tags[i])
+	})
+	if err != nil {
+		return err
+	}
+	pairs := make([]tagPair, 0, len(data.pairs))
+	for pair := range data.pairs {
+		pairs = append(pairs, pair)
+	}
+	return cloud.chunked(ctx, len(pairs), chunkSize, func(i int) {
+		cloud.mergePair(pairs[i], data.pairs[pairs[i]], data.stemmed)
+	})
+}
+
+// PruneCtx works like Prune, but looks for rare tags under the read lock and removes them under the lock
+// taking either lock for at most chunkSize tags at a time, so writers of a concurrent cloud interleave.
+// a tag which count reached minCount while the lock was released isn't removed.
+// cancellation of ctx is checked before every chunk, PruneCtx returns ctx.Err() along with the tags removed so far
+func (cloud *TagCloud) PruneCtx(ctx context.Context, minCount, chunkSize int) ([]TagStat, error) {
+	removed := []TagStat{}
+	candidates, err := cloud.pruneCandidates(ctx, minCount, chunkSize)
+	if err == nil {
+		err = cloud.chunked(ctx, len(candidates), chunkSize, func(i int) {
+			tag := candidates[i]
+			if count, ok := cloud.tags.get(tag); ok && count < minCount {
+				removed = append(removed, TagStat{Tag: tag, OccurrenceCount: count, ErrorBound: cloud.errorBounds[tag]})
+				cloud.setCount(tag, 0)
+			}
+		})
+	}
+	slices.SortFunc(removed, compareTagStats)
+	return removed, err
+}
+
+// pruneCandidates returns tags with count less than minCount releasing the read lock after every chunk.
+// changing a map while it's iterated is allowed, the changes are ordered by the lock,
+// tags changed meanwhile are checked again by PruneCtx
+func (cloud *TagCloud) pruneCandidates(ctx context.Context, minCount, chunkSize int) ([]string, error) {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var candidates []string
+	cloud.rlock()
+	i := 0
+	for tag, count := range cloud.tags.All() {
+		if count < minCount {
+			candidates = append(candidates, tag)
+		}
+		if i++; i%chunkSize == 0 {
+			cloud.runlock()
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			cloud.rlock()
+		}
+	}
+	cloud.runlock()
+	return candidates, nil
+}
+
+// chunked calls f for indexes from 0 to n taking the lock for every chunk of chunkSize indexes
+func (cloud *TagCloud) chunked(ctx context.Context, n, chunkSize int, f func(i int)) error {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	for start := 0; start < n; start += chunkSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		cloud.lock()
+		for i := start; i < min(start+chunkSize, n); i++ {
+			f(i)
+		}
+		cloud.unlock()
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package tagcloud_test
+
+import (
+	"context"
+	"fmt"
+	"lecture02_homework/tagcloud"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cancelAfter is a context cancelled after the given number of Err calls
+type cancelAfter struct {
+	context.Context
+	checks int
+}
+
+func (ctx *cancelAfter) Err() error {
+	if ctx.checks == 0 {
+		return context.Canceled
+	}
+	ctx.checks--
+	return nil
+}
+
+func bigCloud(n int) *tagcloud.TagCloud {
+	tc := tagcloud.New()
+	for i := 0; i < n; i++ {
+		tc.AddTagN(fmt.Sprintf("tag-%d", i), i%5+1)
+	}
+	return tc
+}
+
+// maxWriterWait runs op while another goroutine adds tags to the cloud and returns
+// the longest time a single AddTag waited
+func maxWriterWait(tc *tagcloud.TagCloud, op func()) (wait time.Duration) {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			start := time.Now()
+			tc.AddTag("writer")
+			wait = max(wait, time.Since(start))
+			time.Sleep(100 * time.Microsecond)
+		}
+	}()
+	op()
+	close(done)
+	wg.Wait()
+	return wait
+}
+
+func TestMergeIntoCtx(t *testing.T) {
+	t.Run("ok, same as MergeInto", func(t *testing.T) {
+		other := bigCloud(1000)
+		other.AddTagSet("tag-1", "tag-2")
+		require.NoError(t, other.AddWeighted("tag-3", 0.5))
+		want := tagcloud.New()
+		want.AddTag("tag-1")
+		got := want.Clone()
+
+		want.MergeInto(other)
+		require.NoError(t, got.MergeIntoCtx(context.Background(), other, 7))
+
+		assert.True(t, want.Equal(got), "%v", want.Diff(got))
+		assert.Equal(t, want.Related("tag-1", 10), got.Related("tag-1", 10))
+		assert.Equal(t, want.Weight("tag-3"), got.Weight("tag-3"))
+		assert.NoError(t, got.MergeIntoCtx(context.Background(), nil, 0))
+	})
+
+	t.Run("ok, writers interleave", func(t *testing.T) {
+		other := bigCloud(500000)
+		tc := tagcloud.NewConcurrent()
+		whole := maxWriterWait(tc, func() {
+			tc.MergeInto(other)
+		})
+		tc = tagcloud.NewConcurrent()
+
+		chunked := maxWriterWait(tc, func() {
+			assert.NoError(t, tc.MergeIntoCtx(context.Background(), other, 1000))
+		})
+
+		// on a single CPU the writer also waits for the scheduler, so waits are compared to MergeInto
+		assert.Less(t, chunked, whole/2, "writer waited %v, %v with MergeInto", chunked, whole)
+		assert.Equal(t, other.Total(), tc.Total()-tc.Count("writer"))
+	})
+
+	t.Run("error, cancelled mid-merge", func(t *testing.T) {
+		other := bigCloud(100)
+		other.AddTagSet("tag-1", "tag-2")
+		tc := tagcloud.NewConcurrent()
+
+		err := tc.MergeIntoCtx(&cancelAfter{Context: context.Background(), checks: 3}, other, 10)
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 30, tc.Len())
+		for _, stat := range tc.TopN(tc.Len()) {
+			assert.Equal(t, other.Count(stat.Tag), stat.OccurrenceCount)
+		}
+		assert.Empty(t, tc.Related("tag-1", 10))
+
+		err = tc.MergeIntoCtx(&cancelAfter{Context: context.Background()}, other, 10)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 30, tc.Len())
+	})
+}
+
+func TestPruneCtx(t *testing.T) {
+	t.Run("ok, same as Prune", func(t *testing.T) {
+		want := bigCloud(1000)
+		got := want.Clone()
+
+		removed, err := got.PruneCtx(context.Background(), 3, 7)
+
+		require.NoError(t, err)
+		assert.Equal(t, want.Prune(3), removed)
+		assert.True(t, want.Equal(got), "%v", want.Diff(got))
+	})
+
+	t.Run("ok, writers interleave", func(t *testing.T) {
+		other := bigCloud(500000)
+		tc := tagcloud.NewConcurrent()
+		tc.MergeInto(other)
+		whole := maxWriterWait(tc, func() {
+			tc.Prune(3)
+		})
+		tc = tagcloud.NewConcurrent()
+		tc.MergeInto(other)
+		// the writer tag mustn't be pruned
+		tc.AddTagN("writer", 3)
+
+		chunked := maxWriterWait(tc, func() {
+			removed, err := tc.PruneCtx(context.Background(), 3, 1000)
+			assert.NoError(t, err)
+			assert.Len(t, removed, 200000)
+		})
+
+		assert.Less(t, chunked, whole/2, "writer waited %v, %v with Prune", chunked, whole)
+		assert.Equal(t, 300001, tc.Len())
+	})
+
+	t.Run("error, cancelled mid-prune", func(t *testing.T) {
+		tc := bigCloud(100)
+
+		removed, err := tc.PruneCtx(&cancelAfter{Context: context.Background(), checks: 2}, 3, 10)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, []tagcloud.TagStat{}, removed)
+		assert.Equal(t, 100, tc.Len())
+
+		removed, err = tc.PruneCtx(&cancelAfter{Context: context.Background(), checks: 13}, 3, 10)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Len(t, removed, 20)
+		for _, stat := range removed {
+			assert.Less(t, stat.OccurrenceCount, 3)
+			assert.False(t, tc.Contains(stat.Tag))
+		}
+		assert.Equal(t, 80, tc.Len())
+	})
+}
@@ -0,0 +1,68 @@
+package tagcloud
+
+import "container/heap"
+
+// candidateHeap keeps the most frequent tags with the least frequent of them on top
+type candidateHeap struct {
+	stats []TagStat
+	// index is the position of a candidate in stats
+	index map[string]int
+	// limit is the number of kept tags, zero means no limit
+	limit int
+}
+
+func newCandidateHeap(limit int) *candidateHeap {
+	return &candidateHeap{index: map[string]int{}, limit: limit}
+}
+
+// Update sets the count of the tag and keeps it if it's among the top candidates,
+// a tag with zero count is removed
+func (h *candidateHeap) Update(tag string, count int) {
+	if i, ok := h.index[tag]; ok {
+		if count == 0 {
+			heap.Remove(h, i)
+			return
+		}
+		h.stats[i].OccurrenceCount = count
+		heap.Fix(h, i)
+		return
+	}
+	stat := TagStat{Tag: tag, OccurrenceCount: count}
+	switch {
+	case count == 0:
+	case h.limit == 0 || len(h.stats) < h.limit:
+		heap.Push(h, stat)
+	case compareTagStats(stat, h.stats[0]) < 0:
+		delete(h.index, h.stats[0].Tag)
+		h.stats[0] = stat
+		h.index[tag] = 0
+		heap.Fix(h, 0)
+	}
+}
+
+func (h *candidateHeap) Len() int {
+	return len(h.stats)
+}
+
+func (h *candidateHeap) Less(i, j int) bool {
+	return compareTagStats(h.stats[i], h.stats[j]) > 0
+}
+
+func (h *candidateHeap) Swap(i, j int) {
+	h.stats[i], h.stats[j] = h.stats[j], h.stats[i]
+	h.index[h.stats[i].Tag] = i
+	h.index[h.stats[j].Tag] = j
+}
+
+func (h *candidateHeap) Push(x any) {
+	stat := x.(TagStat)
+	h.index[stat.Tag] = len(h.stats)
+	h.stats = append(h.stats, stat)
+}
+
+func (h *candidateHeap) Pop() any {
+	last := h.stats[len(h.stats)-1]
+	h.stats = h.stats[:len(h.stats)-1]
+	delete(h.index, last.Tag)
+	return last
+}
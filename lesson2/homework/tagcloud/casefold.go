@@ -0,0 +1,46 @@
+package tagcloud
+
+import (
+	"slices"
+	"strings"
+)
+
+// CaseCollisions groups tags which would become the same tag with WithCaseInsensitive, i.e. have equal strings.ToLower.
+// keys are the lower case forms, only groups of two or more tags are returned,
+// tags of a group are in TopN order, so the first one is the most frequent spelling.
+// strings.ToLower has no language special cases: Turkish dotted "İ" collides with "I" and "i", dotless "ı" collides with neither
+func (cloud *TagCloud) CaseCollisions() map[string][]TagStat {
+	cloud.rlock()
+	groups := make(map[string][]TagStat)
+	for tag, count := range cloud.tags.All() {
+		folded := strings.ToLower(tag)
+		groups[folded] = append(groups[folded], TagStat{Tag: tag, OccurrenceCount: count, ErrorBound: cloud.errorBounds[tag]})
+	}
+	cloud.runlock()
+
+	for folded, group := range groups {
+		if len(group) < 2 {
+			delete(groups, folded)
+			continue
+		}
+		slices.SortFunc(group, compareTagStats)
+	}
+	return groups
+}
+
+// FoldCase returns a new cloud with every group of CaseCollisions merged into its most frequent spelling,
+// equally frequent spellings are resolved in TopN order. the receiver isn't changed
+func (cloud *TagCloud) FoldCase() *TagCloud {
+	canonical := make(map[string]string)
+	for _, group := range cloud.CaseCollisions() {
+		for _, stat := range group[1:] {
+			canonical[stat.Tag] = group[0].Tag
+		}
+	}
+	return cloud.MapTags(func(tag string) string {
+		if target, ok := canonical[tag]; ok {
+			return target
+		}
+		return tag
+	})
+}
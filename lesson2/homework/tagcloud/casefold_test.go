@@ -0,0 +1,87 @@
+package tagcloud_test
+
+import (
+	"lecture02_homework/tagcloud"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaseCollisions(t *testing.T) {
+	t.Run("ok, groups spellings in TopN order", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("go", 2)
+		tc.AddTagN("Go", 5)
+		tc.AddTagN("GO", 2)
+		tc.AddTag("rust")
+
+		assert.Equal(t, map[string][]tagcloud.TagStat{
+			"go": {{Tag: "Go", OccurrenceCount: 5}, {Tag: "GO", OccurrenceCount: 2}, {Tag: "go", OccurrenceCount: 2}},
+		}, tc.CaseCollisions())
+	})
+
+	t.Run("ok, turkish dotted and dotless i", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag("İstanbul")
+		tc.AddTag("Istanbul")
+		tc.AddTag("ıstanbul")
+		tc.AddTag("IŞIK")
+		tc.AddTag("ışık")
+
+		collisions := tc.CaseCollisions()
+
+		assert.Len(t, collisions, 1)
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "Istanbul", OccurrenceCount: 1},
+			{Tag: "İstanbul", OccurrenceCount: 1},
+		}, collisions["istanbul"])
+	})
+
+	t.Run("ok, no collisions", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag("go")
+		tc.AddTag("rust")
+
+		assert.Empty(t, tc.CaseCollisions())
+		assert.Empty(t, tagcloud.New().CaseCollisions())
+	})
+}
+
+func TestFoldCase(t *testing.T) {
+	t.Run("ok, merges into most frequent spelling", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("go", 2)
+		tc.AddTagN("Go", 5)
+		tc.AddTagN("GO", 2)
+		tc.AddTag("rust")
+
+		folded := tc.FoldCase()
+
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "Go", OccurrenceCount: 9},
+			{Tag: "rust", OccurrenceCount: 1},
+		}, folded.TopN(10))
+		assert.Equal(t, 10, folded.Total())
+		assert.Equal(t, 4, tc.Len())
+	})
+
+	t.Run("ok, equal counts resolved in TopN order", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag("go")
+		tc.AddTag("GO")
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "GO", OccurrenceCount: 2}}, tc.FoldCase().TopN(10))
+	})
+
+	t.Run("ok, turkish dotless i stays apart", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("İstanbul", 2)
+		tc.AddTag("istanbul")
+		tc.AddTag("ıstanbul")
+
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "İstanbul", OccurrenceCount: 3},
+			{Tag: "ıstanbul", OccurrenceCount: 1},
+		}, tc.FoldCase().TopN(10))
+	})
+}
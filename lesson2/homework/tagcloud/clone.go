@@ -0,0 +1,84 @@
+package tagcloud
+
+// Clone returns a deep copy of the cloud, changes of the copy don't affect the original.
+// a clone of a concurrent cloud is concurrent too
+func (cloud *TagCloud) Clone() *TagCloud {
+	cloud.rlock()
+	defer cloud.runlock()
+	clone := &TagCloud{
+		tags:         cloud.tags.clone(),
+		extraWeights: cloud.copyExtraWeights(),
+		pairs:        cloud.copyPairs(),
+		aliases:      cloud.copyAliases(),
+		added:        cloud.copyAdded(),
+		seen:         cloud.copySeen(),
+		surfaces:     cloud.copySurfaces(),
+		peaks:        copyCounts(cloud.peaks),
+		retiredPeaks: copyCounts(cloud.retiredPeaks),
+		seq:          cloud.seq,
+		concurrent:   cloud.concurrent,
+		cloudConfig:  cloud.cloudConfig,
+	}
+	clone.velocity, clone.velocityStart = copyCounts(cloud.velocity), cloud.velocityStart
+	if cloud.ordered != nil {
+		clone.ordered = newSkipList()
+	}
+	if cloud.recent != nil {
+		clone.recent = cloud.recent.copy()
+	}
+	if cloud.errorBounds != nil {
+		clone.errorBounds = make(map[string]int, len(cloud.errorBounds))
+		for tag, bound := range cloud.errorBounds {
+			clone.errorBounds[tag] = bound
+		}
+	}
+	clone.indexTags()
+	return clone
+}
+
+// Reset removes all tags, the cloud stays usable
+func (cloud *TagCloud) Reset() {
+	cloud.lock()
+	defer cloud.unlock()
+	cloud.replaceTags(map[string]int{})
+}
+
+// tagData is a copy of everything counted by a cloud
+type tagData struct {
+	tags         map[string]int
+	extraWeights map[string]float64
+	pairs        map[tagPair]int
+	seen         map[string]seenTimes
+	surfaces     map[string]map[string]int
+	// stemmed is set for copies of a cloud with a stemmer, their tags are already stems
+	stemmed bool
+}
+
+// snapshot returns a copy of counted data taken under the read lock
+func (cloud *TagCloud) snapshot() tagData {
+	cloud.rlock()
+	defer cloud.runlock()
+	return tagData{
+		tags:         cloud.copyTags(),
+		extraWeights: cloud.copyExtraWeights(),
+		pairs:        cloud.copyPairs(),
+		seen:         cloud.copySeen(),
+		surfaces:     cloud.copySurfaces(),
+		stemmed:      cloud.stemmer != nil,
+	}
+}
+
+func (cloud *TagCloud) copyTags() map[string]int {
+	return cloud.tags.toMap()
+}
+
+func (cloud *TagCloud) copyExtraWeights() map[string]float64 {
+	if len(cloud.extraWeights) == 0 {
+		return nil
+	}
+	weights := make(map[string]float64, len(cloud.extraWeights))
+	for tag, weight := range cloud.extraWeights {
+		weights[tag] = weight
+	}
+	return weights
+}
@@ -0,0 +1,47 @@
+package tagcloud_test
+
+import (
+	"lecture02_homework/tagcloud"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClone(t *testing.T) {
+	original := tagcloud.New()
+	original.AddTags("a", "b", "a")
+
+	clone := original.Clone()
+	assert.Equal(t, original.TopN(10), clone.TopN(10))
+	assert.Equal(t, original.Total(), clone.Total())
+
+	clone.AddTag("c")
+	clone.RemoveTag("a")
+	original.AddTag("b")
+	original.DeleteTag("a")
+
+	assert.Equal(t, []tagcloud.TagStat{{Tag: "b", OccurrenceCount: 2}}, original.TopN(10))
+	assert.Equal(t, 2, original.Total())
+	assert.Equal(t, []tagcloud.TagStat{
+		{Tag: "a", OccurrenceCount: 1},
+		{Tag: "b", OccurrenceCount: 1},
+		{Tag: "c", OccurrenceCount: 1},
+	}, clone.TopN(10))
+	assert.Equal(t, 3, clone.Total())
+}
+
+func TestReset(t *testing.T) {
+	tc := tagcloud.New()
+	tc.AddTags("a", "b", "a")
+	clone := tc.Clone()
+
+	tc.Reset()
+	assert.Empty(t, tc.TopN(10))
+	assert.Equal(t, 0, tc.Len())
+	assert.Equal(t, 0, tc.Total())
+
+	tc.AddTag("c")
+	assert.Equal(t, []tagcloud.TagStat{{Tag: "c", OccurrenceCount: 1}}, tc.TopN(10))
+	assert.Equal(t, 1, tc.Total())
+	assert.Equal(t, 3, clone.Total())
+}
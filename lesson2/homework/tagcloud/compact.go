@@ -0,0 +1,112 @@
+package tagcloud
+
+// minCompactPeak is the least peak number of tags for automatic compaction, rebuilding smaller maps isn't worth it
+const minCompactPeak = 1024
+
+// approximate sizes in bytes used by MemoryHint
+const (
+	// mapSlotBytes is a map slot of a string key and a word-sized value with the unused space of the map
+	mapSlotBytes = 32
+	// pairSlotBytes is a map slot of a tag pair key and a count
+	pairSlotBytes = 56
+	// recencyBytes is an expiration list element with its map slot
+	recencyBytes = 96
+	// skipNodeBytes is a node of the ordered list with its next pointers
+	skipNodeBytes = 80
+	// boundedBytes is an eviction heap entry with its index map slot
+	boundedBytes = 72
+	// seenBytes is a map slot of first and last addition times
+	seenBytes = 64
+)
+
+// WithAutoCompact makes the cloud call Compact when removals leave fewer tags than ratio of the peak number of tags
+// since the last compaction, e.g. 0.25 compacts a cloud which lost three quarters of its tags.
+// clouds which never held minCompactPeak tags aren't compacted, ratio outside (0, 1) disables compaction
+func WithAutoCompact(ratio float64) Option {
+	return func(cloud *TagCloud) {
+		cloud.autoCompact = ratio
+	}
+}
+
+// Compact rebuilds internal maps sized to the current number of tags.
+// go maps never shrink, so a cloud which held many tags keeps their memory after they are removed
+func (cloud *TagCloud) Compact() {
+	cloud.lock()
+	defer cloud.unlock()
+	cloud.compact()
+}
+
+// MemoryHint returns a rough estimate of bytes retained by the cloud, it includes the space
+// kept by maps for the peak number of tags since the last compaction
+func (cloud *TagCloud) MemoryHint() int {
+	cloud.rlock()
+	defer cloud.runlock()
+	size := max(cloud.peak, cloud.tags.Len()) * mapSlotBytes
+	for tag := range cloud.tags.All() {
+		size += len(tag)
+	}
+	size += (len(cloud.extraWeights) + len(cloud.errorBounds) + len(cloud.added) + len(cloud.peaks) + len(cloud.velocity)) * mapSlotBytes
+	for tag := range cloud.retiredPeaks {
+		size += mapSlotBytes + len(tag)
+	}
+	size += len(cloud.pairs) * pairSlotBytes
+	size += len(cloud.seen) * seenBytes
+	for _, counts := range cloud.namespaces {
+		size += len(counts) * mapSlotBytes
+	}
+	if cloud.recent != nil {
+		size += len(cloud.recent.elements) * recencyBytes
+	}
+	if cloud.ordered != nil {
+		size += cloud.tags.Len() * skipNodeBytes
+	}
+	if cloud.bounded != nil {
+		size += len(cloud.bounded.stats) * boundedBytes
+	}
+	return size
+}
+
+// compactIfNeeded compacts the cloud configured with WithAutoCompact after it lost enough tags
+func (cloud *TagCloud) compactIfNeeded() {
+	if cloud.autoCompact <= 0 || cloud.autoCompact >= 1 || cloud.peak < minCompactPeak {
+		return
+	}
+	if float64(cloud.tags.Len()) < cloud.autoCompact*float64(cloud.peak) {
+		cloud.compact()
+	}
+}
+
+func (cloud *TagCloud) compact() {
+	cloud.tags.counts.compact()
+	cloud.extraWeights = shrink(cloud.extraWeights)
+	cloud.errorBounds = shrink(cloud.errorBounds)
+	cloud.pairs = shrink(cloud.pairs)
+	cloud.added = shrink(cloud.added)
+	cloud.seen = shrink(cloud.seen)
+	cloud.peaks = shrink(cloud.peaks)
+	cloud.velocity = shrink(cloud.velocity)
+	cloud.namespaces = shrink(cloud.namespaces)
+	for namespace, counts := range cloud.namespaces {
+		cloud.namespaces[namespace] = shrink(counts)
+	}
+	if cloud.recent != nil {
+		cloud.recent.elements = shrink(cloud.recent.elements)
+	}
+	if cloud.bounded != nil {
+		cloud.bounded.stats = append(make([]TagStat, 0, len(cloud.bounded.stats)), cloud.bounded.stats...)
+		cloud.bounded.index = shrink(cloud.bounded.index)
+	}
+	cloud.peak = cloud.tags.Len()
+}
+
+// shrink copies the map into a new one sized to its length, nil stays nil
+func shrink[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return nil
+	}
+	result := make(map[K]V, len(m))
+	for key, value := range m {
+		result[key] = value
+	}
+	return result
+}
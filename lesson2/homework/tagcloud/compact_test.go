@@ -0,0 +1,123 @@
+package tagcloud_test
+
+import (
+	"lecture02_homework/tagcloud"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// growAndShrink adds tags 0..grow-1 and deletes all but the first keep of them
+func growAndShrink(tc *tagcloud.TagCloud, grow, keep int) {
+	for i := 0; i < grow; i++ {
+		tc.AddTag(strconv.Itoa(i))
+	}
+	for i := keep; i < grow; i++ {
+		tc.DeleteTag(strconv.Itoa(i))
+	}
+}
+
+func heapAlloc() uint64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
+
+func TestCompact(t *testing.T) {
+	t.Run("ok, releases memory of removed tags", func(t *testing.T) {
+		tc := tagcloud.New()
+		growAndShrink(tc, 1<<18, 1000)
+		before := heapAlloc()
+
+		tc.Compact()
+		after := heapAlloc()
+
+		assert.Less(t, after+4<<20, before, "heap before %d, after %d", before, after)
+		assert.Equal(t, 1000, tc.Len())
+		assert.Equal(t, 1000, tc.Total())
+		assert.Equal(t, 1, tc.Count("999"))
+		runtime.KeepAlive(tc)
+	})
+
+	t.Run("ok, auxiliary structures keep working", func(t *testing.T) {
+		tc := tagcloud.NewOrdered(tagcloud.WithMaxTags(3), tagcloud.WithNamespace(":"))
+		tc.AddTagN("lang:go", 3)
+		tc.AddTagN("lang:rust", 2)
+		tc.AddTag("db:postgres")
+		tc.AddWeighted("lang:go", 1.5)
+
+		tc.Compact()
+		tc.AddTagN("db:redis", 4)
+
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "db:redis", OccurrenceCount: 5, ErrorBound: 1},
+			{Tag: "lang:go", OccurrenceCount: 4},
+		}, tc.TopN(2))
+		assert.Equal(t, 3, tc.Len())
+		assert.Equal(t, []string{"db", "lang"}, tc.Namespaces())
+		assert.Equal(t, 6, tc.ByNamespace("lang").Total())
+	})
+
+	t.Run("ok, empty cloud", func(t *testing.T) {
+		tc := tagcloud.New()
+
+		tc.Compact()
+		tc.AddTag("go")
+
+		assert.Equal(t, 1, tc.Count("go"))
+	})
+}
+
+func TestWithAutoCompact(t *testing.T) {
+	t.Run("ok, compacts after removals", func(t *testing.T) {
+		compacted := tagcloud.New(tagcloud.WithAutoCompact(0.25))
+		plain := tagcloud.New()
+
+		growAndShrink(compacted, 10000, 100)
+		growAndShrink(plain, 10000, 100)
+
+		assert.Less(t, compacted.MemoryHint()*10, plain.MemoryHint())
+		assert.Equal(t, plain.TopN(100), compacted.TopN(100))
+	})
+
+	t.Run("ok, small cloud isn't compacted", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithAutoCompact(0.5))
+		plain := tagcloud.New()
+
+		growAndShrink(tc, 1000, 1)
+		growAndShrink(plain, 1000, 1)
+
+		assert.Equal(t, plain.MemoryHint(), tc.MemoryHint())
+	})
+
+	t.Run("ok, invalid ratio disables compaction", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithAutoCompact(1.5))
+		plain := tagcloud.New()
+
+		growAndShrink(tc, 5000, 10)
+		growAndShrink(plain, 5000, 10)
+
+		assert.Equal(t, plain.MemoryHint(), tc.MemoryHint())
+	})
+}
+
+func TestMemoryHint(t *testing.T) {
+	t.Run("ok, grows with tags and drops after compaction", func(t *testing.T) {
+		tc := tagcloud.New()
+		empty := tc.MemoryHint()
+		growAndShrink(tc, 2000, 2000)
+		full := tc.MemoryHint()
+		growAndShrink(tc, 2000, 10)
+		removed := tc.MemoryHint()
+
+		tc.Compact()
+
+		require.Less(t, empty, full)
+		assert.Less(t, removed, full)
+		assert.Less(t, tc.MemoryHint()*10, removed)
+	})
+}
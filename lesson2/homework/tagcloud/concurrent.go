@@ -0,0 +1,49 @@
+package tagcloud
+
+// NewConcurrent creates a TagCloud which is safe for concurrent use.
+// every method takes a mutex: read-only methods like TopN and Count share the read lock,
+// methods changing the cloud take the exclusive lock.
+// a cloud created by New has the same methods but no locking at all
+func NewConcurrent(options ...Option) *TagCloud {
+	cloud := newTagCloud(options)
+	cloud.concurrent = true
+	return cloud
+}
+
+// lock, unlock, rlock and runlock do nothing unless the cloud is concurrent.
+// public methods take the lock once and work through unexported helpers which never lock
+// lock and rlock also remove expired tags of a cloud with TTL, see WithTTL
+func (cloud *TagCloud) lock() {
+	if cloud.concurrent {
+		cloud.mu.Lock()
+	}
+	if cloud.ttl > 0 {
+		cloud.purge(cloud.now())
+	}
+}
+
+// unlock also calls threshold callbacks postponed while the cloud was locked, see OnThreshold,
+// and prunes and compacts the cloud configured with WithAutoPrune and WithAutoCompact
+func (cloud *TagCloud) unlock() {
+	cloud.pruneIfNeeded()
+	cloud.compactIfNeeded()
+	events := cloud.events
+	cloud.events = nil
+	if cloud.concurrent {
+		cloud.mu.Unlock()
+	}
+	fireEvents(events)
+}
+
+func (cloud *TagCloud) rlock() {
+	cloud.purgeBeforeRead()
+	if cloud.concurrent {
+		cloud.mu.RLock()
+	}
+}
+
+func (cloud *TagCloud) runlock() {
+	if cloud.concurrent {
+		cloud.mu.RUnlock()
+	}
+}
@@ -0,0 +1,106 @@
+package tagcloud_test
+
+import (
+	"fmt"
+	"lecture02_homework/tagcloud"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrentAddTag(t *testing.T) {
+	tc := tagcloud.NewConcurrent()
+	writers := 100
+	perWriter := 200
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	readerDone := make(chan struct{})
+	go func() {
+		defer close(readerDone)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			top := tc.TopN(5)
+			assert.LessOrEqual(t, len(top), 10)
+			tc.Count("t0")
+			tc.Total()
+		}
+	}()
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				tc.AddTag(fmt.Sprintf("t%d", (w+i)%10))
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(done)
+	<-readerDone
+
+	assert.Equal(t, writers*perWriter, tc.Total())
+	assert.Equal(t, 10, tc.Len())
+	for _, stat := range tc.TopN(10) {
+		assert.Equal(t, writers*perWriter/10, stat.OccurrenceCount)
+	}
+}
+
+func TestConcurrentMethods(t *testing.T) {
+	tc := tagcloud.NewConcurrent()
+	other := tagcloud.NewConcurrent()
+	other.AddTags("a", "b")
+
+	var wg sync.WaitGroup
+	for w := 0; w < 20; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tc.AddTags("a", "b", "c")
+			tc.AddTagN("d", 2)
+			tc.AddFromMap(map[string]int{"e": 1})
+			tc.RemoveTag("c")
+			tc.DeleteTag("missing")
+			tc.MergeInto(other)
+			other.MergeInto(tc.Clone())
+			other.Reset()
+			tc.Merge(other)
+			tc.BottomN(3)
+			tc.TopNWithMin(3, 2)
+			tc.Contains("a")
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 0, tc.Count("c"))
+	assert.Equal(t, 40, tc.Count("d"))
+	assert.Equal(t, 20, tc.Count("e"))
+}
+
+func TestConcurrentClone(t *testing.T) {
+	tc := tagcloud.NewConcurrent()
+	tc.AddTag("a")
+	clone := tc.Clone()
+
+	var wg sync.WaitGroup
+	for w := 0; w < 10; w++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			clone.AddTag("a")
+		}()
+		go func() {
+			defer wg.Done()
+			tc.Merge(clone)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 11, clone.Count("a"))
+	assert.Equal(t, 1, tc.Count("a"))
+}
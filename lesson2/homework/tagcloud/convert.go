@@ -0,0 +1,61 @@
+package tagcloud
+
+import "fmt"
+
+// ToMap returns a copy of counts taken under a single read lock
+func (cloud *TagCloud) ToMap() map[string]int {
+	return cloud.snapshot().tags
+}
+
+// ToSortedSlice returns all tags in TopN order, the same as TopN(Len()) but consistent for a concurrent cloud
+func (cloud *TagCloud) ToSortedSlice() []TagStat {
+	cloud.rlock()
+	defer cloud.runlock()
+	return cloud.topStats(cloud.tags.Len(), 0)
+}
+
+// FromMap creates a cloud with the counts configured by options, non-positive counts are ignored as in AddFromMap.
+// the counts are normalized into a preallocated map which replaces the tags at once,
+// so it's faster than adding tags one by one
+func FromMap(m map[string]int, options ...Option) *TagCloud {
+	cloud := newTagCloud(options)
+	tags := make(map[string]int, len(m))
+	for tag, count := range m {
+		key, ok := cloud.key(tag)
+		if count <= 0 || !ok {
+			continue
+		}
+		tags[key] += count
+	}
+	cloud.replaceTags(tags)
+	return cloud
+}
+
+// LoadSorted creates a cloud from stats in TopN order, e.g. a dump of ToSortedSlice. the order is verified
+// in a single pass instead of sorting, so tags and the sorted cache are built in linear time and TopN, TopNFrom
+// and SortedAll don't sort until the cloud is changed. tags are taken as is without normalization and only Tag
+// and OccurrenceCount of the stats are used. an entry out of order, a repeated tag or a non-positive count
+// is reported with the index of the entry
+func LoadSorted(entries []TagStat) (*TagCloud, error) {
+	tags := make(map[string]int, len(entries))
+	sorted := make([]TagStat, len(entries))
+	for i, entry := range entries {
+		stat := TagStat{Tag: entry.Tag, OccurrenceCount: entry.OccurrenceCount}
+		if stat.OccurrenceCount <= 0 {
+			return nil, fmt.Errorf("entry %d: tag %q has non-positive count %d", i, stat.Tag, stat.OccurrenceCount)
+		}
+		if _, ok := tags[stat.Tag]; ok {
+			return nil, fmt.Errorf("entry %d: duplicate tag %q", i, stat.Tag)
+		}
+		if i > 0 && compareTagStats(sorted[i-1], stat) > 0 {
+			return nil, fmt.Errorf("entry %d: tag %q with count %d is out of order after tag %q with count %d",
+				i, stat.Tag, stat.OccurrenceCount, sorted[i-1].Tag, sorted[i-1].OccurrenceCount)
+		}
+		tags[stat.Tag] = stat.OccurrenceCount
+		sorted[i] = stat
+	}
+	cloud := New()
+	cloud.replaceTags(tags)
+	cloud.sorted = sorted
+	return cloud, nil
+}
@@ -0,0 +1,164 @@
+package tagcloud_test
+
+import (
+	"fmt"
+	"lecture02_homework/tagcloud"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToMap(t *testing.T) {
+	t.Run("ok, copy of counts", func(t *testing.T) {
+		tc := tagcloud.NewConcurrent()
+		tc.AddTagN("go", 2)
+		tc.AddTag("rust")
+
+		m := tc.ToMap()
+		m["go"] = 100
+
+		assert.Equal(t, map[string]int{"go": 100, "rust": 1}, m)
+		assert.Equal(t, 2, tc.Count("go"))
+	})
+
+	t.Run("ok, empty cloud", func(t *testing.T) {
+		assert.Equal(t, map[string]int{}, tagcloud.New().ToMap())
+	})
+}
+
+func TestToSortedSlice(t *testing.T) {
+	t.Run("ok, same as TopN of all tags", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTags("c", "b", "a", "b")
+
+		assert.Equal(t, tc.TopN(tc.Len()), tc.ToSortedSlice())
+		assert.Empty(t, tagcloud.New().ToSortedSlice())
+	})
+}
+
+func TestFromMap(t *testing.T) {
+	t.Run("ok, counts", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"go": 3, "rust": 1, "zero": 0, "negative": -2})
+
+		assert.Equal(t, map[string]int{"go": 3, "rust": 1}, tc.ToMap())
+		assert.Equal(t, 4, tc.Total())
+	})
+
+	t.Run("ok, normalized by options", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"Go": 3, "go": 1, " GO ": 1, "the": 5}, tagcloud.WithCaseInsensitive(), tagcloud.WithStopWords("the"))
+
+		assert.Equal(t, map[string]int{"go": 5}, tc.ToMap())
+		assert.Equal(t, 5, tc.Total())
+	})
+
+	t.Run("ok, bounded cloud keeps most frequent tags", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"a": 1, "b": 5, "c": 3}, tagcloud.WithMaxTags(2))
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "b", OccurrenceCount: 5}, {Tag: "c", OccurrenceCount: 3}}, tc.TopN(10))
+	})
+
+	t.Run("ok, round trip", func(t *testing.T) {
+		roundTrip := func(m map[string]int) bool {
+			valid := make(map[string]int, len(m))
+			for tag, count := range m {
+				tag = strings.TrimSpace(tag)
+				if tag != "" && count > 0 {
+					valid[tag] = count
+				}
+			}
+			result := tagcloud.FromMap(valid).ToMap()
+			return assert.Equal(t, valid, result)
+		}
+
+		assert.NoError(t, quick.Check(roundTrip, nil))
+	})
+}
+
+func TestLoadSorted(t *testing.T) {
+	t.Run("ok, same as counted cloud", func(t *testing.T) {
+		counted := tagcloud.New()
+		counted.AddTagN("go", 3)
+		counted.AddTagN("rust", 2)
+		counted.AddTagN("c", 2)
+		counted.AddTag("zig")
+
+		tc, err := tagcloud.LoadSorted(counted.ToSortedSlice())
+
+		assert.NoError(t, err)
+		assert.True(t, tc.Equal(counted))
+		assert.Equal(t, counted.TopN(10), tc.TopN(10))
+		assert.Equal(t, counted.TopNFrom(1, 2), tc.TopNFrom(1, 2))
+		assert.Equal(t, counted.TopNWithMin(10, 2), tc.TopNWithMin(10, 2))
+		assert.Equal(t, 8, tc.Total())
+	})
+
+	t.Run("ok, changes after loading", func(t *testing.T) {
+		tc, err := tagcloud.LoadSorted([]tagcloud.TagStat{{Tag: "go", OccurrenceCount: 2}, {Tag: "rust", OccurrenceCount: 1}})
+		assert.NoError(t, err)
+
+		tc.AddTagN("rust", 2)
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "rust", OccurrenceCount: 3}, {Tag: "go", OccurrenceCount: 2}}, tc.TopN(10))
+	})
+
+	t.Run("ok, empty entries", func(t *testing.T) {
+		tc, err := tagcloud.LoadSorted(nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, tc.Len())
+		assert.Equal(t, []tagcloud.TagStat{}, tc.TopN(10))
+	})
+
+	t.Run("error, invalid entries", func(t *testing.T) {
+		for _, tt := range []struct {
+			entries []tagcloud.TagStat
+			err     string
+		}{
+			{
+				entries: []tagcloud.TagStat{{Tag: "go", OccurrenceCount: 1}, {Tag: "rust", OccurrenceCount: 2}},
+				err:     `entry 1: tag "rust" with count 2 is out of order after tag "go" with count 1`,
+			},
+			{
+				entries: []tagcloud.TagStat{{Tag: "rust", OccurrenceCount: 2}, {Tag: "go", OccurrenceCount: 2}},
+				err:     `entry 1: tag "go" with count 2 is out of order after tag "rust" with count 2`,
+			},
+			{
+				entries: []tagcloud.TagStat{{Tag: "go", OccurrenceCount: 3}, {Tag: "rust", OccurrenceCount: 2}, {Tag: "go", OccurrenceCount: 1}},
+				err:     `entry 2: duplicate tag "go"`,
+			},
+			{
+				entries: []tagcloud.TagStat{{Tag: "go", OccurrenceCount: 1}, {Tag: "rust", OccurrenceCount: 0}},
+				err:     `entry 1: tag "rust" has non-positive count 0`,
+			},
+		} {
+			tc, err := tagcloud.LoadSorted(tt.entries)
+
+			assert.Nil(t, tc)
+			assert.EqualError(t, err, tt.err)
+		}
+	})
+}
+
+func BenchmarkLoadSorted(b *testing.B) {
+	counted := tagcloud.New()
+	for i := 0; i < 1000000; i++ {
+		counted.AddTagN(fmt.Sprintf("tag-%d", i), i%1000+1)
+	}
+	entries, counts := counted.ToSortedSlice(), counted.ToMap()
+	b.Run("LoadSorted", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			tc, _ := tagcloud.LoadSorted(entries)
+			tc.TopNFrom(1000, 50)
+		}
+	})
+	b.Run("FromMap", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			tc := tagcloud.FromMap(counts)
+			tc.TopNFrom(1000, 50)
+		}
+	})
+}
@@ -0,0 +1,20 @@
+package tagcloud
+
+// Counter is the method set shared by all tag cloud implementations, so callers can switch between them
+// by changing only the constructor
+type Counter interface {
+	AddTag(tag string)
+	AddTagN(tag string, n int)
+	RemoveTag(tag string)
+	Count(tag string) int
+	Contains(tag string) bool
+	Len() int
+	Total() int
+	TopN(n int) []TagStat
+}
+
+var (
+	_ Counter = (*TagCloud)(nil)
+	_ Counter = (*ShardedTagCloud)(nil)
+	_ Counter = (*ApproximateTagCloud)(nil)
+)
@@ -0,0 +1,40 @@
+package tagcloud
+
+import (
+	"math"
+	"sort"
+)
+
+// TagsWithCount returns tags with exactly count occurrences sorted by tag, e.g. TagsWithCount(1) gives singletons
+func (cloud *TagCloud) TagsWithCount(count int) []string {
+	stats := cloud.TagsBetween(count, count)
+	tags := make([]string, len(stats))
+	for i, stat := range stats {
+		tags[i] = stat.Tag
+	}
+	return tags
+}
+
+// TagsAbove returns tags with at least minCount occurrences in TopN order
+func (cloud *TagCloud) TagsAbove(minCount int) []TagStat {
+	return cloud.TagsBetween(minCount, math.MaxInt)
+}
+
+// TagsBetween returns tags with count within [lo, hi] in TopN order.
+// it binary searches the sorted tags cached by TopNFrom, so repeated queries of an unchanged cloud don't sort again
+func (cloud *TagCloud) TagsBetween(lo, hi int) []TagStat {
+	cloud.rlock()
+	defer cloud.runlock()
+	if lo > hi {
+		return []TagStat{}
+	}
+	sorted := cloud.sortedStats()
+	// counts are descending, so tags within the range are contiguous
+	start := sort.Search(len(sorted), func(i int) bool {
+		return sorted[i].OccurrenceCount <= hi
+	})
+	end := sort.Search(len(sorted), func(i int) bool {
+		return sorted[i].OccurrenceCount < lo
+	})
+	return append([]TagStat{}, sorted[start:end]...)
+}
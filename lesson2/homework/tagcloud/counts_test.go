@@ -0,0 +1,75 @@
+package tagcloud_test
+
+import (
+	"lecture02_homework/tagcloud"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newCountsCloud(newCloud func(...tagcloud.Option) *tagcloud.TagCloud) *tagcloud.TagCloud {
+	tc := newCloud()
+	tc.AddTags("z", "y", "x")
+	tc.AddTagN("c", 2)
+	tc.AddTagN("b", 5)
+	tc.AddTagN("a", 5)
+	tc.AddTagN("go", 9)
+	return tc
+}
+
+func TestTagsWithCount(t *testing.T) {
+	t.Run("ok, singletons sorted by tag", func(t *testing.T) {
+		assert.Equal(t, []string{"x", "y", "z"}, newCountsCloud(tagcloud.New).TagsWithCount(1))
+		assert.Equal(t, []string{"a", "b"}, newCountsCloud(tagcloud.NewOrdered).TagsWithCount(5))
+	})
+
+	t.Run("ok, no tags with the count", func(t *testing.T) {
+		assert.Empty(t, newCountsCloud(tagcloud.New).TagsWithCount(3))
+		assert.Empty(t, tagcloud.New().TagsWithCount(1))
+	})
+}
+
+func TestTagsAbove(t *testing.T) {
+	t.Run("ok, boundary is inclusive", func(t *testing.T) {
+		expected := []tagcloud.TagStat{
+			{Tag: "go", OccurrenceCount: 9},
+			{Tag: "a", OccurrenceCount: 5},
+			{Tag: "b", OccurrenceCount: 5},
+		}
+
+		assert.Equal(t, expected, newCountsCloud(tagcloud.New).TagsAbove(5))
+		assert.Equal(t, expected, newCountsCloud(tagcloud.NewConcurrent).TagsAbove(5))
+	})
+
+	t.Run("ok, empty result", func(t *testing.T) {
+		assert.Empty(t, newCountsCloud(tagcloud.New).TagsAbove(10))
+	})
+}
+
+func TestTagsBetween(t *testing.T) {
+	t.Run("ok, both bounds are inclusive", func(t *testing.T) {
+		tc := newCountsCloud(tagcloud.New)
+
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "a", OccurrenceCount: 5},
+			{Tag: "b", OccurrenceCount: 5},
+			{Tag: "c", OccurrenceCount: 2},
+		}, tc.TagsBetween(2, 5))
+	})
+
+	t.Run("ok, reflects changes", func(t *testing.T) {
+		tc := newCountsCloud(tagcloud.New)
+		assert.Len(t, tc.TagsBetween(1, 1), 3)
+
+		tc.AddTag("x")
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "c", OccurrenceCount: 2}, {Tag: "x", OccurrenceCount: 2}}, tc.TagsBetween(2, 2))
+	})
+
+	t.Run("ok, empty range", func(t *testing.T) {
+		tc := newCountsCloud(tagcloud.New)
+
+		assert.Empty(t, tc.TagsBetween(6, 8))
+		assert.Empty(t, tc.TagsBetween(5, 2))
+	})
+}
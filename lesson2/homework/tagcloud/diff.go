@@ -0,0 +1,72 @@
+package tagcloud
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+)
+
+// TagDiff describes a tag whose count differs between two clouds,
+// zero OldCount means the tag is added and zero NewCount means it's removed
+type TagDiff struct {
+	Tag      string
+	OldCount int
+	NewCount int
+}
+
+// Delta returns the change of the count
+func (diff TagDiff) Delta() int {
+	return diff.NewCount - diff.OldCount
+}
+
+// Equal reports whether both clouds have the same tags with the same counts,
+// nil other is treated as an empty cloud
+func (cloud *TagCloud) Equal(other *TagCloud) bool {
+	if other == cloud {
+		return true
+	}
+	return maps.Equal(cloud.snapshot().tags, other.countsCopy())
+}
+
+// Diff returns changes of counts from the receiver to other ordered by absolute change descending
+// and then by tag, nil other is treated as an empty cloud
+func (cloud *TagCloud) Diff(other *TagCloud) []TagDiff {
+	if other == cloud {
+		return nil
+	}
+	oldTags := cloud.snapshot().tags
+	newTags := other.countsCopy()
+	var diffs []TagDiff
+	for tag, count := range oldTags {
+		if newTags[tag] != count {
+			diffs = append(diffs, TagDiff{Tag: tag, OldCount: count, NewCount: newTags[tag]})
+		}
+	}
+	for tag, count := range newTags {
+		if _, ok := oldTags[tag]; !ok {
+			diffs = append(diffs, TagDiff{Tag: tag, NewCount: count})
+		}
+	}
+	slices.SortFunc(diffs, func(a, b TagDiff) int {
+		if c := cmp.Compare(abs(b.Delta()), abs(a.Delta())); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Tag, b.Tag)
+	})
+	return diffs
+}
+
+// countsCopy returns a copy of counts, nil cloud has no counts
+func (cloud *TagCloud) countsCopy() map[string]int {
+	if cloud == nil {
+		return map[string]int{}
+	}
+	return cloud.snapshot().tags
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,76 @@
+package tagcloud_test
+
+import (
+	"lecture02_homework/tagcloud"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEqual(t *testing.T) {
+	t.Run("ok, identical clouds", func(t *testing.T) {
+		first := tagcloud.New()
+		first.AddTags("go", "go", "rust")
+		second := tagcloud.NewConcurrent()
+		second.AddTags("rust", "go", "go")
+
+		assert.True(t, first.Equal(second))
+		assert.True(t, first.Equal(first))
+		assert.Empty(t, first.Diff(second))
+	})
+
+	t.Run("ok, different counts", func(t *testing.T) {
+		first := tagcloud.New()
+		first.AddTags("go", "rust")
+		second := tagcloud.New()
+		second.AddTags("go", "go", "rust")
+
+		assert.False(t, first.Equal(second))
+	})
+
+	t.Run("ok, empty and nil clouds", func(t *testing.T) {
+		tc := tagcloud.New()
+		assert.True(t, tc.Equal(nil))
+		assert.True(t, tc.Equal(tagcloud.New()))
+		assert.Empty(t, tc.Diff(nil))
+
+		tc.AddTag("go")
+		assert.False(t, tc.Equal(nil))
+	})
+}
+
+func TestDiff(t *testing.T) {
+	t.Run("ok, single count change", func(t *testing.T) {
+		first := tagcloud.New()
+		first.AddTags("go", "rust")
+		second := first.Clone()
+		second.AddTagN("go", 2)
+
+		assert.Equal(t, []tagcloud.TagDiff{{Tag: "go", OldCount: 1, NewCount: 3}}, first.Diff(second))
+		assert.Equal(t, []tagcloud.TagDiff{{Tag: "go", OldCount: 3, NewCount: 1}}, second.Diff(first))
+	})
+
+	t.Run("ok, disjoint clouds", func(t *testing.T) {
+		first := tagcloud.New()
+		first.AddTagN("go", 2)
+		first.AddTag("c")
+		second := tagcloud.New()
+		second.AddTagN("rust", 2)
+
+		assert.Equal(t, []tagcloud.TagDiff{
+			{Tag: "go", OldCount: 2},
+			{Tag: "rust", NewCount: 2},
+			{Tag: "c", OldCount: 1},
+		}, first.Diff(second))
+	})
+
+	t.Run("ok, nil other removes everything", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag("go")
+
+		diffs := tc.Diff(nil)
+
+		assert.Equal(t, []tagcloud.TagDiff{{Tag: "go", OldCount: 1}}, diffs)
+		assert.Equal(t, -1, diffs[0].Delta())
+	})
+}
@@ -0,0 +1,67 @@
+package tagcloud
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+const (
+	// dotMinPenWidth and dotMaxPenWidth are widths of the lightest and the heaviest written edge
+	dotMinPenWidth = 1
+	dotMaxPenWidth = 5
+)
+
+// dotEscaper escapes a tag for a quoted Graphviz ID, line breaks become \n line breaks of labels
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// WriteDOT writes the cloud as an undirected Graphviz graph. every tag is a node with font size scaling
+// with its count as in RenderSVG, tags added in one set by AddTagSet are joined by an edge
+// if they were added together at least minEdgeWeight times, the weight and width of the edge scale with that number.
+// nodes are written in TopN order, edges from the heaviest, so the output is the same for the same cloud
+func (cloud *TagCloud) WriteDOT(w io.Writer, minEdgeWeight int) error {
+	tags, err := cloud.layout(RenderOptions{})
+	if err != nil {
+		return err
+	}
+	type edge struct {
+		tagPair
+		weight int
+	}
+	cloud.rlock()
+	edges := make([]edge, 0, len(cloud.pairs))
+	for pair, weight := range cloud.pairs {
+		if weight >= minEdgeWeight {
+			edges = append(edges, edge{tagPair: pair, weight: weight})
+		}
+	}
+	cloud.runlock()
+	slices.SortFunc(edges, func(a, b edge) int {
+		if c := cmp.Compare(b.weight, a.weight); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.first, b.first); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.second, b.second)
+	})
+
+	writer := bufio.NewWriter(w)
+	writer.WriteString("graph tags {\n")
+	for _, tag := range tags {
+		fmt.Fprintf(writer, "\t\"%s\" [fontsize=%.1f, tooltip=\"%d\"];\n", dotEscaper.Replace(tag.Tag), tag.FontSize, tag.OccurrenceCount)
+	}
+	for _, edge := range edges {
+		width := float64(dotMinPenWidth+dotMaxPenWidth) / 2
+		if high, low := edges[0].weight, edges[len(edges)-1].weight; high != low {
+			width = dotMinPenWidth + (dotMaxPenWidth-dotMinPenWidth)*float64(edge.weight-low)/float64(high-low)
+		}
+		fmt.Fprintf(writer, "\t\"%s\" -- \"%s\" [weight=%d, penwidth=%.1f, label=\"%d\"];\n",
+			dotEscaper.Replace(edge.first), dotEscaper.Replace(edge.second), edge.weight, width, edge.weight)
+	}
+	writer.WriteString("}\n")
+	return writer.Flush()
+}
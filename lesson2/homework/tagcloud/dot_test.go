@@ -0,0 +1,45 @@
+package tagcloud_test
+
+import (
+	"bytes"
+	"lecture02_homework/tagcloud"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteDOT(t *testing.T) {
+	newGraphCloud := func() *tagcloud.TagCloud {
+		tc := tagcloud.New()
+		tc.AddTagSet("go", "rust", `say "hi"`)
+		tc.AddTagSet("go", "rust", "c:\\path")
+		tc.AddTagSet("go", "two\nlines", `say "hi"`)
+		tc.AddTag("go")
+		return tc
+	}
+
+	t.Run("ok, overlapping sets", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		err := newGraphCloud().WriteDOT(out, 1)
+
+		assert.NoError(t, err)
+		assertGolden(t, "testdata/dot/sets.dot", out.Bytes())
+	})
+
+	t.Run("ok, light edges are skipped", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		err := newGraphCloud().WriteDOT(out, 2)
+
+		assert.NoError(t, err)
+		assertGolden(t, "testdata/dot/heavy.dot", out.Bytes())
+	})
+
+	t.Run("ok, empty cloud", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		assert.NoError(t, tagcloud.New().WriteDOT(out, 0))
+		assert.Equal(t, "graph tags {\n}\n", out.String())
+	})
+}
@@ -0,0 +1,10 @@
+package tagcloud
+
+// SetMaxIndexedTagLen lowers the tag length limit of WriteIndexed for tests, the returned function restores it
+func SetMaxIndexedTagLen(n uint64) func() {
+	previous := maxIndexedTagLen
+	maxIndexedTagLen = n
+	return func() {
+		maxIndexedTagLen = previous
+	}
+}
@@ -0,0 +1,50 @@
+package tagcloud
+
+import "fmt"
+
+// TopNWithFrequency works as TopN and fills Frequency of every stat with the tag's share in Total
+// taken at the time of the call
+func (cloud *TagCloud) TopNWithFrequency(n int) []TagStat {
+	cloud.rlock()
+	defer cloud.runlock()
+	stats := cloud.topStats(n, 0)
+	for i := range stats {
+		stats[i].Frequency = float64(stats[i].OccurrenceCount) / float64(cloud.tags.Total())
+	}
+	return stats
+}
+
+// TopPercent returns the shortest TopN prefix whose occurrences make up at least share p of Total,
+// e.g. 0.8 gives tags covering 80% of occurrences. tags with the same count as the last one are included too.
+// p must be within (0, 1]
+func (cloud *TagCloud) TopPercent(p float64) ([]TagStat, error) {
+	if !(p > 0 && p <= 1) {
+		return nil, fmt.Errorf("share %v is out of range (0, 1]", p)
+	}
+	cloud.rlock()
+	defer cloud.runlock()
+	sorted := cloud.sortedStats()
+	covered, end := 0, 0
+	for end < len(sorted) && float64(covered)/float64(cloud.tags.Total()) < p {
+		covered += sorted[end].OccurrenceCount
+		end++
+	}
+	for end > 0 && end < len(sorted) && sorted[end].OccurrenceCount == sorted[end-1].OccurrenceCount {
+		end++
+	}
+	return append([]TagStat{}, sorted[:end]...), nil
+}
+
+// CoverageOf returns the share of Total made up by top n tags, 0 for an empty cloud
+func (cloud *TagCloud) CoverageOf(n int) float64 {
+	cloud.rlock()
+	defer cloud.runlock()
+	if cloud.tags.Total() == 0 {
+		return 0
+	}
+	covered := 0
+	for _, stat := range cloud.topStats(n, 0) {
+		covered += stat.OccurrenceCount
+	}
+	return float64(covered) / float64(cloud.tags.Total())
+}
@@ -0,0 +1,126 @@
+package tagcloud_test
+
+import (
+	"fmt"
+	"lecture02_homework/tagcloud"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopNWithFrequency(t *testing.T) {
+	t.Run("ok, known distribution", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("a", 5)
+		tc.AddTagN("b", 3)
+		tc.AddTagN("c", 2)
+
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "a", OccurrenceCount: 5, Frequency: 0.5},
+			{Tag: "b", OccurrenceCount: 3, Frequency: 0.3},
+			{Tag: "c", OccurrenceCount: 2, Frequency: 0.2},
+		}, tc.TopNWithFrequency(10))
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "a", OccurrenceCount: 5, Frequency: 0.5}}, tc.TopNWithFrequency(1))
+		assert.Zero(t, tc.TopN(1)[0].Frequency)
+	})
+
+	t.Run("ok, after removals", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("a", 5)
+		tc.AddTagN("b", 3)
+		tc.AddTagN("c", 2)
+		tc.RemoveTag("a")
+		tc.DeleteTag("c")
+
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "a", OccurrenceCount: 4, Frequency: 4.0 / 7},
+			{Tag: "b", OccurrenceCount: 3, Frequency: 3.0 / 7},
+		}, tc.TopNWithFrequency(10))
+	})
+
+	t.Run("ok, frequencies sum up to one", func(t *testing.T) {
+		tc := tagcloud.New()
+		for i := 1; i <= 37; i++ {
+			tc.AddTagN(string(rune('a'+i%26))+string(rune('a'+i/26)), i)
+		}
+
+		sum := 0.0
+		for _, stat := range tc.TopNWithFrequency(tc.Len()) {
+			sum += stat.Frequency
+		}
+		assert.InDelta(t, 1, sum, 1e-9)
+	})
+
+	t.Run("ok, empty cloud", func(t *testing.T) {
+		top := tagcloud.New().TopNWithFrequency(10)
+
+		assert.NotNil(t, top)
+		assert.Empty(t, top)
+	})
+}
+
+// newZipfCloud has counts 60/n for tags t1..t5: 60, 30, 20, 15, 12
+func newZipfCloud() *tagcloud.TagCloud {
+	tc := tagcloud.New()
+	for i := 1; i <= 5; i++ {
+		tc.AddTagN(fmt.Sprint("t", i), 60/i)
+	}
+	return tc
+}
+
+func TestTopPercent(t *testing.T) {
+	t.Run("ok, zipf cutoff", func(t *testing.T) {
+		tc := newZipfCloud()
+		// cumulative shares: 60/137, 90/137, 110/137, 125/137, 137/137
+		for p, expected := range map[float64]int{0.01: 1, 0.43: 1, 0.44: 2, 0.65: 2, 0.66: 3, 0.8: 3, 0.81: 4, 0.92: 5, 1: 5} {
+			stats, err := tc.TopPercent(p)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.TopN(expected), stats, "share %v", p)
+		}
+	})
+
+	t.Run("ok, ties at the cutoff are included", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("a", 4)
+		tc.AddTagN("b", 2)
+		tc.AddTagN("c", 2)
+		tc.AddTagN("d", 2)
+
+		stats, err := tc.TopPercent(0.6)
+
+		assert.NoError(t, err)
+		assert.Equal(t, tc.TopN(4), stats)
+	})
+
+	t.Run("ok, empty cloud", func(t *testing.T) {
+		stats, err := tagcloud.New().TopPercent(0.5)
+
+		assert.NoError(t, err)
+		assert.Empty(t, stats)
+	})
+
+	t.Run("error, out of range", func(t *testing.T) {
+		for _, p := range []float64{0, -0.5, 1.01, math.NaN()} {
+			_, err := newZipfCloud().TopPercent(p)
+
+			assert.Error(t, err)
+		}
+	})
+}
+
+func TestCoverageOf(t *testing.T) {
+	t.Run("ok, zipf coverage", func(t *testing.T) {
+		tc := newZipfCloud()
+
+		assert.InDelta(t, 60.0/137, tc.CoverageOf(1), 1e-9)
+		assert.InDelta(t, 110.0/137, tc.CoverageOf(3), 1e-9)
+		assert.Equal(t, 1.0, tc.CoverageOf(10))
+		assert.Equal(t, 0.0, tc.CoverageOf(0))
+	})
+
+	t.Run("ok, empty cloud", func(t *testing.T) {
+		assert.Equal(t, 0.0, tagcloud.New().CoverageOf(5))
+	})
+}
@@ -0,0 +1,158 @@
+//go:build fuzz
+
+package tagcloud_test
+
+import (
+	"cmp"
+	"lecture02_homework/tagcloud"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// run with: go test -tags fuzz -run '^$' -fuzz FuzzFromMap ./tagcloud
+func FuzzFromMap(f *testing.F) {
+	f.Add("go rust go", uint8(3))
+	f.Add("", uint8(0))
+	f.Add("Машинное обучение 😊", uint8(200))
+	f.Add(" \t\xff\xfe ", uint8(1))
+
+	f.Fuzz(func(t *testing.T, text string, count uint8) {
+		m := map[string]int{}
+		for i, tag := range strings.Fields(text) {
+			m[tag] = int(count)%7 + i + 1
+		}
+
+		result := tagcloud.FromMap(m).ToMap()
+
+		if len(result) != len(m) {
+			t.Fatalf("FromMap(%v).ToMap() = %v", m, result)
+		}
+		for tag, count := range m {
+			if result[tag] != count {
+				t.Fatalf("FromMap(%v).ToMap() = %v", m, result)
+			}
+		}
+	})
+}
+
+// fuzzCloud is implemented by all clouds checked by FuzzInvariants
+type fuzzCloud interface {
+	AddTag(tag string)
+	AddTagN(tag string, n int)
+	RemoveTag(tag string)
+	Count(tag string) int
+	Len() int
+	Total() int
+	TopN(n int) []tagcloud.TagStat
+}
+
+// modelCloud is a trivial map-based cloud with the default normalization
+type modelCloud map[string]int
+
+func (m modelCloud) AddTagN(tag string, n int) {
+	if tag = strings.TrimSpace(tag); tag != "" && n > 0 {
+		m[tag] += n
+	}
+}
+
+func (m modelCloud) RemoveTag(tag string) {
+	tag = strings.TrimSpace(tag)
+	if m[tag] > 1 {
+		m[tag]--
+		return
+	}
+	delete(m, tag)
+}
+
+func (m modelCloud) Total() int {
+	total := 0
+	for _, count := range m {
+		total += count
+	}
+	return total
+}
+
+func (m modelCloud) TopN() []tagcloud.TagStat {
+	stats := []tagcloud.TagStat{}
+	for tag, count := range m {
+		stats = append(stats, tagcloud.TagStat{Tag: tag, OccurrenceCount: count})
+	}
+	slices.SortFunc(stats, func(a, b tagcloud.TagStat) int {
+		if c := cmp.Compare(b.OccurrenceCount, a.OccurrenceCount); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Tag, b.Tag)
+	})
+	return stats
+}
+
+// run with: go test -tags fuzz -run '^$' -fuzz FuzzInvariants ./tagcloud
+func FuzzInvariants(f *testing.F) {
+	f.Add([]byte{0, 5, 10, 1, 2, 3, 4}, "go|rust|go")
+	f.Add([]byte{0, 1, 2, 3}, "")
+	f.Add([]byte{0, 6, 17, 2, 8, 3, 19}, " | \t|go ")
+	f.Add([]byte{0, 5, 21, 36, 2, 7}, strings.Repeat("long", 4096)+"|"+strings.Repeat("long", 4095)+"lonG")
+	f.Add([]byte{0, 5, 5, 16, 2, 7, 3}, "caf\u00e9|cafe\u0301|CAFÉ")
+	f.Add([]byte{1, 1, 1, 2, 2, 2, 2, 3, 3}, "Машина|машина|😊|\xff\xfe")
+
+	f.Fuzz(func(t *testing.T, ops []byte, text string) {
+		tags := strings.Split(text, "|")
+		model := modelCloud{}
+		clouds := map[string]fuzzCloud{
+			"plain":      tagcloud.New(),
+			"ordered":    tagcloud.NewOrdered(),
+			"concurrent": tagcloud.NewConcurrent(),
+			"sharded":    tagcloud.NewSharded(4),
+		}
+		for step, op := range ops {
+			tag := tags[int(op>>3)%len(tags)]
+			n := int(op>>5) + 1
+			for _, cloud := range clouds {
+				switch op % 4 {
+				case 0:
+					cloud.AddTag(tag)
+				case 1:
+					cloud.AddTagN(tag, n)
+				case 2:
+					cloud.RemoveTag(tag)
+				case 3:
+					if cloud, ok := cloud.(*tagcloud.TagCloud); ok {
+						other := tagcloud.New()
+						other.AddTagN(tag, n)
+						cloud.MergeInto(other)
+						continue
+					}
+					cloud.AddTagN(tag, n)
+				}
+			}
+			switch op % 4 {
+			case 0:
+				model.AddTagN(tag, 1)
+			case 2:
+				model.RemoveTag(tag)
+			default:
+				model.AddTagN(tag, n)
+			}
+
+			expected := model.TopN()
+			for name, cloud := range clouds {
+				for _, candidate := range append(tags, tag) {
+					if got, want := cloud.Count(candidate), model[strings.TrimSpace(candidate)]; got != want {
+						t.Fatalf("%s, step %d: Count(%q) = %d, want %d", name, step, candidate, got, want)
+					}
+				}
+				if got := cloud.Len(); got != len(model) {
+					t.Fatalf("%s, step %d: Len() = %d, want %d", name, step, got, len(model))
+				}
+				if got := cloud.Total(); got != model.Total() {
+					t.Fatalf("%s, step %d: Total() = %d, want %d", name, step, got, model.Total())
+				}
+				if got := cloud.TopN(cloud.Len()); !reflect.DeepEqual(got, expected) {
+					t.Fatalf("%s, step %d: TopN(%d) = %v, want %v", name, step, cloud.Len(), got, expected)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package tagcloud
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+// Stat represents statistics regarding single key of Cloud
+type Stat[K comparable] struct {
+	Key             K
+	OccurrenceCount int
+}
+
+// Cloud counts occurrences of keys of any comparable type, e.g. int64 IDs or small structs.
+// it's the counting core of TagCloud, which adds string specific features such as normalization and rendering.
+// the cloud isn't safe for concurrent use
+type Cloud[K comparable] struct {
+	counts bucketCounts[K]
+	total  int
+	// compare orders keys with the same count in TopN, nil leaves their order unspecified
+	compare func(a, b K) int
+}
+
+// NewCloud creates a Cloud which orders keys with the same count in their natural order
+func NewCloud[K cmp.Ordered]() *Cloud[K] {
+	return NewCloudFunc[K](cmp.Compare[K])
+}
+
+// NewCloudFunc creates a Cloud which orders keys with the same count by compare,
+// so TopN is the same between runs. nil compare leaves the order of such keys unspecified
+func NewCloudFunc[K comparable](compare func(a, b K) int) *Cloud[K] {
+	return &Cloud[K]{compare: compare}
+}
+
+// Add increases occurrence count of the key
+func (cloud *Cloud[K]) Add(key K) {
+	cloud.AddN(key, 1)
+}
+
+// AddN works the same as calling Add n times, non-positive n doesn't change the cloud
+func (cloud *Cloud[K]) AddN(key K, n int) {
+	if n <= 0 {
+		return
+	}
+	cloud.set(key, cloud.Count(key)+n)
+}
+
+// Remove decreases occurrence count of the key, the key is removed when its count reaches zero
+func (cloud *Cloud[K]) Remove(key K) {
+	if count, ok := cloud.get(key); ok {
+		cloud.set(key, count-1)
+	}
+}
+
+// Delete removes the key regardless of its occurrence count and reports whether it was present
+func (cloud *Cloud[K]) Delete(key K) bool {
+	_, ok := cloud.get(key)
+	cloud.set(key, 0)
+	return ok
+}
+
+// Count returns occurrence count of the key, 0 if it's absent
+func (cloud *Cloud[K]) Count(key K) int {
+	count, _ := cloud.get(key)
+	return count
+}
+
+// Contains reports whether the key was added and not removed
+func (cloud *Cloud[K]) Contains(key K) bool {
+	_, ok := cloud.get(key)
+	return ok
+}
+
+// Len returns the number of distinct keys
+func (cloud *Cloud[K]) Len() int {
+	return cloud.counts.length
+}
+
+// Total returns the sum of all occurrence counts
+func (cloud *Cloud[K]) Total() int {
+	return cloud.total
+}
+
+// TopN returns top n most frequent keys ordered by occurrence count descending and then by the compare function,
+// n <= 0 gives an empty slice
+func (cloud *Cloud[K]) TopN(n int) []Stat[K] {
+	if n <= 0 {
+		return []Stat[K]{}
+	}
+	stats := make([]Stat[K], 0, cloud.Len())
+	for key, count := range cloud.All() {
+		stats = append(stats, Stat[K]{Key: key, OccurrenceCount: count})
+	}
+	slices.SortFunc(stats, func(a, b Stat[K]) int {
+		if c := cmp.Compare(b.OccurrenceCount, a.OccurrenceCount); c != 0 || cloud.compare == nil {
+			return c
+		}
+		return cloud.compare(a.Key, b.Key)
+	})
+	if len(stats) > n {
+		stats = stats[:n]
+	}
+	return stats
+}
+
+// All yields every key with its occurrence count in no particular order.
+// the cloud may be changed inside the loop, keys added by the loop may or may not be yielded
+func (cloud *Cloud[K]) All() iter.Seq2[K, int] {
+	return cloud.counts.all()
+}
+
+// get returns the count of the key and whether it's present
+func (cloud *Cloud[K]) get(key K) (int, bool) {
+	return cloud.counts.get(key)
+}
+
+// set changes the count of the key and the total, zero count removes the key
+func (cloud *Cloud[K]) set(key K, count int) {
+	old, _ := cloud.counts.get(key)
+	cloud.counts.set(key, count)
+	cloud.total += count - old
+}
+
+// share returns a read-only copy referencing the same buckets, see bucketCounts
+func (cloud *Cloud[K]) share() Cloud[K] {
+	return Cloud[K]{counts: cloud.counts.share(), total: cloud.total, compare: cloud.compare}
+}
+
+// clone returns a deep copy of the cloud
+func (cloud *Cloud[K]) clone() Cloud[K] {
+	return Cloud[K]{counts: cloud.counts.clone(), total: cloud.total, compare: cloud.compare}
+}
+
+// toMap returns a copy of counts as a map
+func (cloud *Cloud[K]) toMap() map[K]int {
+	counts := make(map[K]int, cloud.Len())
+	for key, count := range cloud.All() {
+		counts[key] = count
+	}
+	return counts
+}
+
+// newCloudOf returns a cloud with the counts of the map, keys with zero counts are skipped
+func newCloudOf[K comparable](counts map[K]int) Cloud[K] {
+	var cloud Cloud[K]
+	for key, count := range counts {
+		cloud.set(key, count)
+	}
+	return cloud
+}
+
+// Generic returns a copy of the counting core of the cloud, tags are already normalized.
+// keys with the same count are ordered naturally
+func (cloud *TagCloud) Generic() *Cloud[string] {
+	cloud.rlock()
+	defer cloud.runlock()
+	generic := cloud.tags.clone()
+	generic.compare = cmp.Compare[string]
+	return &generic
+}
@@ -0,0 +1,116 @@
+package tagcloud_test
+
+import (
+	"cmp"
+	"lecture02_homework/tagcloud"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type point struct {
+	x, y int
+}
+
+func comparePoints(a, b point) int {
+	if c := cmp.Compare(a.x, b.x); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.y, b.y)
+}
+
+func TestCloud(t *testing.T) {
+	t.Run("ok, int64 keys in natural order", func(t *testing.T) {
+		cloud := tagcloud.NewCloud[int64]()
+		cloud.AddN(42, 3)
+		cloud.Add(7)
+		cloud.Add(1 << 40)
+		cloud.Add(-5)
+
+		assert.Equal(t, []tagcloud.Stat[int64]{
+			{Key: 42, OccurrenceCount: 3},
+			{Key: -5, OccurrenceCount: 1},
+			{Key: 7, OccurrenceCount: 1},
+		}, cloud.TopN(3))
+		assert.Equal(t, 4, cloud.Len())
+		assert.Equal(t, 6, cloud.Total())
+	})
+
+	t.Run("ok, struct keys with compare function", func(t *testing.T) {
+		cloud := tagcloud.NewCloudFunc(comparePoints)
+		cloud.Add(point{2, 1})
+		cloud.Add(point{1, 2})
+		cloud.AddN(point{1, 1}, 2)
+
+		assert.Equal(t, []tagcloud.Stat[point]{
+			{Key: point{1, 1}, OccurrenceCount: 2},
+			{Key: point{1, 2}, OccurrenceCount: 1},
+			{Key: point{2, 1}, OccurrenceCount: 1},
+		}, cloud.TopN(10))
+		assert.True(t, cloud.Contains(point{2, 1}))
+		assert.Equal(t, 0, cloud.Count(point{3, 3}))
+	})
+
+	t.Run("ok, remove and delete", func(t *testing.T) {
+		cloud := tagcloud.NewCloud[int64]()
+		cloud.AddN(1, 2)
+		cloud.AddN(2, 3)
+		cloud.AddN(3, 0)
+
+		cloud.Remove(1)
+		cloud.Remove(1)
+		cloud.Remove(4)
+
+		assert.False(t, cloud.Contains(1))
+		assert.False(t, cloud.Contains(3))
+		assert.True(t, cloud.Delete(2))
+		assert.False(t, cloud.Delete(2))
+		assert.Equal(t, 0, cloud.Total())
+		assert.Empty(t, cloud.TopN(10))
+	})
+
+	t.Run("ok, all keys", func(t *testing.T) {
+		cloud := tagcloud.NewCloudFunc[point](nil)
+		cloud.AddN(point{1, 1}, 2)
+		cloud.Add(point{0, 0})
+
+		counts := map[point]int{}
+		for key, count := range cloud.All() {
+			counts[key] = count
+		}
+
+		assert.Equal(t, map[point]int{{1, 1}: 2, {0, 0}: 1}, counts)
+	})
+}
+
+func TestGeneric(t *testing.T) {
+	t.Run("ok, same top as the tag cloud", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithCaseInsensitive())
+		tc.AddTags("Go", "go", "rust", "c")
+
+		generic := tc.Generic()
+
+		assert.Equal(t, []tagcloud.Stat[string]{
+			{Key: "go", OccurrenceCount: 2},
+			{Key: "c", OccurrenceCount: 1},
+			{Key: "rust", OccurrenceCount: 1},
+		}, generic.TopN(10))
+		assert.Equal(t, tc.Total(), generic.Total())
+	})
+
+	t.Run("ok, copy is independent of the tag cloud", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTags("go", "go", "rust")
+
+		generic := tc.Generic()
+		tc.AddTag("c")
+		tc.DeleteTag("go")
+		generic.Add("rust")
+
+		assert.Equal(t, 2, generic.Count("go"))
+		assert.Equal(t, 2, generic.Count("rust"))
+		assert.False(t, generic.Contains("c"))
+		assert.Equal(t, 4, generic.Total())
+		assert.Equal(t, 1, tc.Count("rust"))
+	})
+}
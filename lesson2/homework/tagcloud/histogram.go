@@ -0,0 +1,56 @@
+package tagcloud
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// HistogramBucket is the number of tags with count within [Min, Max]
+type HistogramBucket struct {
+	Min int
+	// Max is math.MaxInt for the last bucket
+	Max   int
+	Count int
+}
+
+// Histogram counts tags by ranges of occurrence counts starting at the given bounds,
+// e.g. bounds 1, 2, 5 give ranges 1, 2-4 and 5+. tags with count below the first bound aren't counted.
+// bounds must be non-empty and strictly increasing
+func (cloud *TagCloud) Histogram(bounds []int) ([]HistogramBucket, error) {
+	if len(bounds) == 0 {
+		return nil, fmt.Errorf("histogram needs at least one bucket")
+	}
+	buckets := make([]HistogramBucket, len(bounds))
+	for i, bound := range bounds {
+		if i > 0 && bound <= bounds[i-1] {
+			return nil, fmt.Errorf("histogram buckets must be strictly increasing, got %d after %d", bound, bounds[i-1])
+		}
+		buckets[i] = HistogramBucket{Min: bound, Max: math.MaxInt}
+		if i > 0 {
+			buckets[i-1].Max = bound - 1
+		}
+	}
+
+	cloud.rlock()
+	defer cloud.runlock()
+	for _, count := range cloud.tags.All() {
+		// index of the first bucket starting after the count
+		i := sort.SearchInts(bounds, count+1)
+		if i > 0 {
+			buckets[i-1].Count++
+		}
+	}
+	return buckets, nil
+}
+
+// CountDistribution maps every occurrence count to the number of tags having it
+func (cloud *TagCloud) CountDistribution() map[int]int {
+	cloud.rlock()
+	defer cloud.runlock()
+	distribution := map[int]int{}
+	for _, count := range cloud.tags.All() {
+		distribution[count]++
+	}
+	return distribution
+}
@@ -0,0 +1,72 @@
+package tagcloud_test
+
+import (
+	"lecture02_homework/tagcloud"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogram(t *testing.T) {
+	t.Run("ok, boundaries land in the right bucket", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{
+			"tag0": 1, "tag1": 1, "tag2": 2, "tag3": 4, "tag4": 5, "tag5": 9, "tag6": 10, "tag7": 99, "tag8": 100,
+			"tag9": 1000,
+		})
+		buckets, err := tc.Histogram([]int{1, 2, 5, 10, 100})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []tagcloud.HistogramBucket{
+			{Min: 1, Max: 1, Count: 2},
+			{Min: 2, Max: 4, Count: 2},
+			{Min: 5, Max: 9, Count: 2},
+			{Min: 10, Max: 99, Count: 2},
+			{Min: 100, Max: math.MaxInt, Count: 2},
+		}, buckets)
+	})
+
+	t.Run("ok, counts below the first bucket are skipped", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{
+			"tag0": 1, "tag1": 1, "tag2": 2, "tag3": 4, "tag4": 5, "tag5": 9, "tag6": 10, "tag7": 99, "tag8": 100,
+			"tag9": 1000,
+		})
+		buckets, err := tc.Histogram([]int{10})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []tagcloud.HistogramBucket{{Min: 10, Max: math.MaxInt, Count: 4}}, buckets)
+	})
+
+	t.Run("ok, empty cloud", func(t *testing.T) {
+		buckets, err := tagcloud.New().Histogram([]int{1, 10})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []tagcloud.HistogramBucket{{Min: 1, Max: 9}, {Min: 10, Max: math.MaxInt}}, buckets)
+	})
+
+	t.Run("error, invalid buckets", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{
+			"tag0": 1, "tag1": 1, "tag2": 2, "tag3": 4, "tag4": 5, "tag5": 9, "tag6": 10, "tag7": 99, "tag8": 100,
+			"tag9": 1000,
+		})
+		for _, bounds := range [][]int{nil, {}, {1, 5, 2}, {1, 1}} {
+			_, err := tc.Histogram(bounds)
+
+			assert.Error(t, err, bounds)
+		}
+	})
+}
+
+func TestCountDistribution(t *testing.T) {
+	t.Run("ok, exact counts", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTags("a", "b", "c", "c", "d", "d")
+		tc.AddTagN("e", 7)
+
+		assert.Equal(t, map[int]int{1: 2, 2: 2, 7: 1}, tc.CountDistribution())
+	})
+
+	t.Run("ok, empty cloud", func(t *testing.T) {
+		assert.Empty(t, tagcloud.New().CountDistribution())
+	})
+}
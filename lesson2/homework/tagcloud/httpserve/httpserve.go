@@ -0,0 +1,110 @@
+// Package httpserve exposes a tag cloud over HTTP, so a small service can be stood up around a cloud
+package httpserve
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"lecture02_homework/tagcloud"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+const (
+	// DefaultN is the number of tags returned by /top without n
+	DefaultN = 50
+	// maxBodySize limits the body of POST /tags
+	maxBodySize = 1 << 20
+)
+
+// tagStat is the JSON form of a tag, the same as the -json output of the tagcloud command
+type tagStat struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// server serializes requests with a mutex, so any cloud can be served, not only a concurrent one
+type server struct {
+	mu    sync.Mutex
+	cloud *tagcloud.TagCloud
+}
+
+// Handler returns a handler serving the cloud:
+//
+//	GET /top?n=50      JSON array of top n {"tag", "count"} objects in TopN order, n is DefaultN by default
+//	GET /count?tag=x   {"tag", "count"} object of the tag, the count is 0 for an absent tag
+//	POST /tags         adds tags of a JSON array of strings
+//	GET /export        all tags as CSV with word,count header as the -csv output of the tagcloud command
+//
+// invalid requests get 400 Bad Request with a plain text error
+func Handler(cloud *tagcloud.TagCloud) http.Handler {
+	s := &server{cloud: cloud}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /top", s.top)
+	mux.HandleFunc("GET /count", s.count)
+	mux.HandleFunc("POST /tags", s.addTags)
+	mux.HandleFunc("GET /export", s.export)
+	return mux
+}
+
+func (s *server) top(w http.ResponseWriter, r *http.Request) {
+	n := DefaultN
+	if param := r.URL.Query().Get("n"); param != "" {
+		var err error
+		if n, err = strconv.Atoi(param); err != nil || n <= 0 {
+			http.Error(w, fmt.Sprintf("n must be a positive integer, got %q", param), http.StatusBadRequest)
+			return
+		}
+	}
+	s.mu.Lock()
+	stats := s.cloud.TopN(n)
+	s.mu.Unlock()
+	result := make([]tagStat, len(stats))
+	for i, stat := range stats {
+		result[i] = tagStat{Tag: stat.Tag, Count: stat.OccurrenceCount}
+	}
+	writeJSON(w, result)
+}
+
+func (s *server) count(w http.ResponseWriter, r *http.Request) {
+	tag := r.URL.Query().Get("tag")
+	if tag == "" {
+		http.Error(w, "tag is required", http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	count := s.cloud.Count(tag)
+	s.mu.Unlock()
+	writeJSON(w, tagStat{Tag: tag, Count: count})
+}
+
+func (s *server) addTags(w http.ResponseWriter, r *http.Request) {
+	var tags []string
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&tags); err != nil {
+		http.Error(w, fmt.Sprintf("body must be a JSON array of strings: %v", err), http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	s.cloud.AddTags(tags...)
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *server) export(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	stats := s.cloud.TopN(s.cloud.Len())
+	s.mu.Unlock()
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	writer := csv.NewWriter(w)
+	_ = writer.Write([]string{"word", "count"})
+	for _, stat := range stats {
+		_ = writer.Write([]string{stat.Tag, strconv.Itoa(stat.OccurrenceCount)})
+	}
+	writer.Flush()
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,152 @@
+package httpserve_test
+
+import (
+	"io"
+	"lecture02_homework/tagcloud"
+	"lecture02_homework/tagcloud/httpserve"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newServer(t *testing.T) (*httptest.Server, *tagcloud.TagCloud) {
+	cloud := tagcloud.New(tagcloud.WithCaseInsensitive())
+	cloud.AddTagN("go", 3)
+	cloud.AddTagN("rust", 2)
+	cloud.AddTag("c, c++")
+	server := httptest.NewServer(httpserve.Handler(cloud))
+	t.Cleanup(server.Close)
+	return server, cloud
+}
+
+func get(t *testing.T, url string) (int, string) {
+	t.Helper()
+	response, err := http.Get(url)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	return response.StatusCode, string(body)
+}
+
+func TestTop(t *testing.T) {
+	server, _ := newServer(t)
+
+	t.Run("ok, top n", func(t *testing.T) {
+		code, body := get(t, server.URL+"/top?n=2")
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.JSONEq(t, `[{"tag":"go","count":3},{"tag":"rust","count":2}]`, body)
+	})
+
+	t.Run("ok, default n", func(t *testing.T) {
+		code, body := get(t, server.URL+"/top")
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.JSONEq(t, `[{"tag":"go","count":3},{"tag":"rust","count":2},{"tag":"c, c++","count":1}]`, body)
+	})
+
+	t.Run("error, bad n", func(t *testing.T) {
+		for _, n := range []string{"0", "-1", "ten"} {
+			code, body := get(t, server.URL+"/top?n="+n)
+
+			assert.Equal(t, http.StatusBadRequest, code)
+			assert.Equal(t, `n must be a positive integer, got "`+n+"\"\n", body)
+		}
+	})
+
+	t.Run("error, wrong method", func(t *testing.T) {
+		response, err := http.Post(server.URL+"/top", "application/json", nil)
+		require.NoError(t, err)
+		response.Body.Close()
+
+		assert.Equal(t, http.StatusMethodNotAllowed, response.StatusCode)
+	})
+}
+
+func TestCount(t *testing.T) {
+	server, _ := newServer(t)
+
+	t.Run("ok, present and absent tags", func(t *testing.T) {
+		code, body := get(t, server.URL+"/count?tag=GO")
+		assert.Equal(t, http.StatusOK, code)
+		assert.JSONEq(t, `{"tag":"GO","count":3}`, body)
+
+		code, body = get(t, server.URL+"/count?tag=zig")
+		assert.Equal(t, http.StatusOK, code)
+		assert.JSONEq(t, `{"tag":"zig","count":0}`, body)
+	})
+
+	t.Run("error, missing tag", func(t *testing.T) {
+		code, body := get(t, server.URL+"/count")
+
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "tag is required\n", body)
+	})
+}
+
+func TestAddTags(t *testing.T) {
+	t.Run("ok, tags are added", func(t *testing.T) {
+		server, cloud := newServer(t)
+
+		response, err := http.Post(server.URL+"/tags", "application/json", strings.NewReader(`["Rust", "zig", "rust"]`))
+		require.NoError(t, err)
+		response.Body.Close()
+
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+		assert.Equal(t, 4, cloud.Count("rust"))
+		assert.Equal(t, 1, cloud.Count("zig"))
+	})
+
+	t.Run("ok, concurrent requests to a plain cloud", func(t *testing.T) {
+		server, cloud := newServer(t)
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 10; j++ {
+					response, err := http.Post(server.URL+"/tags", "application/json", strings.NewReader(`["zig"]`))
+					if assert.NoError(t, err) {
+						response.Body.Close()
+					}
+					get(t, server.URL+"/top?n=1")
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 80, cloud.Count("zig"))
+	})
+
+	t.Run("error, invalid body", func(t *testing.T) {
+		server, cloud := newServer(t)
+		for _, body := range []string{`{"tag": "go"}`, `["go", 1]`, `[`} {
+			response, err := http.Post(server.URL+"/tags", "application/json", strings.NewReader(body))
+			require.NoError(t, err)
+			response.Body.Close()
+
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode, body)
+		}
+		assert.Equal(t, 3, cloud.Count("go"))
+	})
+}
+
+func TestExport(t *testing.T) {
+	server, _ := newServer(t)
+
+	response, err := http.Get(server.URL + "/export")
+	require.NoError(t, err)
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "text/csv; charset=utf-8", response.Header.Get("Content-Type"))
+	assert.Equal(t, "word,count\ngo,3\nrust,2\n\"c, c++\",1\n", string(body))
+}
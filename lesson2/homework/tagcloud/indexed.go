@@ -0,0 +1,268 @@
+package tagcloud
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strings"
+)
+
+// indexed file layout, all numbers are little endian:
+// header: magic, version byte, 3 zero bytes, then uint64 number of tags, total count, number of top tags,
+// offset of the tag index and offset of the top index.
+// records sorted by tag follow the header, a record is uint32 tag length, tag bytes and uint64 count.
+// the tag index is uint64 offsets of all records in tag order, the top index is offsets of top records in TopN order
+const (
+	indexedMagic      = "TGIX"
+	indexedVersion    = 1
+	indexedHeaderSize = 8 + 5*8
+	// DefaultIndexedTopK is the number of top tags stored by WriteIndexed when topK isn't positive
+	DefaultIndexedTopK = 10000
+)
+
+// maxIndexedTagLen is the length limit of a tag stored with uint32 length
+var maxIndexedTagLen uint64 = math.MaxUint32
+
+// ErrBeyondTopK is returned by StaticCloud.TopN for more tags than the file stores in TopN order
+var ErrBeyondTopK = errors.New("requested more top tags than stored")
+
+// WriteIndexed writes the cloud in the format read by OpenIndexed, the topK most frequent tags are stored
+// in TopN order, non-positive topK means DefaultIndexedTopK.
+// the header is written last, so w must be able to seek back to the start
+func (cloud *TagCloud) WriteIndexed(w io.WriteSeeker, topK int) error {
+	if topK <= 0 {
+		topK = DefaultIndexedTopK
+	}
+	cloud.rlock()
+	stats := make([]TagStat, 0, cloud.tags.Len())
+	for tag, count := range cloud.tags.All() {
+		stats = append(stats, TagStat{Tag: tag, OccurrenceCount: count})
+	}
+	total := cloud.tags.Total()
+	top := cloud.topStats(topK, 0)
+	cloud.runlock()
+	// tags are checked before writing, so an error leaves w untouched
+	for _, stat := range stats {
+		if uint64(len(stat.Tag)) > maxIndexedTagLen {
+			return fmt.Errorf("tag %.20q... is too long: %d bytes", stat.Tag, len(stat.Tag))
+		}
+	}
+	slices.SortFunc(stats, func(a, b TagStat) int {
+		return cmp.Compare(a.Tag, b.Tag)
+	})
+
+	start, err := w.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(w)
+	writer.Write(make([]byte, indexedHeaderSize))
+	offset := uint64(indexedHeaderSize)
+	offsets := make(map[string]uint64, len(stats))
+	index := make([]uint64, len(stats))
+	for i, stat := range stats {
+		index[i] = offset
+		offsets[stat.Tag] = offset
+		binary.Write(writer, binary.LittleEndian, uint32(len(stat.Tag)))
+		writer.WriteString(stat.Tag)
+		binary.Write(writer, binary.LittleEndian, uint64(stat.OccurrenceCount))
+		offset += 4 + uint64(len(stat.Tag)) + 8
+	}
+	indexOffset := offset
+	binary.Write(writer, binary.LittleEndian, index)
+	topOffset := indexOffset + 8*uint64(len(index))
+	for _, stat := range top {
+		binary.Write(writer, binary.LittleEndian, offsets[stat.Tag])
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+
+	header := make([]byte, 0, indexedHeaderSize)
+	header = append(header, indexedMagic...)
+	header = append(header, indexedVersion, 0, 0, 0)
+	for _, value := range []uint64{uint64(len(stats)), uint64(total), uint64(len(top)), indexOffset, topOffset} {
+		header = binary.LittleEndian.AppendUint64(header, value)
+	}
+	if _, err := w.Seek(start, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err = w.Seek(start+int64(topOffset)+8*int64(len(top)), io.SeekStart)
+	return err
+}
+
+// StaticCloud is a read-only cloud served from a file written by WriteIndexed without loading it,
+// Count reads O(log n) records and TopN reads only the requested top records.
+// tags are looked up exactly as stored, without normalization. it's safe for concurrent use
+type StaticCloud struct {
+	r      io.ReaderAt
+	closer io.Closer
+	// tags, total and top are the numbers of tags, occurrences and tags stored in TopN order
+	tags        int
+	total       int
+	top         int
+	indexOffset int64
+	topOffset   int64
+}
+
+// OpenIndexed opens a file written by WriteIndexed, the file is read on demand until Close
+func OpenIndexed(path string) (*StaticCloud, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	cloud, err := OpenIndexedReader(file, info.Size())
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	cloud.closer = file
+	return cloud, nil
+}
+
+// OpenIndexedReader works as OpenIndexed for data of the given size written by WriteIndexed
+func OpenIndexedReader(r io.ReaderAt, size int64) (*StaticCloud, error) {
+	header := make([]byte, indexedHeaderSize)
+	if err := readAt(r, header, 0); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(header[:len(indexedMagic)], []byte(indexedMagic)) {
+		return nil, fmt.Errorf("not an indexed tag cloud: unknown header %q", header[:len(indexedMagic)])
+	}
+	if version := header[len(indexedMagic)]; version != indexedVersion {
+		return nil, fmt.Errorf("unsupported indexed tag cloud version %d", version)
+	}
+	var values [5]uint64
+	for i := range values {
+		values[i] = binary.LittleEndian.Uint64(header[8+8*i:])
+	}
+	tags, total, top, indexOffset, topOffset := values[0], values[1], values[2], values[3], values[4]
+	if total > math.MaxInt || top > tags || indexOffset < indexedHeaderSize || indexOffset > uint64(size) ||
+		tags > (uint64(size)-indexOffset)/8 || topOffset != indexOffset+8*tags || (uint64(size)-topOffset)/8 < top {
+		return nil, errors.New("indexed tag cloud is corrupted: invalid header")
+	}
+	return &StaticCloud{
+		r:           r,
+		tags:        int(tags),
+		total:       int(total),
+		top:         int(top),
+		indexOffset: int64(indexOffset),
+		topOffset:   int64(topOffset),
+	}, nil
+}
+
+// Close closes the file opened by OpenIndexed
+func (cloud *StaticCloud) Close() error {
+	if cloud.closer == nil {
+		return nil
+	}
+	return cloud.closer.Close()
+}
+
+// Len returns the number of tags
+func (cloud *StaticCloud) Len() int {
+	return cloud.tags
+}
+
+// Total returns the sum of all occurrence counts
+func (cloud *StaticCloud) Total() int {
+	return cloud.total
+}
+
+// Count returns the occurrence count of the tag, zero if the tag is absent
+func (cloud *StaticCloud) Count(tag string) (int, error) {
+	low, high := 0, cloud.tags
+	for low < high {
+		middle := int(uint(low+high) >> 1)
+		stat, err := cloud.recordAt(cloud.indexOffset + 8*int64(middle))
+		if err != nil {
+			return 0, err
+		}
+		switch c := strings.Compare(stat.Tag, tag); {
+		case c == 0:
+			return stat.OccurrenceCount, nil
+		case c < 0:
+			low = middle + 1
+		default:
+			high = middle
+		}
+	}
+	return 0, nil
+}
+
+// TopN returns n top tags in TopN order, n above the number of stored top tags is ErrBeyondTopK
+// unless the file stores all tags. n <= 0 gives an empty slice
+func (cloud *StaticCloud) TopN(n int) ([]TagStat, error) {
+	if n > cloud.top && cloud.top < cloud.tags {
+		return nil, fmt.Errorf("%w: %d requested, %d stored", ErrBeyondTopK, n, cloud.top)
+	}
+	n = max(min(n, cloud.top), 0)
+	stats := make([]TagStat, n)
+	for i := range stats {
+		stat, err := cloud.recordAt(cloud.topOffset + 8*int64(i))
+		if err != nil {
+			return nil, err
+		}
+		stats[i] = stat
+	}
+	return stats, nil
+}
+
+// recordAt reads the record which offset is stored at the given position of an index
+func (cloud *StaticCloud) recordAt(position int64) (TagStat, error) {
+	var buf [8]byte
+	if err := readAt(cloud.r, buf[:], position); err != nil {
+		return TagStat{}, err
+	}
+	offset := int64(binary.LittleEndian.Uint64(buf[:]))
+	if offset < indexedHeaderSize || offset >= cloud.indexOffset {
+		return TagStat{}, fmt.Errorf("indexed tag cloud is corrupted: invalid record offset %d", offset)
+	}
+	if err := readAt(cloud.r, buf[:4], offset); err != nil {
+		return TagStat{}, err
+	}
+	length := int64(binary.LittleEndian.Uint32(buf[:4]))
+	if offset+4+length+8 > cloud.indexOffset {
+		return TagStat{}, fmt.Errorf("indexed tag cloud is corrupted: record at %d is too long", offset)
+	}
+	record := make([]byte, length+8)
+	if err := readAt(cloud.r, record, offset+4); err != nil {
+		return TagStat{}, err
+	}
+	count := binary.LittleEndian.Uint64(record[length:])
+	if count > math.MaxInt {
+		return TagStat{}, fmt.Errorf("indexed tag cloud is corrupted: invalid count %d", count)
+	}
+	return TagStat{Tag: string(record[:length]), OccurrenceCount: int(count)}, nil
+}
+
+// readAt reads len(buf) bytes at the offset, unlike io.ReaderAt it never reports io.EOF after a full read
+func readAt(r io.ReaderAt, buf []byte, offset int64) error {
+	n, err := r.ReadAt(buf, offset)
+	if n == len(buf) {
+		return nil
+	}
+	return indexedError(err)
+}
+
+func indexedError(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("indexed tag cloud is truncated: %w", io.ErrUnexpectedEOF)
+	}
+	return err
+}
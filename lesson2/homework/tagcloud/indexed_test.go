@@ -0,0 +1,179 @@
+package tagcloud_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"lecture02_homework/tagcloud"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingReaderAt counts ReadAt calls
+type countingReaderAt struct {
+	r     io.ReaderAt
+	reads int
+}
+
+func (r *countingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	r.reads++
+	return r.r.ReadAt(p, off)
+}
+
+// writeIndexed writes the cloud to a temporary file and returns its path
+func writeIndexed(t testing.TB, tc *tagcloud.TagCloud, topK int) string {
+	path := filepath.Join(t.TempDir(), "cloud.idx")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	defer file.Close()
+	require.NoError(t, tc.WriteIndexed(file, topK))
+	return path
+}
+
+func TestIndexed(t *testing.T) {
+	tc := tagcloud.New()
+	tc.AddTagN("go", 5)
+	tc.AddTagN("rust", 3)
+	tc.AddTagN("машина", 3)
+	tc.AddTag("c")
+	tc.AddTag("zig")
+
+	t.Run("ok, round trip", func(t *testing.T) {
+		static, err := tagcloud.OpenIndexed(writeIndexed(t, tc, 2))
+		require.NoError(t, err)
+		defer static.Close()
+
+		assert.Equal(t, 5, static.Len())
+		assert.Equal(t, 13, static.Total())
+		for _, stat := range tc.TopN(tc.Len()) {
+			count, err := static.Count(stat.Tag)
+			require.NoError(t, err)
+			assert.Equal(t, stat.OccurrenceCount, count, stat.Tag)
+		}
+		for _, absent := range []string{"", "a", "gp", "zz", "Go"} {
+			count, err := static.Count(absent)
+			require.NoError(t, err)
+			assert.Zero(t, count, absent)
+		}
+		top, err := static.TopN(2)
+		require.NoError(t, err)
+		assert.Equal(t, tc.TopN(2), top)
+		top, err = static.TopN(0)
+		require.NoError(t, err)
+		assert.Equal(t, []tagcloud.TagStat{}, top)
+	})
+
+	t.Run("ok, all tags stored in order", func(t *testing.T) {
+		static, err := tagcloud.OpenIndexed(writeIndexed(t, tc, 0))
+		require.NoError(t, err)
+		defer static.Close()
+
+		top, err := static.TopN(100)
+
+		require.NoError(t, err)
+		assert.Equal(t, tc.TopN(100), top)
+	})
+
+	t.Run("ok, empty cloud", func(t *testing.T) {
+		static, err := tagcloud.OpenIndexed(writeIndexed(t, tagcloud.New(), 10))
+		require.NoError(t, err)
+		defer static.Close()
+
+		count, err := static.Count("go")
+		require.NoError(t, err)
+		assert.Zero(t, count)
+		top, err := static.TopN(10)
+		require.NoError(t, err)
+		assert.Empty(t, top)
+	})
+
+	t.Run("error, beyond top k", func(t *testing.T) {
+		static, err := tagcloud.OpenIndexed(writeIndexed(t, tc, 2))
+		require.NoError(t, err)
+		defer static.Close()
+
+		_, err = static.TopN(3)
+
+		assert.ErrorIs(t, err, tagcloud.ErrBeyondTopK)
+		assert.EqualError(t, err, "requested more top tags than stored: 3 requested, 2 stored")
+	})
+
+	t.Run("error, too long tag writes nothing", func(t *testing.T) {
+		defer tagcloud.SetMaxIndexedTagLen(6)()
+		file, err := os.Create(filepath.Join(t.TempDir(), "cloud.idx"))
+		require.NoError(t, err)
+		defer file.Close()
+
+		err = tc.WriteIndexed(file, 2)
+
+		assert.ErrorContains(t, err, "is too long: 12 bytes")
+		stat, err := file.Stat()
+		require.NoError(t, err)
+		assert.Zero(t, stat.Size())
+	})
+
+	t.Run("error, invalid files", func(t *testing.T) {
+		data, err := os.ReadFile(writeIndexed(t, tc, 2))
+		require.NoError(t, err)
+
+		_, err = tagcloud.OpenIndexedReader(bytes.NewReader(data[:20]), 20)
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+		_, err = tagcloud.OpenIndexedReader(bytes.NewReader(data[:len(data)-1]), int64(len(data)-1))
+		assert.EqualError(t, err, "indexed tag cloud is corrupted: invalid header")
+
+		saved := append([]byte("TGCL"), data[4:]...)
+		_, err = tagcloud.OpenIndexedReader(bytes.NewReader(saved), int64(len(saved)))
+		assert.EqualError(t, err, `not an indexed tag cloud: unknown header "TGCL"`)
+
+		_, err = tagcloud.OpenIndexed(filepath.Join(t.TempDir(), "missing.idx"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestIndexedCountReads(t *testing.T) {
+	tc := tagcloud.New()
+	for i := 0; i < 100000; i++ {
+		tc.AddTagN(fmt.Sprintf("tag-%d", i), i%100+1)
+	}
+	data, err := os.ReadFile(writeIndexed(t, tc, 10))
+	require.NoError(t, err)
+	reader := &countingReaderAt{r: bytes.NewReader(data)}
+	static, err := tagcloud.OpenIndexedReader(reader, int64(len(data)))
+	require.NoError(t, err)
+	// a record is read by three ReadAt calls on every step of the binary search
+	limit := 3 * bits.Len(uint(tc.Len()))
+
+	for _, tag := range []string{"tag-0", "tag-99999", "tag-50000", "absent"} {
+		reader.reads = 0
+		count, err := static.Count(tag)
+
+		require.NoError(t, err)
+		assert.Equal(t, tc.Count(tag), count)
+		assert.LessOrEqual(t, reader.reads, limit, tag)
+	}
+}
+
+func BenchmarkIndexedCount(b *testing.B) {
+	for _, size := range []int{1000, 1000000} {
+		tc := tagcloud.New()
+		for i := 0; i < size; i++ {
+			tc.AddTag(fmt.Sprintf("tag-%d", i))
+		}
+		static, err := tagcloud.OpenIndexed(writeIndexed(b, tc, 10))
+		require.NoError(b, err)
+		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := static.Count(fmt.Sprintf("tag-%d", i%size)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		static.Close()
+	}
+}
@@ -0,0 +1,39 @@
+package tagcloud
+
+import (
+	"iter"
+	"slices"
+)
+
+// All yields every tag with its occurrence count in no particular order.
+// it iterates over a copy taken when the iteration starts, so the cloud may be changed inside the loop
+func (cloud *TagCloud) All() iter.Seq2[string, int] {
+	return func(yield func(string, int) bool) {
+		for tag, count := range cloud.snapshot().tags {
+			if !yield(tag, count) {
+				return
+			}
+		}
+	}
+}
+
+// SortedAll yields every tag in TopN order.
+// it iterates over the snapshot sorted when the iteration starts, so the cloud may be changed inside the loop
+// and the changes are not visible to the iteration
+func (cloud *TagCloud) SortedAll() iter.Seq[TagStat] {
+	return func(yield func(TagStat) bool) {
+		cloud.rlock()
+		sorted := cloud.sortedStats()
+		if cloud.surfaceForms {
+			// display forms are filled in a copy, the sorted stats are shared
+			sorted = slices.Clone(sorted)
+			cloud.fillDisplayForms(sorted)
+		}
+		cloud.runlock()
+		for _, stat := range sorted {
+			if !yield(stat) {
+				return
+			}
+		}
+	}
+}
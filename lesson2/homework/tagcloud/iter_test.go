@@ -0,0 +1,93 @@
+package tagcloud_test
+
+import (
+	"lecture02_homework/tagcloud"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAll(t *testing.T) {
+	t.Run("ok, full iteration", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"a": 3, "b": 1, "c": 3, "d": 2})
+
+		counts := map[string]int{}
+		for tag, count := range tc.All() {
+			counts[tag] = count
+		}
+
+		assert.Equal(t, map[string]int{"a": 3, "b": 1, "c": 3, "d": 2}, counts)
+	})
+
+	t.Run("ok, early break", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"a": 3, "b": 1, "c": 3, "d": 2})
+		seen := 0
+		for range tc.All() {
+			seen++
+			if seen == 2 {
+				break
+			}
+		}
+
+		assert.Equal(t, 2, seen)
+	})
+
+	t.Run("ok, change during iteration", func(t *testing.T) {
+		tc := tagcloud.NewConcurrent()
+		tc.AddTags("a", "b")
+
+		seen := 0
+		for tag := range tc.All() {
+			tc.DeleteTag(tag)
+			tc.AddTag(tag + "!")
+			seen++
+		}
+
+		assert.Equal(t, 2, seen)
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "a!", OccurrenceCount: 1}, {Tag: "b!", OccurrenceCount: 1}}, tc.TopN(10))
+	})
+}
+
+func TestSortedAll(t *testing.T) {
+	t.Run("ok, full iteration", func(t *testing.T) {
+		counts := map[string]int{"a": 3, "b": 1, "c": 3, "d": 2}
+		for _, tc := range []*tagcloud.TagCloud{tagcloud.FromMap(counts), tagcloud.NewOrdered().Merge(tagcloud.FromMap(counts))} {
+			var stats []tagcloud.TagStat
+			for stat := range tc.SortedAll() {
+				stats = append(stats, stat)
+			}
+
+			assert.Equal(t, tc.TopN(tc.Len()), stats)
+		}
+	})
+
+	t.Run("ok, early break", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"a": 3, "b": 1, "c": 3, "d": 2})
+
+		var stats []tagcloud.TagStat
+		for stat := range tc.SortedAll() {
+			stats = append(stats, stat)
+			if len(stats) == 3 {
+				break
+			}
+		}
+
+		assert.Equal(t, tc.TopN(3), stats)
+	})
+
+	t.Run("ok, change during iteration", func(t *testing.T) {
+		tc := tagcloud.NewConcurrent()
+		tc.AddTagN("a", 2)
+		tc.AddTag("b")
+
+		var tags []string
+		for stat := range tc.SortedAll() {
+			tags = append(tags, stat.Tag)
+			tc.AddTagN("b", 5)
+			tc.AddTag("c")
+		}
+
+		assert.Equal(t, []string{"a", "b"}, tags)
+		assert.Equal(t, 11, tc.Count("b"))
+	})
+}
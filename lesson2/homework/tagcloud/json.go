@@ -0,0 +1,81 @@
+package tagcloud
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// jsonTagStat is the serialized form of a single tag, times are set only by clouds with WithTimestamps
+// and peaks only by clouds with WithHighWaterMarks
+type jsonTagStat struct {
+	Tag       string     `json:"tag"`
+	Count     int        `json:"count"`
+	FirstSeen *time.Time `json:"first_seen,omitempty"`
+	LastSeen  *time.Time `json:"last_seen,omitempty"`
+	Peak      int        `json:"peak,omitempty"`
+}
+
+// MarshalJSON encodes the cloud as an array of {"tag": ..., "count": ...} objects sorted by tag,
+// so equal clouds always give equal output. a cloud with WithTimestamps adds "first_seen" and "last_seen",
+// a cloud with WithHighWaterMarks adds "peak" and writes retired peaks as objects with zero "count"
+func (cloud *TagCloud) MarshalJSON() ([]byte, error) {
+	cloud.rlock()
+	stats := make([]jsonTagStat, 0, cloud.tags.Len())
+	for tag, count := range cloud.tags.All() {
+		stat := jsonTagStat{Tag: tag, Count: count, Peak: cloud.peaks[tag]}
+		if times, ok := cloud.seen[tag]; ok {
+			stat.FirstSeen, stat.LastSeen = &times.first, &times.last
+		}
+		stats = append(stats, stat)
+	}
+	for tag, peak := range cloud.retiredPeaks {
+		stats = append(stats, jsonTagStat{Tag: tag, Peak: peak})
+	}
+	cloud.runlock()
+	slices.SortFunc(stats, func(a, b jsonTagStat) int {
+		return cmp.Compare(a.Tag, b.Tag)
+	})
+	return json.Marshal(stats)
+}
+
+// UnmarshalJSON replaces content of the cloud with the decoded tags.
+// non-positive counts other than ones of retired peaks and repeated tags are rejected,
+// a zero value TagCloud can be used as the target.
+// timestamps are kept only by a cloud with WithTimestamps and peaks only by a cloud with WithHighWaterMarks
+func (cloud *TagCloud) UnmarshalJSON(data []byte) error {
+	var stats []jsonTagStat
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return fmt.Errorf("invalid tag cloud JSON: %w", err)
+	}
+	tags := make(map[string]int, len(stats))
+	seen := map[string]seenTimes{}
+	peaks := map[string]int{}
+	for _, stat := range stats {
+		if stat.Count < 0 || stat.Count == 0 && stat.Peak <= 0 {
+			return fmt.Errorf("tag %q has non-positive count %d", stat.Tag, stat.Count)
+		}
+		_, ok := tags[stat.Tag]
+		if _, retired := peaks[stat.Tag]; ok || retired {
+			return fmt.Errorf("duplicate tag %q", stat.Tag)
+		}
+		if stat.Peak > 0 {
+			peaks[stat.Tag] = stat.Peak
+		}
+		if stat.Count == 0 {
+			continue
+		}
+		tags[stat.Tag] = stat.Count
+		if stat.FirstSeen != nil && stat.LastSeen != nil {
+			seen[stat.Tag] = seenTimes{first: *stat.FirstSeen, last: *stat.LastSeen}
+		}
+	}
+	cloud.lock()
+	defer cloud.unlock()
+	cloud.replaceTags(tags)
+	cloud.restoreSeen(seen)
+	cloud.restorePeaks(peaks)
+	return nil
+}
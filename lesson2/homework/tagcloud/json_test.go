@@ -0,0 +1,96 @@
+package tagcloud_test
+
+import (
+	"encoding/json"
+	"lecture02_homework/tagcloud"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSON(t *testing.T) {
+	t.Run("ok, round trip", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("go", 3)
+		tc.AddTagN("json", 1<<40)
+		tc.AddTag("ä")
+
+		data, err := json.Marshal(tc)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `[{"tag":"go","count":3},{"tag":"json","count":1099511627776},{"tag":"ä","count":1}]`, string(data))
+
+		var restored tagcloud.TagCloud
+		err = json.Unmarshal(data, &restored)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.TopN(10), restored.TopN(10))
+		assert.Equal(t, tc.Total(), restored.Total())
+
+		restored.AddTag("new")
+		assert.Equal(t, 1, restored.Count("new"))
+	})
+
+	t.Run("ok, output is sorted by tag", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTags("c", "a", "b", "a")
+
+		data, err := json.Marshal(tc)
+		assert.NoError(t, err)
+		assert.Equal(t, `[{"tag":"a","count":2},{"tag":"b","count":1},{"tag":"c","count":1}]`, string(data))
+	})
+
+	t.Run("ok, empty cloud", func(t *testing.T) {
+		data, err := json.Marshal(tagcloud.New())
+		assert.NoError(t, err)
+		assert.Equal(t, `[]`, string(data))
+
+		tc := tagcloud.New()
+		tc.AddTag("old")
+		assert.NoError(t, json.Unmarshal(data, tc))
+		assert.Equal(t, 0, tc.Len())
+		assert.Equal(t, 0, tc.Total())
+	})
+
+	t.Run("ok, streaming decoder", func(t *testing.T) {
+		input := `[{"tag":"a","count":1}]
+[{"tag":"b","count":2},{"tag":"c","count":3}]
+`
+		decoder := json.NewDecoder(strings.NewReader(input))
+		var clouds []*tagcloud.TagCloud
+		for decoder.More() {
+			tc := tagcloud.New()
+			assert.NoError(t, decoder.Decode(tc))
+			clouds = append(clouds, tc)
+		}
+
+		if assert.Len(t, clouds, 2) {
+			assert.Equal(t, 1, clouds[0].Total())
+			assert.Equal(t, []tagcloud.TagStat{{Tag: "c", OccurrenceCount: 3}, {Tag: "b", OccurrenceCount: 2}}, clouds[1].TopN(10))
+		}
+	})
+
+	t.Run("error, malformed input", func(t *testing.T) {
+		for _, input := range []string{`{"a":1}`, `[{"tag":"a","count":"1"}]`, `[`, `null1`} {
+			var tc tagcloud.TagCloud
+			assert.Error(t, json.Unmarshal([]byte(input), &tc), input)
+		}
+	})
+
+	t.Run("error, negative count", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag("old")
+
+		err := json.Unmarshal([]byte(`[{"tag":"a","count":1},{"tag":"b","count":-2}]`), tc)
+
+		assert.EqualError(t, err, `tag "b" has non-positive count -2`)
+		assert.Equal(t, 1, tc.Count("old"))
+	})
+
+	t.Run("error, duplicate tag", func(t *testing.T) {
+		var tc tagcloud.TagCloud
+
+		err := json.Unmarshal([]byte(`[{"tag":"a","count":1},{"tag":"a","count":2}]`), &tc)
+
+		assert.EqualError(t, err, `duplicate tag "a"`)
+	})
+}
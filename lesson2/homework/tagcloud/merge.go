@@ -0,0 +1,70 @@
+package tagcloud
+
+// Merge returns a new cloud with summed counts of both clouds, neither cloud is changed.
+// nil other is treated as an empty cloud, the result is concurrent if the receiver is
+func (cloud *TagCloud) Merge(other *TagCloud) *TagCloud {
+	result := cloud.Clone()
+	result.MergeInto(other)
+	return result
+}
+
+// MergeInto adds counts of other cloud to the receiver, nil other is treated as an empty cloud
+func (cloud *TagCloud) MergeInto(other *TagCloud) {
+	if other == nil {
+		return
+	}
+	// other is copied first, so merging two clouds into each other concurrently can't deadlock
+	data := other.snapshot()
+	cloud.lock()
+	defer cloud.unlock()
+	cloud.mergeData(data)
+}
+
+// mergeData adds copied counts to the cloud, keys are normalized by the cloud
+func (cloud *TagCloud) mergeData(data tagData) {
+	for tag := range data.tags {
+		cloud.mergeTag(data, tag)
+	}
+	for pair, count := range data.pairs {
+		cloud.mergePair(pair, count, data.stemmed)
+	}
+}
+
+// mergeTag adds the copied count of a tag with its extra weight and times
+func (cloud *TagCloud) mergeTag(data tagData, tag string) {
+	key, ok := cloud.copiedKey(tag, data.stemmed)
+	if !ok {
+		return
+	}
+	before, existed := cloud.seen[key]
+	cloud.setCount(key, cloud.tags.Count(key)+data.tags[tag])
+	if times, ok := data.seen[tag]; ok {
+		cloud.mergeSeen(key, before, existed, times)
+	}
+	if weight := data.extraWeights[tag]; weight != 0 {
+		cloud.addExtraWeight(key, weight)
+	}
+	if cloud.surfaceForms {
+		for surface, count := range data.surfaces[tag] {
+			cloud.addSurface(key, surface, count)
+		}
+	}
+}
+
+// mergePair adds a copied pair count if both tags are in the cloud
+func (cloud *TagCloud) mergePair(pair tagPair, count int, stemmed bool) {
+	first, ok := cloud.copiedKey(pair.first, stemmed)
+	second, ok2 := cloud.copiedKey(pair.second, stemmed)
+	if ok && ok2 && first != second && cloud.tags.Count(first) > 0 && cloud.tags.Count(second) > 0 {
+		cloud.addPair(first, second, count)
+	}
+}
+
+// MergeAll returns a new cloud with summed counts of all clouds, nil clouds are skipped
+func MergeAll(clouds ...*TagCloud) *TagCloud {
+	result := New()
+	for _, cloud := range clouds {
+		result.MergeInto(cloud)
+	}
+	return result
+}
@@ -0,0 +1,93 @@
+package tagcloud_test
+
+import (
+	"lecture02_homework/tagcloud"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	t.Run("ok, overlapping tags", func(t *testing.T) {
+		first := tagcloud.New()
+		first.AddTagN("a", 2)
+		first.AddTagN("b", 1)
+		second := tagcloud.New()
+		second.AddTagN("a", 3)
+		second.AddTagN("b", 4)
+
+		merged := first.Merge(second)
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "a", OccurrenceCount: 5}, {Tag: "b", OccurrenceCount: 5}}, merged.TopN(10))
+		assert.Equal(t, 10, merged.Total())
+		assert.Equal(t, 3, first.Total())
+		assert.Equal(t, 7, second.Total())
+	})
+
+	t.Run("ok, disjoint tags", func(t *testing.T) {
+		first := tagcloud.New()
+		first.AddTagN("a", 2)
+		second := tagcloud.New()
+		second.AddTagN("b", 1)
+
+		merged := first.Merge(second)
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "a", OccurrenceCount: 2}, {Tag: "b", OccurrenceCount: 1}}, merged.TopN(10))
+	})
+
+	t.Run("ok, empty and nil clouds", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTags("a", "b", "a")
+
+		assert.Equal(t, tc.TopN(10), tc.Merge(tagcloud.New()).TopN(10))
+		assert.Equal(t, tc.TopN(10), tc.Merge(nil).TopN(10))
+		assert.Equal(t, tc.TopN(10), tagcloud.New().Merge(tc).TopN(10))
+	})
+
+	t.Run("ok, result doesn't alias merged clouds", func(t *testing.T) {
+		first := tagcloud.New()
+		first.AddTag("a")
+		second := tagcloud.New()
+		second.AddTag("b")
+
+		merged := first.Merge(second)
+		merged.AddTag("a")
+		merged.DeleteTag("b")
+
+		assert.Equal(t, 1, first.Count("a"))
+		assert.Equal(t, 1, second.Count("b"))
+	})
+}
+
+func TestMergeInto(t *testing.T) {
+	tc := tagcloud.New()
+	tc.AddTagN("a", 2)
+	other := tagcloud.New()
+	other.AddTagN("a", 1)
+	other.AddTagN("b", 1)
+
+	tc.MergeInto(other)
+	tc.MergeInto(nil)
+	other.AddTag("b")
+
+	assert.Equal(t, []tagcloud.TagStat{{Tag: "a", OccurrenceCount: 3}, {Tag: "b", OccurrenceCount: 1}}, tc.TopN(10))
+	assert.Equal(t, 4, tc.Total())
+}
+
+func TestMergeAll(t *testing.T) {
+	first := tagcloud.New()
+	first.AddTags("a", "b")
+	second := tagcloud.New()
+	second.AddTags("b", "c")
+	third := tagcloud.New()
+	third.AddTags("c", "c")
+
+	merged := tagcloud.MergeAll(first, nil, second, third)
+
+	assert.Equal(t, []tagcloud.TagStat{
+		{Tag: "c", OccurrenceCount: 3},
+		{Tag: "b", OccurrenceCount: 2},
+		{Tag: "a", OccurrenceCount: 1},
+	}, merged.TopN(10))
+	assert.Equal(t, 0, tagcloud.MergeAll().Len())
+}
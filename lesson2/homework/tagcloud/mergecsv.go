@@ -0,0 +1,135 @@
+package tagcloud
+
+import (
+	"container/heap"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"unsafe"
+)
+
+// WriteMergedCSV writes the tags of both clouds as CSV with word,count header, counts of a tag present in both
+// clouds are summed and tags are ordered by the combined count descending and then by tag, as TopN of a merged cloud.
+// keys are combined as is, without normalization by the other cloud, nil cloud is treated as an empty one.
+//
+// no merged cloud is built: tags of both clouds are walked together in TopN order, the combined count
+// of a read tag is looked up in the other cloud and the tag waits in a buffer until no unread tag can outrank it,
+// i.e. its count exceeds the sum of the next unread counts of both clouds. an ordered cloud is walked along
+// its ordering, any other cloud sorts all its tags once and caches them like for TopNFrom, so only the buffer
+// is allocated for the write, it holds tags with combined counts in the region where both clouds overlap,
+// ties of the boundary count included. both clouds are read locked until the output is written,
+// always in the same order, so concurrent calls with swapped clouds don't deadlock
+func WriteMergedCSV(w io.Writer, a, b *TagCloud) error {
+	if a == nil {
+		a = New()
+	}
+	if b == nil {
+		b = New()
+	}
+	first, second := a, b
+	if uintptr(unsafe.Pointer(b)) < uintptr(unsafe.Pointer(a)) {
+		first, second = b, a
+	}
+	first.rlock()
+	defer first.runlock()
+	if second != first {
+		second.rlock()
+		defer second.runlock()
+	}
+	writer := csv.NewWriter(w)
+	_ = writer.Write([]string{"word", "count"})
+	merged := mergedWalk{a: newSortedWalk(a), b: newSortedWalk(b), pending: &statHeap{compare: func(x, y TagStat) int {
+		// statHeap keeps the worst stat on top, so the order is reversed to pop the best one
+		return -compareTagStats(x, y)
+	}}}
+	merged.walk(func(stat TagStat) {
+		_ = writer.Write([]string{stat.Tag, strconv.Itoa(stat.OccurrenceCount)})
+	})
+	writer.Flush()
+	return writer.Error()
+}
+
+// sortedWalk is a cursor over the tags of a locked cloud in TopN order,
+// it follows the skip list of an ordered cloud and the sorted stats of any other cloud
+type sortedWalk struct {
+	cloud   *TagCloud
+	node    *skipNode
+	stats   []TagStat
+	compare func(a, b TagStat) int
+}
+
+func newSortedWalk(cloud *TagCloud) *sortedWalk {
+	if cloud.walksOrdering() {
+		return &sortedWalk{cloud: cloud, node: cloud.ordered.head.next[0], compare: compareTagStats}
+	}
+	return &sortedWalk{cloud: cloud, stats: cloud.sortedStats(), compare: cloud.compare()}
+}
+
+// done reports whether all tags are read
+func (s *sortedWalk) done() bool {
+	return s.node == nil && len(s.stats) == 0
+}
+
+// peek returns the next unread stat, the walk must not be done
+func (s *sortedWalk) peek() TagStat {
+	if s.node != nil {
+		return s.node.stat
+	}
+	return s.stats[0]
+}
+
+// advance marks the next stat as read
+func (s *sortedWalk) advance() {
+	if s.node != nil {
+		s.node = s.node.next[0]
+		return
+	}
+	s.stats = s.stats[1:]
+}
+
+// nextCount is the largest count of unread tags, zero when all tags are read
+func (s *sortedWalk) nextCount() int {
+	if s.done() {
+		return 0
+	}
+	return s.peek().OccurrenceCount
+}
+
+// passed reports whether the tag of the cloud is already read
+func (s *sortedWalk) passed(tag string) bool {
+	count, ok := s.cloud.tags.get(tag)
+	if !ok {
+		return false
+	}
+	return s.done() || s.compare(TagStat{Tag: tag, OccurrenceCount: count}, s.peek()) < 0
+}
+
+// mergedWalk reads tags of two clouds in the order of the combined count, every tag is taken
+// when it's read from the first cloud having it, the other cloud has not passed it by then
+type mergedWalk struct {
+	a, b    *sortedWalk
+	pending *statHeap
+}
+
+func (m *mergedWalk) walk(yield func(TagStat)) {
+	for !m.a.done() || !m.b.done() {
+		from, other := m.a, m.b
+		if m.a.done() || m.b.nextCount() > m.a.nextCount() {
+			from, other = m.b, m.a
+		}
+		stat := from.peek()
+		from.advance()
+		if !other.passed(stat.Tag) {
+			stat.OccurrenceCount += other.cloud.tags.Count(stat.Tag)
+			heap.Push(m.pending, TagStat{Tag: stat.Tag, OccurrenceCount: stat.OccurrenceCount})
+		}
+		// an unread tag has at most the next counts of both clouds
+		bound := m.a.nextCount() + m.b.nextCount()
+		for m.pending.Len() > 0 && m.pending.stats[0].OccurrenceCount > bound {
+			yield(popStat(m.pending))
+		}
+	}
+	for m.pending.Len() > 0 {
+		yield(popStat(m.pending))
+	}
+}
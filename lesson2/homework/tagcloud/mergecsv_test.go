@@ -0,0 +1,117 @@
+package tagcloud_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"lecture02_homework/tagcloud"
+	"math/rand"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// naiveMergedCSV writes TopN of the merged cloud
+func naiveMergedCSV(t *testing.T, a, b *tagcloud.TagCloud) string {
+	merged := a.Merge(b)
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	assert.NoError(t, writer.Write([]string{"word", "count"}))
+	for _, stat := range merged.TopN(merged.Len()) {
+		assert.NoError(t, writer.Write([]string{stat.Tag, strconv.Itoa(stat.OccurrenceCount)}))
+	}
+	writer.Flush()
+	return buf.String()
+}
+
+func TestWriteMergedCSV(t *testing.T) {
+	t.Run("ok, same as merged cloud", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(1))
+		for _, tags := range []int{1, 10, 100, 2000} {
+			a, b := tagcloud.New(), tagcloud.NewOrdered()
+			for i := 0; i < tags; i++ {
+				// few distinct counts and overlapping tags make many ties of combined counts
+				a.AddTagN(fmt.Sprintf("t%d", rnd.Intn(tags)), rnd.Intn(5)+1)
+				b.AddTagN(fmt.Sprintf("t%d", rnd.Intn(tags)), rnd.Intn(5)+1)
+			}
+			var buf bytes.Buffer
+
+			err := tagcloud.WriteMergedCSV(&buf, a, b)
+
+			assert.NoError(t, err)
+			assert.Equal(t, naiveMergedCSV(t, a, b), buf.String(), "%d tags", tags)
+		}
+	})
+
+	t.Run("ok, skewed clouds", func(t *testing.T) {
+		a, b := tagcloud.New(), tagcloud.New()
+		a.AddTagN("go", 100)
+		a.AddTagN("rust", 1)
+		b.AddTagN("rust", 99)
+		b.AddTagN("c", 50)
+		b.AddTagN("go", 1)
+		var buf bytes.Buffer
+
+		err := tagcloud.WriteMergedCSV(&buf, a, b)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "word,count\ngo,101\nrust,100\nc,50\n", buf.String())
+	})
+
+	t.Run("ok, same cloud twice", func(t *testing.T) {
+		tc := tagcloud.NewConcurrent()
+		tc.AddTags("go", "go", "rust", "c")
+		var buf bytes.Buffer
+
+		err := tagcloud.WriteMergedCSV(&buf, tc, tc)
+
+		assert.NoError(t, err)
+		assert.Equal(t, naiveMergedCSV(t, tc, tc), buf.String())
+	})
+
+	t.Run("ok, swapped clouds written concurrently", func(t *testing.T) {
+		a, b := tagcloud.NewConcurrent(), tagcloud.NewConcurrent()
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 200; j++ {
+					a.AddTag("go")
+					b.AddTag("rust")
+					if i%2 == 0 {
+						assert.NoError(t, tagcloud.WriteMergedCSV(io.Discard, a, b))
+					} else {
+						assert.NoError(t, tagcloud.WriteMergedCSV(io.Discard, b, a))
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 800, a.Count("go")+b.Count("go"))
+	})
+
+	t.Run("ok, nil and empty clouds", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag("go")
+		var buf bytes.Buffer
+
+		assert.NoError(t, tagcloud.WriteMergedCSV(&buf, nil, tc))
+		assert.NoError(t, tagcloud.WriteMergedCSV(&buf, tagcloud.New(), nil))
+
+		assert.Equal(t, "word,count\ngo,1\nword,count\n", buf.String())
+	})
+
+	t.Run("error, failed writer", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag("go")
+
+		err := tagcloud.WriteMergedCSV(failingWriter{}, tc, tc)
+
+		assert.Error(t, err)
+	})
+}
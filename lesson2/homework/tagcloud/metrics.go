@@ -0,0 +1,80 @@
+package tagcloud
+
+import "expvar"
+
+// MetricsTopN is the number of most frequent tags reported by Metrics
+const MetricsTopN = 10
+
+// MetricsTag is a tag reported by Metrics
+type MetricsTag struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// Metrics is a consistent snapshot of cloud size and its most frequent tags
+type Metrics struct {
+	Distinct int          `json:"distinct"`
+	Total    int          `json:"total"`
+	Top      []MetricsTag `json:"top"`
+}
+
+// Metrics returns the number of distinct tags, the total count and MetricsTopN most frequent tags
+// taken under a single read lock
+func (cloud *TagCloud) Metrics() Metrics {
+	cloud.rlock()
+	defer cloud.runlock()
+	metrics := Metrics{Distinct: cloud.tags.Len(), Total: cloud.tags.Total(), Top: []MetricsTag{}}
+	for _, stat := range cloud.topStats(MetricsTopN, 0) {
+		metrics.Top = append(metrics.Top, MetricsTag{Tag: stat.Tag, Count: stat.OccurrenceCount})
+	}
+	return metrics
+}
+
+// ExpvarFunc returns a function publishing Metrics, register it with expvar.Publish to see the cloud
+// on /debug/vars
+func (cloud *TagCloud) ExpvarFunc() expvar.Func {
+	return func() any {
+		return cloud.Metrics()
+	}
+}
+
+// Sample is a single metric value, Tag is set only for per tag metrics
+type Sample struct {
+	Name  string
+	Tag   string
+	Value float64
+}
+
+// Collector exposes metrics as plain samples, so a Prometheus collector can be built on top of it
+// without this package depending on the client library
+type Collector interface {
+	Collect() []Sample
+}
+
+const (
+	MetricDistinctTags   = "tagcloud_distinct_tags"
+	MetricOccurrences    = "tagcloud_occurrences_total"
+	MetricTopOccurrences = "tagcloud_top_tag_occurrences"
+)
+
+type cloudCollector struct {
+	cloud *TagCloud
+}
+
+// Collector returns a Collector reporting Metrics of the cloud on every Collect call
+func (cloud *TagCloud) Collector() Collector {
+	return cloudCollector{cloud: cloud}
+}
+
+func (c cloudCollector) Collect() []Sample {
+	metrics := c.cloud.Metrics()
+	samples := make([]Sample, 0, len(metrics.Top)+2)
+	samples = append(samples,
+		Sample{Name: MetricDistinctTags, Value: float64(metrics.Distinct)},
+		Sample{Name: MetricOccurrences, Value: float64(metrics.Total)},
+	)
+	for _, stat := range metrics.Top {
+		samples = append(samples, Sample{Name: MetricTopOccurrences, Tag: stat.Tag, Value: float64(stat.Count)})
+	}
+	return samples
+}
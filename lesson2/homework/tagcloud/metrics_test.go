@@ -0,0 +1,97 @@
+package tagcloud_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"lecture02_homework/tagcloud"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpvarFunc(t *testing.T) {
+	t.Run("ok, published JSON", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("go", 3)
+		tc.AddTag("rust")
+		expvar.Publish("tagcloud_test_cloud", tc.ExpvarFunc())
+
+		var published struct {
+			Distinct int `json:"distinct"`
+			Total    int `json:"total"`
+			Top      []struct {
+				Tag   string `json:"tag"`
+				Count int    `json:"count"`
+			} `json:"top"`
+		}
+		err := json.Unmarshal([]byte(expvar.Get("tagcloud_test_cloud").String()), &published)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, published.Distinct)
+		assert.Equal(t, 4, published.Total)
+		require.Len(t, published.Top, 2)
+		assert.Equal(t, "go", published.Top[0].Tag)
+		assert.Equal(t, 3, published.Top[0].Count)
+	})
+
+	t.Run("ok, empty cloud", func(t *testing.T) {
+		data, err := json.Marshal(tagcloud.New().ExpvarFunc().Value())
+
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"distinct": 0, "total": 0, "top": []}`, string(data))
+	})
+
+	t.Run("ok, top is capped", func(t *testing.T) {
+		tc := tagcloud.New()
+		for i := 0; i < 20; i++ {
+			tc.AddTag(fmt.Sprint("tag", i))
+		}
+
+		metrics := tc.Metrics()
+
+		assert.Equal(t, 20, metrics.Distinct)
+		assert.Len(t, metrics.Top, tagcloud.MetricsTopN)
+	})
+}
+
+func TestCollector(t *testing.T) {
+	t.Run("ok, samples", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("go", 2)
+		tc.AddTag("rust")
+
+		assert.Equal(t, []tagcloud.Sample{
+			{Name: tagcloud.MetricDistinctTags, Value: 2},
+			{Name: tagcloud.MetricOccurrences, Value: 3},
+			{Name: tagcloud.MetricTopOccurrences, Tag: "go", Value: 2},
+			{Name: tagcloud.MetricTopOccurrences, Tag: "rust", Value: 1},
+		}, tc.Collector().Collect())
+	})
+
+	t.Run("ok, consistent with concurrent writers", func(t *testing.T) {
+		tc := tagcloud.NewConcurrent()
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 1000; j++ {
+					tc.AddTag(fmt.Sprint("tag", j%50))
+				}
+			}()
+		}
+		for i := 0; i < 100; i++ {
+			metrics := tc.Metrics()
+			sum := 0
+			for _, stat := range metrics.Top {
+				sum += stat.Count
+			}
+			assert.LessOrEqual(t, sum, metrics.Total)
+			assert.LessOrEqual(t, len(metrics.Top), metrics.Distinct)
+		}
+		wg.Wait()
+	})
+}
@@ -0,0 +1,74 @@
+package tagcloud
+
+import (
+	"slices"
+	"strings"
+)
+
+// DefaultNamespace holds tags without a namespace, see WithNamespace
+const DefaultNamespace = ""
+
+// WithNamespace makes the cloud track tags like "lang:go" with separator ":" per namespace as well, see ByNamespace.
+// a tag is split at the first separator, tags without it or with an empty part belong to DefaultNamespace as is
+func WithNamespace(sep string) Option {
+	return func(cloud *TagCloud) {
+		cloud.separator = sep
+	}
+}
+
+// ByNamespace returns a new cloud with tags of the namespace without the namespace prefix,
+// e.g. "go" for "lang:go". an unknown namespace or a cloud without WithNamespace gives an empty cloud
+func (cloud *TagCloud) ByNamespace(namespace string) *TagCloud {
+	cloud.rlock()
+	tags := make(map[string]int, len(cloud.namespaces[namespace]))
+	for name, count := range cloud.namespaces[namespace] {
+		tags[name] = count
+	}
+	cloud.runlock()
+	result := New()
+	result.replaceTags(tags)
+	return result
+}
+
+// Namespaces returns sorted namespaces having at least one tag
+func (cloud *TagCloud) Namespaces() []string {
+	cloud.rlock()
+	defer cloud.runlock()
+	namespaces := make([]string, 0, len(cloud.namespaces))
+	for namespace := range cloud.namespaces {
+		namespaces = append(namespaces, namespace)
+	}
+	slices.Sort(namespaces)
+	return namespaces
+}
+
+// splitNamespace returns the namespace of the tag and the tag without it
+func (cloud *TagCloud) splitNamespace(tag string) (string, string) {
+	namespace, name, ok := strings.Cut(tag, cloud.separator)
+	if !ok || namespace == "" || name == "" {
+		return DefaultNamespace, tag
+	}
+	return namespace, name
+}
+
+// setNamespaceCount mirrors the count change in the tag's namespace
+func (cloud *TagCloud) setNamespaceCount(tag string, count int) {
+	if cloud.separator == "" {
+		return
+	}
+	namespace, name := cloud.splitNamespace(tag)
+	if count == 0 {
+		delete(cloud.namespaces[namespace], name)
+		if len(cloud.namespaces[namespace]) == 0 {
+			delete(cloud.namespaces, namespace)
+		}
+		return
+	}
+	if cloud.namespaces == nil {
+		cloud.namespaces = map[string]map[string]int{}
+	}
+	if cloud.namespaces[namespace] == nil {
+		cloud.namespaces[namespace] = map[string]int{}
+	}
+	cloud.namespaces[namespace][name] = count
+}
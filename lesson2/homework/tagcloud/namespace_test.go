@@ -0,0 +1,92 @@
+package tagcloud_test
+
+import (
+	"lecture02_homework/tagcloud"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestByNamespace(t *testing.T) {
+	t.Run("ok, counts in parent and namespace", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{
+			"lang:go": 3, "lang:rust": 1, "topic:http": 2, "untagged": 1,
+		}, tagcloud.WithNamespace(":"))
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "go", OccurrenceCount: 3}, {Tag: "rust", OccurrenceCount: 1}}, tc.ByNamespace("lang").TopN(10))
+		assert.Equal(t, 4, tc.ByNamespace("lang").Total())
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "untagged", OccurrenceCount: 1}}, tc.ByNamespace(tagcloud.DefaultNamespace).TopN(10))
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "lang:go", OccurrenceCount: 3},
+			{Tag: "topic:http", OccurrenceCount: 2},
+			{Tag: "lang:rust", OccurrenceCount: 1},
+			{Tag: "untagged", OccurrenceCount: 1},
+		}, tc.TopN(10))
+	})
+
+	t.Run("ok, follows removals", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{
+			"lang:go": 3, "lang:rust": 1, "topic:http": 2, "untagged": 1,
+		}, tagcloud.WithNamespace(":"))
+		tc.RemoveTag("lang:go")
+		tc.DeleteTag("lang:rust")
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "go", OccurrenceCount: 2}}, tc.ByNamespace("lang").TopN(10))
+
+		tc.DeleteTag("lang:go")
+
+		assert.Equal(t, []string{"", "topic"}, tc.Namespaces())
+	})
+
+	t.Run("ok, namespace view is a copy", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{
+			"lang:go": 3, "lang:rust": 1, "topic:http": 2, "untagged": 1,
+		}, tagcloud.WithNamespace(":"))
+
+		tc.ByNamespace("lang").AddTag("go")
+
+		assert.Equal(t, 3, tc.ByNamespace("lang").Count("go"))
+	})
+
+	t.Run("ok, unknown namespace gives an empty cloud", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{
+			"lang:go": 3, "lang:rust": 1, "topic:http": 2, "untagged": 1,
+		}, tagcloud.WithNamespace(":"))
+		sub := tc.ByNamespace("unknown")
+
+		assert.NotNil(t, sub)
+		assert.Equal(t, 0, sub.Len())
+		assert.NotNil(t, tagcloud.New().ByNamespace("lang"))
+	})
+
+	t.Run("ok, empty parts belong to the default namespace", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithNamespace(":"))
+		tc.AddTags(":go", "lang:", "a:b:c")
+
+		assert.Equal(t, []string{":go", "lang:"}, tc.ByNamespace(tagcloud.DefaultNamespace).TagsWithCount(1))
+		assert.Equal(t, []string{"b:c"}, tc.ByNamespace("a").TagsWithCount(1))
+	})
+
+	t.Run("ok, loaded and cloned clouds", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"lang:go": 2, "topic:http": 1}, tagcloud.WithNamespace(":"))
+
+		assert.Equal(t, 2, tc.ByNamespace("lang").Count("go"))
+		assert.Equal(t, 1, tc.Clone().ByNamespace("topic").Count("http"))
+	})
+}
+
+func TestNamespaces(t *testing.T) {
+	t.Run("ok, sorted namespaces", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{
+			"lang:go": 3, "lang:rust": 1, "topic:http": 2, "untagged": 1,
+		}, tagcloud.WithNamespace(":"))
+		assert.Equal(t, []string{"", "lang", "topic"}, tc.Namespaces())
+	})
+
+	t.Run("ok, cloud without namespaces", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag("lang:go")
+
+		assert.Empty(t, tc.Namespaces())
+	})
+}
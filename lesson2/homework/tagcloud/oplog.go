@@ -0,0 +1,285 @@
+package tagcloud
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+)
+
+// OpKind is the kind of a change recorded with WithOpLog
+type OpKind uint8
+
+const (
+	// OpAdd adds N occurrences of the tag
+	OpAdd OpKind = iota + 1
+	// OpRemove removes N occurrences of the tag
+	OpRemove
+	// OpDelete removes the tag regardless of its count
+	OpDelete
+	// OpReset removes all tags, it's recorded when tags are replaced at once, e.g. by Reset or decoding
+	OpReset
+)
+
+var opNames = map[OpKind]string{OpAdd: "add", OpRemove: "remove", OpDelete: "delete", OpReset: "reset"}
+
+func (kind OpKind) String() string {
+	if name, ok := opNames[kind]; ok {
+		return name
+	}
+	return fmt.Sprintf("OpKind(%d)", kind)
+}
+
+// Op is a single change of a cloud, tags are normalized by the recording cloud
+type Op struct {
+	Kind OpKind
+	Tag  string
+	N    int
+}
+
+// opLogMagic starts binary encoded operations, opLogVersion is changed on every format change
+const (
+	opLogMagic   = "TGOP"
+	opLogVersion = 1
+)
+
+// OpLog is a sequence of changes returned by Flush, it can be encoded to be applied by another process
+type OpLog []Op
+
+// WithOpLog makes the cloud record every change of counts, see Flush and Apply.
+// additions, removals, merges, eviction and expiration are all recorded as changes of single tags
+func WithOpLog() Option {
+	return func(cloud *TagCloud) {
+		cloud.opLog = true
+	}
+}
+
+// Flush returns changes recorded since the previous Flush and forgets them.
+// applying them to a copy of the cloud taken at the previous Flush gives a cloud equal to the receiver
+func (cloud *TagCloud) Flush() []Op {
+	cloud.lock()
+	defer cloud.unlock()
+	ops := cloud.ops
+	cloud.ops = nil
+	if ops == nil {
+		return []Op{}
+	}
+	return ops
+}
+
+// Apply replays operations recorded by another cloud, tags are normalized by the receiver.
+// invalid operations are reported before anything is applied.
+// a receiver which limits or expires tags may diverge from the recording cloud
+func (cloud *TagCloud) Apply(ops []Op) error {
+	for i, op := range ops {
+		if err := op.validate(); err != nil {
+			return fmt.Errorf("op %d: %w", i, err)
+		}
+	}
+	cloud.lock()
+	defer cloud.unlock()
+	for _, op := range ops {
+		if op.Kind == OpReset {
+			cloud.replaceTags(map[string]int{})
+			continue
+		}
+		key, ok := cloud.key(op.Tag)
+		if !ok {
+			continue
+		}
+		switch op.Kind {
+		case OpAdd:
+			cloud.setCount(key, cloud.tags.Count(key)+op.N)
+		case OpRemove:
+			if count, ok := cloud.tags.get(key); ok {
+				cloud.setCount(key, count-op.N)
+			}
+		case OpDelete:
+			if _, ok := cloud.tags.get(key); ok {
+				cloud.setCount(key, 0)
+			}
+		}
+	}
+	return nil
+}
+
+func (op Op) validate() error {
+	switch op.Kind {
+	case OpAdd, OpRemove:
+		if op.N <= 0 {
+			return fmt.Errorf("%s of %q has non-positive count %d", op.Kind, op.Tag, op.N)
+		}
+	case OpDelete, OpReset:
+	default:
+		return fmt.Errorf("unknown op kind %d", op.Kind)
+	}
+	return nil
+}
+
+// recordOp records the change of the tag count made by setCount
+func (cloud *TagCloud) recordOp(tag string, old, count int) {
+	if !cloud.opLog {
+		return
+	}
+	switch {
+	case count > old:
+		cloud.ops = append(cloud.ops, Op{Kind: OpAdd, Tag: tag, N: count - old})
+	case count == 0 && old > 0:
+		cloud.ops = append(cloud.ops, Op{Kind: OpDelete, Tag: tag})
+	case count < old:
+		cloud.ops = append(cloud.ops, Op{Kind: OpRemove, Tag: tag, N: old - count})
+	}
+}
+
+// recordReplace records tags replaced by replaceTags as a reset followed by additions sorted by tag
+func (cloud *TagCloud) recordReplace(tags map[string]int) {
+	if !cloud.opLog {
+		return
+	}
+	if cloud.tags.Len() > 0 {
+		cloud.ops = append(cloud.ops, Op{Kind: OpReset})
+	}
+	start := len(cloud.ops)
+	for tag, count := range tags {
+		cloud.ops = append(cloud.ops, Op{Kind: OpAdd, Tag: tag, N: count})
+	}
+	slices.SortFunc(cloud.ops[start:], func(a, b Op) int {
+		return cmp.Compare(a.Tag, b.Tag)
+	})
+}
+
+// jsonOp is the serialized form of an operation, tag and n are omitted when they aren't used
+type jsonOp struct {
+	Op  string `json:"op"`
+	Tag string `json:"tag,omitempty"`
+	N   int    `json:"n,omitempty"`
+}
+
+// MarshalJSON encodes the operations as an array of {"op": ..., "tag": ..., "n": ...} objects
+func (log OpLog) MarshalJSON() ([]byte, error) {
+	ops := make([]jsonOp, len(log))
+	for i, op := range log {
+		if err := op.validate(); err != nil {
+			return nil, fmt.Errorf("op %d: %w", i, err)
+		}
+		ops[i] = jsonOp{Op: op.Kind.String(), Tag: op.Tag, N: op.N}
+	}
+	return json.Marshal(ops)
+}
+
+// UnmarshalJSON decodes operations encoded by MarshalJSON, unknown and invalid operations are rejected
+func (log *OpLog) UnmarshalJSON(data []byte) error {
+	var ops []jsonOp
+	if err := json.Unmarshal(data, &ops); err != nil {
+		return fmt.Errorf("invalid op log JSON: %w", err)
+	}
+	result := make(OpLog, len(ops))
+	for i, encoded := range ops {
+		op := Op{Tag: encoded.Tag, N: encoded.N}
+		for kind, name := range opNames {
+			if name == encoded.Op {
+				op.Kind = kind
+			}
+		}
+		if op.Kind == 0 {
+			return fmt.Errorf("op %d: unknown op %q", i, encoded.Op)
+		}
+		if err := op.validate(); err != nil {
+			return fmt.Errorf("op %d: %w", i, err)
+		}
+		result[i] = op
+	}
+	*log = result
+	return nil
+}
+
+// MarshalBinary encodes the operations as magic header, version byte, number of operations
+// and then every operation as kind byte, uvarint tag length, tag bytes and uvarint n for additions and removals
+func (log OpLog) MarshalBinary() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteString(opLogMagic)
+	buf.WriteByte(opLogVersion)
+	buf.Write(binary.AppendUvarint(nil, uint64(len(log))))
+	for i, op := range log {
+		if err := op.validate(); err != nil {
+			return nil, fmt.Errorf("op %d: %w", i, err)
+		}
+		buf.WriteByte(byte(op.Kind))
+		buf.Write(binary.AppendUvarint(nil, uint64(len(op.Tag))))
+		buf.WriteString(op.Tag)
+		if op.Kind == OpAdd || op.Kind == OpRemove {
+			buf.Write(binary.AppendUvarint(nil, uint64(op.N)))
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes operations encoded by MarshalBinary
+func (log *OpLog) UnmarshalBinary(data []byte) error {
+	reader := bufio.NewReader(bytes.NewReader(data))
+	header := make([]byte, len(opLogMagic)+1)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return opLogError(err)
+	}
+	if !bytes.Equal(header[:len(opLogMagic)], []byte(opLogMagic)) {
+		return fmt.Errorf("not an op log: unknown header %q", header[:len(opLogMagic)])
+	}
+	if version := header[len(opLogMagic)]; version != opLogVersion {
+		return fmt.Errorf("unsupported op log format version %d", version)
+	}
+	size, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return opLogError(err)
+	}
+	var result OpLog
+	for i := uint64(0); i < size; i++ {
+		kind, err := reader.ReadByte()
+		if err != nil {
+			return opLogError(err)
+		}
+		length, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return opLogError(err)
+		}
+		if length > maxBinaryTag {
+			return fmt.Errorf("op %d: tag is too long: %d bytes", i, length)
+		}
+		tag := make([]byte, length)
+		if _, err := io.ReadFull(reader, tag); err != nil {
+			return opLogError(err)
+		}
+		op := Op{Kind: OpKind(kind), Tag: string(tag)}
+		if op.Kind == OpAdd || op.Kind == OpRemove {
+			n, err := binary.ReadUvarint(reader)
+			if err != nil {
+				return opLogError(err)
+			}
+			if n > math.MaxInt {
+				return fmt.Errorf("op %d: invalid count %d", i, n)
+			}
+			op.N = int(n)
+		}
+		if err := op.validate(); err != nil {
+			return fmt.Errorf("op %d: %w", i, err)
+		}
+		result = append(result, op)
+	}
+	if result == nil {
+		result = OpLog{}
+	}
+	*log = result
+	return nil
+}
+
+func opLogError(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("op log is truncated: %w", io.ErrUnexpectedEOF)
+	}
+	return err
+}
@@ -0,0 +1,154 @@
+package tagcloud_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"lecture02_homework/tagcloud"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpLog(t *testing.T) {
+	t.Run("ok, records changes", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithOpLog(), tagcloud.WithCaseInsensitive())
+		tc.AddTag("Go")
+		tc.AddTagN("rust", 3)
+		tc.RemoveTag("RUST")
+		tc.DeleteTag("go")
+		tc.DeleteTag("absent")
+		tc.RemoveTag("absent")
+
+		assert.Equal(t, []tagcloud.Op{
+			{Kind: tagcloud.OpAdd, Tag: "go", N: 1},
+			{Kind: tagcloud.OpAdd, Tag: "rust", N: 3},
+			{Kind: tagcloud.OpRemove, Tag: "rust", N: 1},
+			{Kind: tagcloud.OpDelete, Tag: "go"},
+		}, tc.Flush())
+		assert.Equal(t, []tagcloud.Op{}, tc.Flush())
+	})
+
+	t.Run("ok, eviction and replacement", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithOpLog(), tagcloud.WithMaxTags(1))
+		tc.AddTag("go")
+		tc.AddTag("rust")
+		require.NoError(t, tc.UnmarshalText([]byte("c\t2\nzig\t1\n")))
+
+		assert.Equal(t, []tagcloud.Op{
+			{Kind: tagcloud.OpAdd, Tag: "go", N: 1},
+			{Kind: tagcloud.OpDelete, Tag: "go"},
+			{Kind: tagcloud.OpAdd, Tag: "rust", N: 2},
+			{Kind: tagcloud.OpReset},
+			{Kind: tagcloud.OpAdd, Tag: "c", N: 2},
+			{Kind: tagcloud.OpAdd, Tag: "zig", N: 1},
+			{Kind: tagcloud.OpDelete, Tag: "zig"},
+		}, tc.Flush())
+	})
+
+	t.Run("ok, without option", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag("go")
+
+		assert.Equal(t, []tagcloud.Op{}, tc.Flush())
+	})
+
+	t.Run("error, invalid ops aren't applied", func(t *testing.T) {
+		tc := tagcloud.New()
+
+		err := tc.Apply([]tagcloud.Op{{Kind: tagcloud.OpAdd, Tag: "go", N: 1}, {Kind: tagcloud.OpRemove, Tag: "go"}})
+		assert.EqualError(t, err, `op 1: remove of "go" has non-positive count 0`)
+		assert.EqualError(t, tc.Apply([]tagcloud.Op{{Kind: 9, Tag: "go"}}), "op 0: unknown op kind 9")
+		assert.Zero(t, tc.Len())
+	})
+}
+
+func TestOpLogReplication(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	source := tagcloud.New(tagcloud.WithOpLog(), tagcloud.WithMaxTags(40))
+	viaJSON := tagcloud.New()
+	viaBinary := tagcloud.New()
+	tag := func() string {
+		return fmt.Sprintf("tag-%d", rnd.Intn(60))
+	}
+
+	for checkpoint := 0; checkpoint < 20; checkpoint++ {
+		for i := 0; i < 200; i++ {
+			switch rnd.Intn(10) {
+			case 0:
+				source.RemoveTag(tag())
+			case 1:
+				source.DeleteTag(tag())
+			case 2:
+				other := tagcloud.New()
+				other.AddTagN(tag(), rnd.Intn(5)+1)
+				source.MergeInto(other)
+			case 3:
+				source.AddTagN(tag(), rnd.Intn(10)+1)
+			default:
+				source.AddTag(tag())
+			}
+		}
+		if checkpoint == 10 {
+			source.Reset()
+			source.AddTag("after reset")
+		}
+		ops := tagcloud.OpLog(source.Flush())
+
+		data, err := json.Marshal(ops)
+		require.NoError(t, err)
+		var decoded tagcloud.OpLog
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		require.NoError(t, viaJSON.Apply(decoded))
+
+		data, err = ops.MarshalBinary()
+		require.NoError(t, err)
+		decoded = nil
+		require.NoError(t, decoded.UnmarshalBinary(data))
+		require.NoError(t, viaBinary.Apply(decoded))
+
+		assert.True(t, source.Equal(viaJSON), "checkpoint %d: %v", checkpoint, source.Diff(viaJSON))
+		assert.True(t, source.Equal(viaBinary), "checkpoint %d: %v", checkpoint, source.Diff(viaBinary))
+	}
+}
+
+func TestOpLogEncoding(t *testing.T) {
+	ops := tagcloud.OpLog{
+		{Kind: tagcloud.OpAdd, Tag: "go", N: 3},
+		{Kind: tagcloud.OpRemove, Tag: "машина", N: 1},
+		{Kind: tagcloud.OpDelete, Tag: "go"},
+		{Kind: tagcloud.OpReset},
+	}
+
+	t.Run("ok, json", func(t *testing.T) {
+		data, err := json.Marshal(ops)
+		require.NoError(t, err)
+		assert.Equal(t, `[{"op":"add","tag":"go","n":3},{"op":"remove","tag":"машина","n":1},{"op":"delete","tag":"go"},{"op":"reset"}]`, string(data))
+
+		var decoded tagcloud.OpLog
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, ops, decoded)
+	})
+
+	t.Run("ok, binary", func(t *testing.T) {
+		data, err := ops.MarshalBinary()
+		require.NoError(t, err)
+		assert.Equal(t, "TGOP\x01\x04\x01\x02go\x03\x02\x0cмашина\x01\x03\x02go\x04\x00", string(data))
+
+		var decoded tagcloud.OpLog
+		require.NoError(t, decoded.UnmarshalBinary(data))
+		assert.Equal(t, ops, decoded)
+	})
+
+	t.Run("error, invalid data", func(t *testing.T) {
+		var decoded tagcloud.OpLog
+
+		assert.EqualError(t, json.Unmarshal([]byte(`[{"op":"rename","tag":"go"}]`), &decoded), `op 0: unknown op "rename"`)
+		assert.EqualError(t, json.Unmarshal([]byte(`[{"op":"add","tag":"go"}]`), &decoded), `op 0: add of "go" has non-positive count 0`)
+		assert.EqualError(t, decoded.UnmarshalBinary([]byte("TGOP\x01\x01\x01\x02go")), "op log is truncated: unexpected EOF")
+		assert.EqualError(t, decoded.UnmarshalBinary([]byte("TGOP\x02\x00")), "unsupported op log format version 2")
+		_, err := tagcloud.OpLog{{Kind: tagcloud.OpAdd, Tag: "go"}}.MarshalBinary()
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,66 @@
+package tagcloud
+
+import "strings"
+
+// Option configures a TagCloud created by New, NewConcurrent or NewOrdered
+type Option func(*TagCloud)
+
+// WithNormalizer adds a function applied to every tag before it's counted or looked up.
+// normalizers run in the order the options are passed and should give the same result when applied twice,
+// since merged tags are normalized by the receiving cloud again
+func WithNormalizer(normalizer func(string) string) Option {
+	return func(cloud *TagCloud) {
+		cloud.normalizers = append(cloud.normalizers, normalizer)
+	}
+}
+
+// WithCaseInsensitive makes tags differing only in case the same tag, TopN returns them in lower case
+func WithCaseInsensitive() Option {
+	return WithNormalizer(strings.ToLower)
+}
+
+// WithStopWords makes the cloud silently drop the words, they are compared after normalization
+func WithStopWords(words ...string) Option {
+	return func(cloud *TagCloud) {
+		cloud.stopWords = append(cloud.stopWords, words...)
+	}
+}
+
+// newTagCloud applies options to an empty cloud
+func newTagCloud(options []Option) *TagCloud {
+	cloud := &TagCloud{}
+	for _, option := range options {
+		option(cloud)
+	}
+	if len(cloud.stopWords) > 0 {
+		stopWords := cloud.stopWords
+		cloud.stopWords = nil
+		cloud.stop = make(map[string]struct{}, len(stopWords))
+		for _, word := range stopWords {
+			cloud.stop[cloud.normalize(word)] = struct{}{}
+		}
+	}
+	return cloud
+}
+
+// normalize cleans and stems the tag and resolves its alias
+func (cloud *TagCloud) normalize(tag string) string {
+	return cloud.resolve(cloud.stem(cloud.clean(tag)))
+}
+
+// clean applies Unicode normalization and normalizers and trims surrounding whitespace
+func (cloud *TagCloud) clean(tag string) string {
+	if cloud.unicodeNormalization {
+		tag = cloud.unicodeForm.String(tag)
+	}
+	for _, normalizer := range cloud.normalizers {
+		tag = normalizer(tag)
+	}
+	return strings.TrimSpace(tag)
+}
+
+// key returns the normalized tag, false means the tag must be ignored
+func (cloud *TagCloud) key(tag string) (string, bool) {
+	tag, err := cloud.checkTag(tag)
+	return tag, err == nil
+}
@@ -0,0 +1,94 @@
+package tagcloud_test
+
+import (
+	"lecture02_homework/tagcloud"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptions(t *testing.T) {
+	trimPunctuation := func(tag string) string {
+		return strings.TrimFunc(tag, unicode.IsPunct)
+	}
+
+	t.Run("ok, no options", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTags("Go", "go", "GO", "the")
+
+		assert.Equal(t, 4, tc.Len())
+		assert.Equal(t, 1, tc.Count("the"))
+	})
+
+	t.Run("ok, case insensitive", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithCaseInsensitive())
+		tc.AddTags("Go", "go", "GO", "Rust")
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "go", OccurrenceCount: 3}, {Tag: "rust", OccurrenceCount: 1}}, tc.TopN(10))
+		assert.Equal(t, 3, tc.Count("gO"))
+		assert.True(t, tc.Contains("RUST"))
+
+		tc.RemoveTag("GO")
+		assert.True(t, tc.DeleteTag("rUST"))
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "go", OccurrenceCount: 2}}, tc.TopN(10))
+	})
+
+	t.Run("ok, stop words", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithStopWords("the", "a"))
+		tc.AddTags("the", "a", "go", "The")
+		tc.AddTagN("the", 5)
+		tc.AddFromMap(map[string]int{"a": 2})
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "The", OccurrenceCount: 1}, {Tag: "go", OccurrenceCount: 1}}, tc.TopN(10))
+		assert.False(t, tc.Contains("the"))
+		assert.Equal(t, 2, tc.Total())
+	})
+
+	t.Run("ok, custom normalizer", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithNormalizer(trimPunctuation))
+		tc.AddTags("go!", "(go)", "go", "...", "rust,")
+
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "go", OccurrenceCount: 3},
+			{Tag: "rust", OccurrenceCount: 1},
+		}, tc.TopN(10))
+	})
+
+	t.Run("ok, combined options", func(t *testing.T) {
+		options := []tagcloud.Option{
+			tagcloud.WithStopWords("THE"),
+			tagcloud.WithNormalizer(trimPunctuation),
+			tagcloud.WithCaseInsensitive(),
+		}
+		for _, tc := range []tagcloud.Counter{
+			tagcloud.New(options...),
+			tagcloud.NewConcurrent(options...),
+			tagcloud.NewOrdered(options...),
+			tagcloud.NewSharded(4, options...),
+		} {
+			for _, tag := range []string{"Go!", "go", "The", "the.", "RUST", "rust?"} {
+				tc.AddTag(tag)
+			}
+			tc.AddTagN("\"GO\"", 2)
+
+			assert.Equal(t, []tagcloud.TagStat{{Tag: "go", OccurrenceCount: 4}, {Tag: "rust", OccurrenceCount: 2}}, tc.TopN(10))
+			assert.Equal(t, 4, tc.Count("GO!"))
+		}
+	})
+
+	t.Run("ok, clone and merge keep options", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithCaseInsensitive(), tagcloud.WithStopWords("a"))
+		tc.AddTag("Go")
+		clone := tc.Clone()
+		clone.AddTags("GO", "A")
+
+		other := tagcloud.New()
+		other.AddTags("GO", "A")
+		tc.MergeInto(other)
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "go", OccurrenceCount: 2}}, clone.TopN(10))
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "go", OccurrenceCount: 2}}, tc.TopN(10))
+	})
+}
@@ -0,0 +1,127 @@
+package tagcloud
+
+import "math/bits"
+
+// maxSkipLevel is enough for far more tags than fit in memory with the level probability of 1/4
+const maxSkipLevel = 32
+
+// NewOrdered creates a TagCloud which keeps tags sorted in TopN order on every change.
+// changes cost O(log n) instead of O(1), but TopN, TopNWithMin and TopNFrom only walk
+// the requested tags instead of scanning the whole cloud.
+// the cloud isn't safe for concurrent use, same as the one created by New
+func NewOrdered(options ...Option) *TagCloud {
+	cloud := newTagCloud(options)
+	cloud.ordered = newSkipList()
+	return cloud
+}
+
+// walksOrdering reports whether the skip list gives TopN order, it doesn't know about recency of tags
+func (cloud *TagCloud) walksOrdering() bool {
+	return cloud.ordered != nil && !cloud.recencyTieBreak
+}
+
+// skipList keeps stats sorted by compareTagStats
+type skipList struct {
+	head  skipNode
+	level int
+	// state of xorshift generator for node levels
+	state uint64
+}
+
+type skipNode struct {
+	stat TagStat
+	next []*skipNode
+}
+
+func newSkipList() *skipList {
+	return &skipList{head: skipNode{next: make([]*skipNode, maxSkipLevel)}, level: 1, state: 0x9E3779B97F4A7C15}
+}
+
+func (l *skipList) randomLevel() int {
+	l.state ^= l.state << 13
+	l.state ^= l.state >> 7
+	l.state ^= l.state << 17
+	level := 1 + bits.TrailingZeros64(l.state)/2
+	if level > maxSkipLevel {
+		level = maxSkipLevel
+	}
+	return level
+}
+
+// path fills update with the last node before stat on every level
+func (l *skipList) path(stat TagStat, update *[maxSkipLevel]*skipNode) {
+	node := &l.head
+	for i := l.level - 1; i >= 0; i-- {
+		for node.next[i] != nil && compareTagStats(node.next[i].stat, stat) < 0 {
+			node = node.next[i]
+		}
+		update[i] = node
+	}
+}
+
+func (l *skipList) Insert(stat TagStat) {
+	l.insert(&skipNode{stat: stat, next: make([]*skipNode, l.randomLevel())})
+}
+
+func (l *skipList) Remove(stat TagStat) {
+	l.remove(stat)
+}
+
+// Update moves the stat to its new place reusing its node, so changing a count doesn't allocate
+func (l *skipList) Update(old, stat TagStat) {
+	node := l.remove(old)
+	if node == nil {
+		l.Insert(stat)
+		return
+	}
+	node.stat = stat
+	l.insert(node)
+}
+
+func (l *skipList) insert(node *skipNode) {
+	var update [maxSkipLevel]*skipNode
+	l.path(node.stat, &update)
+	level := len(node.next)
+	for ; l.level < level; l.level++ {
+		update[l.level] = &l.head
+	}
+	for i := 0; i < level; i++ {
+		node.next[i] = update[i].next[i]
+		update[i].next[i] = node
+	}
+}
+
+// remove unlinks the node of the stat and returns it, nil means the stat isn't in the list
+func (l *skipList) remove(stat TagStat) *skipNode {
+	var update [maxSkipLevel]*skipNode
+	l.path(stat, &update)
+	node := update[0].next[0]
+	if node == nil || node.stat != stat {
+		return nil
+	}
+	for i := 0; i < len(node.next); i++ {
+		update[i].next[i] = node.next[i]
+	}
+	for l.level > 1 && l.head.next[l.level-1] == nil {
+		l.level--
+	}
+	return node
+}
+
+// Top returns n stats starting at offset, stats with count less than minCount are not returned
+func (l *skipList) Top(offset, n, minCount int) []TagStat {
+	return l.AppendTop([]TagStat{}, offset, n, minCount)
+}
+
+// AppendTop works as Top appending the stats to dst
+func (l *skipList) AppendTop(dst []TagStat, offset, n, minCount int) []TagStat {
+	node := l.head.next[0]
+	for ; node != nil && offset > 0; offset-- {
+		node = node.next[0]
+	}
+	for ; node != nil && n > 0 && node.stat.OccurrenceCount >= minCount; node = node.next[0] {
+		dst = append(dst, node.stat)
+		n--
+	}
+	return dst
+}
@@ -0,0 +1,104 @@
+package tagcloud_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"lecture02_homework/tagcloud"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderedTagCloud(t *testing.T) {
+	t.Run("ok, same results as basic cloud", func(t *testing.T) {
+		basic, ordered := tagcloud.New(), tagcloud.NewOrdered()
+		rnd := rand.New(rand.NewSource(3))
+		for i := 0; i < 20000; i++ {
+			tag := fmt.Sprintf("t%d", rnd.Intn(300))
+			op, n := rnd.Intn(10), rnd.Intn(5)
+			for _, cloud := range []*tagcloud.TagCloud{basic, ordered} {
+				switch op {
+				case 0, 1:
+					cloud.RemoveTag(tag)
+				case 2:
+					cloud.AddTagN(tag, n)
+				case 3:
+					if n == 0 {
+						cloud.DeleteTag(tag)
+					}
+				default:
+					cloud.AddTag(tag)
+				}
+			}
+			if i%1000 == 0 {
+				assert.Equal(t, basic.TopN(20), ordered.TopN(20), "TopN(20) after operation %d", i)
+			}
+		}
+
+		for _, n := range []int{-1, 0, 1, 50, 1000} {
+			assert.Equal(t, basic.TopN(n), ordered.TopN(n), "TopN(%d)", n)
+			assert.Equal(t, basic.TopNWithMin(n, 10), ordered.TopNWithMin(n, 10), "TopNWithMin(%d, 10)", n)
+			assert.Equal(t, basic.BottomN(n), ordered.BottomN(n), "BottomN(%d)", n)
+			assert.Equal(t, basic.TopNFrom(-1, n), ordered.TopNFrom(-1, n), "TopNFrom(-1, %d)", n)
+			assert.Equal(t, basic.TopNFrom(7, n), ordered.TopNFrom(7, n), "TopNFrom(7, %d)", n)
+		}
+		assert.Equal(t, basic.TopNFrom(10000, 5), ordered.TopNFrom(10000, 5))
+		assert.Equal(t, basic.Total(), ordered.Total())
+	})
+
+	t.Run("ok, clone keeps ordering", func(t *testing.T) {
+		tc := tagcloud.NewOrdered()
+		tc.AddTags("a", "b", "b")
+		clone := tc.Clone()
+
+		clone.AddTagN("a", 5)
+		tc.AddTag("c")
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "a", OccurrenceCount: 6}, {Tag: "b", OccurrenceCount: 2}}, clone.TopN(10))
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "b", OccurrenceCount: 2},
+			{Tag: "a", OccurrenceCount: 1},
+			{Tag: "c", OccurrenceCount: 1},
+		}, tc.TopN(10))
+	})
+
+	t.Run("ok, replaced tags are ordered", func(t *testing.T) {
+		tc := tagcloud.NewOrdered()
+		tc.AddTag("old")
+
+		assert.NoError(t, json.Unmarshal([]byte(`[{"tag":"a","count":1},{"tag":"b","count":2}]`), tc))
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "b", OccurrenceCount: 2}, {Tag: "a", OccurrenceCount: 1}}, tc.TopN(10))
+
+		tc.Reset()
+		assert.Empty(t, tc.TopN(10))
+		tc.AddTag("c")
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "c", OccurrenceCount: 1}}, tc.TopN(10))
+	})
+}
+
+func BenchmarkMixedWorkload(b *testing.B) {
+	constructors := map[string]func(...tagcloud.Option) *tagcloud.TagCloud{
+		"basic":   tagcloud.New,
+		"ordered": tagcloud.NewOrdered,
+	}
+	for _, name := range []string{"basic", "ordered"} {
+		b.Run(name, func(b *testing.B) {
+			tc := constructors[name]()
+			rnd := rand.New(rand.NewSource(1))
+			tags := make([]string, 100000)
+			for i := range tags {
+				tags[i] = fmt.Sprintf("tag-%d", i)
+				tc.AddTagN(tags[i], rnd.Intn(100)+1)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				// a dashboard query after every hundred new tags
+				for j := 0; j < 100; j++ {
+					tc.AddTag(tags[rnd.Intn(len(tags))])
+				}
+				tc.TopN(50)
+			}
+		})
+	}
+}
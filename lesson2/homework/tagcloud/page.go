@@ -0,0 +1,44 @@
+package tagcloud
+
+// TopNFrom returns n tags starting at offset in TopN order, so pages can be requested one by one.
+// negative offset is treated as zero, offset past the end and n <= 0 give an empty slice.
+// the sorted tags are cached until the cloud is changed, so successive pages don't sort again,
+// an ordered cloud walks its ordering instead
+func (cloud *TagCloud) TopNFrom(offset, n int) []TagStat {
+	cloud.rlock()
+	defer cloud.runlock()
+	if offset < 0 {
+		offset = 0
+	}
+	var page []TagStat
+	if cloud.walksOrdering() {
+		page = cloud.ordered.Top(offset, n, 0)
+	} else {
+		sorted := cloud.sortedStats()
+		offset = min(offset, len(sorted))
+		n = max(0, min(n, len(sorted)-offset))
+		page = append([]TagStat{}, sorted[offset:offset+n]...)
+	}
+	cloud.fillErrorBounds(page)
+	cloud.fillDisplayForms(page)
+	return page
+}
+
+// cachedStats returns stats sorted by sortedStats or nil if they were not sorted since the last change,
+// the result is shared and must not be changed
+func (cloud *TagCloud) cachedStats() []TagStat {
+	cloud.sortedMu.Lock()
+	defer cloud.sortedMu.Unlock()
+	return cloud.sorted
+}
+
+// sortedStats returns all stats in TopN order, the result is shared and must not be changed
+func (cloud *TagCloud) sortedStats() []TagStat {
+	cloud.sortedMu.Lock()
+	defer cloud.sortedMu.Unlock()
+	if cloud.sorted == nil {
+		cloud.sorted = cloud.selectStats(cloud.tags.Len(), 0, cloud.compare())
+		cloud.fillErrorBounds(cloud.sorted)
+	}
+	return cloud.sorted
+}
@@ -0,0 +1,86 @@
+package tagcloud_test
+
+import (
+	"fmt"
+	"lecture02_homework/tagcloud"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopNFrom(t *testing.T) {
+	newCloud := func() *tagcloud.TagCloud {
+		tc := tagcloud.New()
+		for i := 0; i < 23; i++ {
+			tc.AddTagN(fmt.Sprintf("t%02d", i), i%5+1)
+		}
+		return tc
+	}
+
+	t.Run("ok, pages concatenate to TopN", func(t *testing.T) {
+		tc := newCloud()
+		for _, size := range []int{1, 4, 5, 23, 100} {
+			var pages []tagcloud.TagStat
+			for offset := 0; offset < tc.Len(); offset += size {
+				page := tc.TopNFrom(offset, size)
+				assert.LessOrEqual(t, len(page), size)
+				pages = append(pages, page...)
+			}
+
+			assert.Equal(t, tc.TopN(tc.Len()), pages, "page size %d", size)
+		}
+	})
+
+	t.Run("ok, offset bounds", func(t *testing.T) {
+		tc := newCloud()
+
+		assert.Equal(t, tc.TopN(3), tc.TopNFrom(-5, 3))
+		assert.Equal(t, tc.TopN(tc.Len())[22:], tc.TopNFrom(22, 10))
+		assert.Empty(t, tc.TopNFrom(23, 10))
+		assert.NotNil(t, tc.TopNFrom(100, 10))
+		assert.Empty(t, tc.TopNFrom(0, 0))
+		assert.Empty(t, tc.TopNFrom(0, -1))
+		assert.Empty(t, tagcloud.New().TopNFrom(0, 10))
+	})
+
+	t.Run("ok, cache is invalidated on change", func(t *testing.T) {
+		tc := newCloud()
+		assert.Equal(t, "t04", tc.TopNFrom(0, 1)[0].Tag)
+
+		tc.AddTagN("t00", 10)
+		assert.Equal(t, tagcloud.TagStat{Tag: "t00", OccurrenceCount: 11}, tc.TopNFrom(0, 1)[0])
+
+		tc.DeleteTag("t00")
+		assert.Equal(t, "t04", tc.TopNFrom(0, 1)[0].Tag)
+
+		tc.Reset()
+		assert.Empty(t, tc.TopNFrom(0, 1))
+	})
+
+	t.Run("ok, result doesn't share the cache", func(t *testing.T) {
+		tc := newCloud()
+		page := tc.TopNFrom(0, 2)
+		page[0].Tag = "changed"
+
+		assert.NotEqual(t, "changed", tc.TopNFrom(0, 2)[0].Tag)
+	})
+
+	t.Run("ok, concurrent pages", func(t *testing.T) {
+		tc := tagcloud.NewConcurrent()
+		var wg sync.WaitGroup
+		for w := 0; w < 10; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				for i := 0; i < 100; i++ {
+					tc.AddTag(fmt.Sprintf("t%d", (w+i)%7))
+					tc.TopNFrom(i%5, 3)
+				}
+			}(w)
+		}
+		wg.Wait()
+
+		assert.Len(t, tc.TopNFrom(0, 10), 7)
+	})
+}
@@ -0,0 +1,92 @@
+package tagcloud
+
+// WithHighWaterMarks makes the cloud remember the largest count every tag ever had, see Peak.
+// the peak of a removed tag is retired and kept until DropPeaks, it's restored when the tag is added again.
+// Save, MarshalJSON and MarshalText of the cloud include peaks, retired peaks are written with zero count
+func WithHighWaterMarks() Option {
+	return func(cloud *TagCloud) {
+		cloud.highWaterMarks = true
+	}
+}
+
+// Peak returns the largest count the tag had, removed tags included, or 0 for an unknown tag
+// and a cloud without WithHighWaterMarks
+func (cloud *TagCloud) Peak(tag string) int {
+	tag = cloud.normalize(tag)
+	cloud.rlock()
+	defer cloud.runlock()
+	return cloud.peakOf(tag)
+}
+
+// DropPeaks forgets peaks of removed tags, peaks of tags in the cloud are kept
+func (cloud *TagCloud) DropPeaks() {
+	cloud.lock()
+	defer cloud.unlock()
+	cloud.retiredPeaks = nil
+}
+
+// updatePeak raises the peak of the tag to its new count, a tag removed from the cloud retires its peak
+func (cloud *TagCloud) updatePeak(tag string, count int) {
+	if !cloud.highWaterMarks {
+		return
+	}
+	if count == 0 {
+		if peak, ok := cloud.peaks[tag]; ok {
+			if cloud.retiredPeaks == nil {
+				cloud.retiredPeaks = map[string]int{}
+			}
+			cloud.retiredPeaks[tag] = peak
+			delete(cloud.peaks, tag)
+		}
+		return
+	}
+	peak, ok := cloud.peaks[tag]
+	if !ok {
+		peak = cloud.retiredPeaks[tag]
+		delete(cloud.retiredPeaks, tag)
+	}
+	if cloud.peaks == nil {
+		cloud.peaks = map[string]int{}
+	}
+	cloud.peaks[tag] = max(peak, count)
+}
+
+// restorePeaks sets decoded peaks of a cloud with WithHighWaterMarks after its tags are replaced,
+// peaks of absent tags are retired
+func (cloud *TagCloud) restorePeaks(peaks map[string]int) {
+	for tag, peak := range peaks {
+		cloud.updatePeak(tag, peak)
+		if _, ok := cloud.tags.get(tag); !ok {
+			cloud.updatePeak(tag, 0)
+		}
+	}
+}
+
+// peakOf returns the peak of a tag in the cloud or a retired one
+func (cloud *TagCloud) peakOf(tag string) int {
+	if peak, ok := cloud.peaks[tag]; ok {
+		return peak
+	}
+	return cloud.retiredPeaks[tag]
+}
+
+// retiredStats returns retired peaks as stats with zero count for encoders
+func (cloud *TagCloud) retiredStats() []TagStatFull {
+	stats := make([]TagStatFull, 0, len(cloud.retiredPeaks))
+	for tag, peak := range cloud.retiredPeaks {
+		stats = append(stats, TagStatFull{TagStat: TagStat{Tag: tag}, Peak: peak})
+	}
+	return stats
+}
+
+// copyCounts copies a map of counts by tag, nil stays nil
+func copyCounts(peaks map[string]int) map[string]int {
+	if peaks == nil {
+		return nil
+	}
+	copied := make(map[string]int, len(peaks))
+	for tag, peak := range peaks {
+		copied[tag] = peak
+	}
+	return copied
+}
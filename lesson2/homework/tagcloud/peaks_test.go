@@ -0,0 +1,177 @@
+package tagcloud_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"lecture02_homework/tagcloud"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithHighWaterMarks(t *testing.T) {
+	t.Run("ok, peaks never decrease", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(1))
+		tc := tagcloud.New(tagcloud.WithHighWaterMarks())
+		peaks := map[string]int{}
+		for i := 0; i < 5000; i++ {
+			tag := fmt.Sprintf("t%d", rnd.Intn(20))
+			switch rnd.Intn(4) {
+			case 0:
+				tc.RemoveTag(tag)
+			case 1:
+				tc.DeleteTag(tag)
+			default:
+				tc.AddTagN(tag, rnd.Intn(3)+1)
+			}
+			peak := tc.Peak(tag)
+
+			assert.GreaterOrEqual(t, peak, peaks[tag], tag)
+			assert.GreaterOrEqual(t, peak, tc.Count(tag), tag)
+			peaks[tag] = peak
+		}
+	})
+
+	t.Run("ok, removed tag keeps its peak", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithHighWaterMarks(), tagcloud.WithCaseInsensitive())
+		tc.AddTagN("go", 5)
+		tc.RemoveTag("go")
+
+		assert.Equal(t, 5, tc.Peak("GO"))
+		assert.True(t, tc.DeleteTag("go"))
+		assert.Equal(t, 5, tc.Peak("go"))
+
+		tc.AddTagN("go", 2)
+		assert.Equal(t, 5, tc.Peak("go"))
+		tc.AddTagN("go", 4)
+		assert.Equal(t, 6, tc.Peak("go"))
+		assert.Equal(t, []tagcloud.TagStatFull{{TagStat: tagcloud.TagStat{Tag: "go", OccurrenceCount: 6}, Peak: 6}}, tc.TopNFull(1))
+	})
+
+	t.Run("ok, DropPeaks forgets removed tags only", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithHighWaterMarks())
+		tc.AddTagN("go", 3)
+		tc.AddTagN("rust", 2)
+		tc.RemoveTag("rust")
+		tc.DeleteTag("go")
+
+		tc.DropPeaks()
+
+		assert.Zero(t, tc.Peak("go"))
+		assert.Equal(t, 2, tc.Peak("rust"))
+	})
+
+	t.Run("ok, clone keeps peaks", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithHighWaterMarks())
+		tc.AddTagN("go", 3)
+		tc.AddTagN("rust", 2)
+		tc.DeleteTag("go")
+		tc.RemoveTag("rust")
+
+		clone := tc.Clone()
+
+		assert.Equal(t, 3, clone.Peak("go"))
+		assert.Equal(t, 2, clone.Peak("rust"))
+	})
+
+	t.Run("ok, no peaks without option", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("go", 3)
+
+		assert.Zero(t, tc.Peak("go"))
+		assert.Zero(t, tc.TopNFull(1)[0].Peak)
+	})
+}
+
+func TestHighWaterMarksEncoding(t *testing.T) {
+	newCloud := func() *tagcloud.TagCloud {
+		tc := tagcloud.New(tagcloud.WithHighWaterMarks())
+		tc.AddTagN("go", 5)
+		tc.RemoveTag("go")
+		tc.AddTagN("rust", 2)
+		tc.DeleteTag("rust")
+		tc.AddTag("c")
+		return tc
+	}
+	assertPeaks := func(t *testing.T, tc *tagcloud.TagCloud) {
+		assert.Equal(t, map[string]int{"go": 4, "c": 1}, tc.ToMap())
+		assert.Equal(t, 5, tc.Total())
+		assert.Equal(t, 5, tc.Peak("go"))
+		assert.Equal(t, 2, tc.Peak("rust"))
+		assert.Equal(t, 1, tc.Peak("c"))
+	}
+
+	t.Run("ok, text", func(t *testing.T) {
+		text, err := newCloud().MarshalText()
+		require.NoError(t, err)
+		assert.Equal(t, "c\t1\t1\ngo\t4\t5\nrust\t0\t2\n", string(text))
+
+		decoded := tagcloud.New(tagcloud.WithHighWaterMarks())
+		require.NoError(t, decoded.UnmarshalText(text))
+
+		assertPeaks(t, decoded)
+	})
+
+	t.Run("ok, JSON", func(t *testing.T) {
+		data, err := json.Marshal(newCloud())
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"tag":"c","count":1,"peak":1},{"tag":"go","count":4,"peak":5},{"tag":"rust","count":0,"peak":2}]`, string(data))
+
+		decoded := tagcloud.New(tagcloud.WithHighWaterMarks())
+		require.NoError(t, json.Unmarshal(data, decoded))
+
+		assertPeaks(t, decoded)
+	})
+
+	t.Run("ok, binary", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, newCloud().Save(&buf))
+
+		decoded, err := tagcloud.Load(&buf)
+
+		require.NoError(t, err)
+		assertPeaks(t, decoded)
+	})
+
+	t.Run("ok, binary with timestamps", func(t *testing.T) {
+		clock := func() time.Time { return time.Unix(1700000000, 0) }
+		tc := tagcloud.New(tagcloud.WithHighWaterMarks(), tagcloud.WithTimestamps(clock))
+		tc.AddTagN("go", 3)
+		tc.RemoveTag("go")
+		data, err := tc.MarshalBinary()
+		require.NoError(t, err)
+
+		decoded := tagcloud.New(tagcloud.WithHighWaterMarks(), tagcloud.WithTimestamps(nil))
+		require.NoError(t, decoded.UnmarshalBinary(data))
+
+		assert.Equal(t, tc.TopNFull(1), decoded.TopNFull(1))
+		assert.Equal(t, 3, decoded.Peak("go"))
+	})
+
+	t.Run("ok, peaks are dropped by a cloud without option", func(t *testing.T) {
+		text, err := newCloud().MarshalText()
+		require.NoError(t, err)
+		decoded := tagcloud.New()
+
+		require.NoError(t, decoded.UnmarshalText(text))
+
+		assert.Equal(t, map[string]int{"go": 4, "c": 1}, decoded.ToMap())
+		assert.Zero(t, decoded.Peak("go"))
+	})
+
+	t.Run("error, retired tag without peak", func(t *testing.T) {
+		decoded := tagcloud.New(tagcloud.WithHighWaterMarks())
+
+		assert.EqualError(t, decoded.UnmarshalText([]byte("go\t0\t0\n")), `line 1: invalid count "0" of tag "go"`)
+		assert.EqualError(t, decoded.UnmarshalText([]byte("go\t1\t1\ngo\t0\t2\n")), `line 2: duplicate tag "go"`)
+		assert.Error(t, json.Unmarshal([]byte(`[{"tag":"go","count":0}]`), decoded))
+		_, err := tagcloud.Load(bytes.NewReader([]byte("TGCL\x03\x02\x01\x02go\x00\x00")))
+		assert.EqualError(t, err, `tag "go" has invalid peak 0`)
+		_, err = tagcloud.Load(bytes.NewReader([]byte("TGCL\x03\x04\x00")))
+		assert.EqualError(t, err, "unknown tag cloud format flags 0x4")
+	})
+}
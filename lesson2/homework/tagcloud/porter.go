@@ -0,0 +1,262 @@
+package tagcloud
+
+// porterStem returns the stem of a lower case English word by the Porter stemming algorithm,
+// see https://tartarus.org/martin/PorterStemmer/def.txt. words with other than a-z letters
+// and words of at most two letters are returned as is
+func porterStem(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return word
+		}
+	}
+	p := &porter{b: []byte(word), k: len(word) - 1}
+	p.step1ab()
+	if p.k > 0 {
+		p.step1c()
+		p.step2()
+		p.step3()
+		p.step4()
+		p.step5()
+	}
+	return string(p.b[:p.k+1])
+}
+
+// porter is the word being stemmed, b[:k+1] is the current stem and j marks the end of the stem
+// without the suffix matched by the last successful ends
+type porter struct {
+	b    []byte
+	k, j int
+}
+
+// cons reports whether b[i] is a consonant, y is a consonant at the start or after a vowel
+func (p *porter) cons(i int) bool {
+	switch p.b[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		return i == 0 || !p.cons(i-1)
+	}
+	return true
+}
+
+// m returns the number of vowel-consonant sequences in b[:j+1]
+func (p *porter) m() int {
+	n, i := 0, 0
+	for ; i <= p.j && p.cons(i); i++ {
+	}
+	for i <= p.j {
+		for ; i <= p.j && !p.cons(i); i++ {
+		}
+		if i > p.j {
+			break
+		}
+		n++
+		for ; i <= p.j && p.cons(i); i++ {
+		}
+	}
+	return n
+}
+
+// vowelInStem reports whether b[:j+1] has a vowel
+func (p *porter) vowelInStem() bool {
+	for i := 0; i <= p.j; i++ {
+		if !p.cons(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// doubleCons reports whether b[i-1:i+1] is a double consonant
+func (p *porter) doubleCons(i int) bool {
+	return i >= 1 && p.b[i] == p.b[i-1] && p.cons(i)
+}
+
+// cvc reports whether b[i-2:i+1] is consonant-vowel-consonant and the last consonant isn't w, x or y,
+// e.g. hop but not snow
+func (p *porter) cvc(i int) bool {
+	if i < 2 || !p.cons(i) || p.cons(i-1) || !p.cons(i-2) {
+		return false
+	}
+	return p.b[i] != 'w' && p.b[i] != 'x' && p.b[i] != 'y'
+}
+
+// ends reports whether the stem ends with s and sets j to the end of the stem without it
+func (p *porter) ends(s string) bool {
+	if len(s) > p.k+1 || string(p.b[p.k+1-len(s):p.k+1]) != s {
+		return false
+	}
+	p.j = p.k - len(s)
+	return true
+}
+
+// setTo replaces the suffix after j with s
+func (p *porter) setTo(s string) {
+	p.b = append(p.b[:p.j+1], s...)
+	p.k = p.j + len(s)
+}
+
+// replace replaces the suffix after j with s if the rest of the stem has a vowel-consonant sequence
+func (p *porter) replace(s string) {
+	if p.m() > 0 {
+		p.setTo(s)
+	}
+}
+
+// replaceFirst replaces the first matching suffix of the pairs of suffix and replacement
+func (p *porter) replaceFirst(pairs ...string) {
+	for i := 0; i < len(pairs); i += 2 {
+		if p.ends(pairs[i]) {
+			p.replace(pairs[i+1])
+			return
+		}
+	}
+}
+
+// step1ab removes plurals and -ed or -ing, e.g. caresses -> caress, ponies -> poni, hopping -> hop
+func (p *porter) step1ab() {
+	if p.b[p.k] == 's' {
+		switch {
+		case p.ends("sses"):
+			p.k -= 2
+		case p.ends("ies"):
+			p.setTo("i")
+		case p.b[p.k-1] != 's':
+			p.k--
+		}
+	}
+	if p.ends("eed") {
+		if p.m() > 0 {
+			p.k--
+		}
+		return
+	}
+	if !(p.ends("ed") || p.ends("ing")) || !p.vowelInStem() {
+		return
+	}
+	p.k = p.j
+	switch {
+	case p.ends("at"):
+		p.setTo("ate")
+	case p.ends("bl"):
+		p.setTo("ble")
+	case p.ends("iz"):
+		p.setTo("ize")
+	case p.doubleCons(p.k):
+		if c := p.b[p.k]; c != 'l' && c != 's' && c != 'z' {
+			p.k--
+		}
+	case p.m() == 1 && p.cvc(p.k):
+		p.setTo("e")
+	}
+}
+
+// step1c turns terminal y to i when there is another vowel in the stem
+func (p *porter) step1c() {
+	if p.ends("y") && p.vowelInStem() {
+		p.b[p.k] = 'i'
+	}
+}
+
+// step2 maps double suffixes to single ones, e.g. -ization -> -ize
+func (p *porter) step2() {
+	switch p.b[p.k-1] {
+	case 'a':
+		p.replaceFirst("ational", "ate", "tional", "tion")
+	case 'c':
+		p.replaceFirst("enci", "ence", "anci", "ance")
+	case 'e':
+		p.replaceFirst("izer", "ize")
+	case 'l':
+		p.replaceFirst("bli", "ble", "alli", "al", "entli", "ent", "eli", "e", "ousli", "ous")
+	case 'o':
+		p.replaceFirst("ization", "ize", "ation", "ate", "ator", "ate")
+	case 's':
+		p.replaceFirst("alism", "al", "iveness", "ive", "fulness", "ful", "ousness", "ous")
+	case 't':
+		p.replaceFirst("aliti", "al", "iviti", "ive", "biliti", "ble")
+	case 'g':
+		p.replaceFirst("logi", "log")
+	}
+}
+
+// step3 handles -ic-, -full, -ness etc.
+func (p *porter) step3() {
+	switch p.b[p.k] {
+	case 'e':
+		p.replaceFirst("icate", "ic", "ative", "", "alize", "al")
+	case 'i':
+		p.replaceFirst("iciti", "ic")
+	case 'l':
+		p.replaceFirst("ical", "ic", "ful", "")
+	case 's':
+		p.replaceFirst("ness", "")
+	}
+}
+
+// step4 removes -ant, -ence etc. when the stem has more than one vowel-consonant sequence
+func (p *porter) step4() {
+	var suffixes []string
+	switch p.b[p.k-1] {
+	case 'a':
+		suffixes = []string{"al"}
+	case 'c':
+		suffixes = []string{"ance", "ence"}
+	case 'e':
+		suffixes = []string{"er"}
+	case 'i':
+		suffixes = []string{"ic"}
+	case 'l':
+		suffixes = []string{"able", "ible"}
+	case 'n':
+		suffixes = []string{"ant", "ement", "ment", "ent"}
+	case 'o':
+		if p.ends("ion") && p.j >= 0 && (p.b[p.j] == 's' || p.b[p.j] == 't') {
+			break
+		}
+		suffixes = []string{"ou"}
+	case 's':
+		suffixes = []string{"ism"}
+	case 't':
+		suffixes = []string{"ate", "iti"}
+	case 'u':
+		suffixes = []string{"ous"}
+	case 'v':
+		suffixes = []string{"ive"}
+	case 'z':
+		suffixes = []string{"ize"}
+	default:
+		return
+	}
+	if suffixes != nil {
+		found := false
+		for _, suffix := range suffixes {
+			if p.ends(suffix) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return
+		}
+	}
+	if p.m() > 1 {
+		p.k = p.j
+	}
+}
+
+// step5 removes a final -e and turns -ll to -l when the stem has more than one vowel-consonant sequence
+func (p *porter) step5() {
+	p.j = p.k
+	if p.b[p.k] == 'e' {
+		if a := p.m(); a > 1 || a == 1 && !p.cvc(p.k-1) {
+			p.k--
+		}
+	}
+	if p.b[p.k] == 'l' && p.doubleCons(p.k) && p.m() > 1 {
+		p.k--
+	}
+}
@@ -0,0 +1,43 @@
+package tagcloud
+
+import "slices"
+
+// WithAutoPrune makes the cloud call Prune(minCount) after every everyN additions of tags,
+// e.g. AddTagN is a single addition whatever its n is. non-positive everyN disables pruning
+func WithAutoPrune(minCount, everyN int) Option {
+	return func(cloud *TagCloud) {
+		cloud.pruneMin = minCount
+		cloud.pruneEvery = everyN
+	}
+}
+
+// Prune removes every tag with count less than minCount and returns the removed tags in TopN order,
+// so they can be archived before they are forgotten
+func (cloud *TagCloud) Prune(minCount int) []TagStat {
+	cloud.lock()
+	defer cloud.unlock()
+	return cloud.prune(minCount)
+}
+
+func (cloud *TagCloud) prune(minCount int) []TagStat {
+	removed := []TagStat{}
+	for tag, count := range cloud.tags.All() {
+		if count < minCount {
+			removed = append(removed, TagStat{Tag: tag, OccurrenceCount: count, ErrorBound: cloud.errorBounds[tag]})
+		}
+	}
+	slices.SortFunc(removed, cloud.compare())
+	for _, stat := range removed {
+		cloud.setCount(stat.Tag, 0)
+	}
+	return removed
+}
+
+// pruneIfNeeded prunes the cloud configured with WithAutoPrune after enough additions
+func (cloud *TagCloud) pruneIfNeeded() {
+	if cloud.pruneEvery <= 0 || cloud.additions < cloud.pruneEvery {
+		return
+	}
+	cloud.additions = 0
+	cloud.prune(cloud.pruneMin)
+}
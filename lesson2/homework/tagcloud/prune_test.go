@@ -0,0 +1,98 @@
+package tagcloud_test
+
+import (
+	"fmt"
+	"lecture02_homework/tagcloud"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrune(t *testing.T) {
+	for name, newCloud := range map[string]func(...tagcloud.Option) *tagcloud.TagCloud{
+		"new":        tagcloud.New,
+		"ordered":    tagcloud.NewOrdered,
+		"concurrent": tagcloud.NewConcurrent,
+	} {
+		t.Run("ok, removes tail, "+name, func(t *testing.T) {
+			tc := newCloud()
+			tc.AddTagN("go", 5)
+			tc.AddTagN("rust", 2)
+			tc.AddTag("c")
+			tc.AddTag("zig")
+
+			removed := tc.Prune(2)
+
+			assert.Equal(t, []tagcloud.TagStat{{Tag: "c", OccurrenceCount: 1}, {Tag: "zig", OccurrenceCount: 1}}, removed)
+			assert.Equal(t, []tagcloud.TagStat{{Tag: "go", OccurrenceCount: 5}, {Tag: "rust", OccurrenceCount: 2}}, tc.TopN(10))
+			assert.Equal(t, 7, tc.Total())
+			assert.Equal(t, 2, tc.Len())
+		})
+	}
+
+	t.Run("ok, removed tags match what disappeared", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(1))
+		tc := tagcloud.NewOrdered()
+		for i := 0; i < 2000; i++ {
+			tc.AddTag(fmt.Sprintf("t%d", int(rnd.ExpFloat64()*50)))
+		}
+		before := tc.Clone()
+
+		removed := tc.Prune(3)
+
+		diff := before.Diff(tc)
+		assert.Len(t, removed, len(diff))
+		for _, stat := range removed {
+			assert.Less(t, stat.OccurrenceCount, 3)
+			assert.Equal(t, stat.OccurrenceCount, before.Count(stat.Tag))
+			assert.False(t, tc.Contains(stat.Tag))
+		}
+		total := 0
+		for _, stat := range tc.TopN(tc.Len()) {
+			assert.GreaterOrEqual(t, stat.OccurrenceCount, 3)
+			total += stat.OccurrenceCount
+		}
+		assert.Equal(t, total, tc.Total())
+	})
+
+	t.Run("ok, nothing to prune", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag("go")
+
+		assert.Equal(t, []tagcloud.TagStat{}, tc.Prune(1))
+		assert.Equal(t, []tagcloud.TagStat{}, tagcloud.New().Prune(10))
+		assert.Equal(t, 1, tc.Len())
+	})
+}
+
+func TestWithAutoPrune(t *testing.T) {
+	t.Run("ok, prunes after every n additions", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithAutoPrune(2, 4))
+		tc.AddTagN("go", 2)
+		tc.AddTag("c")
+		tc.AddTag("zig")
+		assert.Equal(t, 3, tc.Len())
+
+		tc.AddTag("rust")
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "go", OccurrenceCount: 2}}, tc.TopN(10))
+		assert.Equal(t, 2, tc.Total())
+
+		tc.AddTag("c")
+		tc.AddTag("c")
+		tc.AddTag("zig")
+		assert.Equal(t, 3, tc.Len())
+		tc.AddTag("rust")
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "c", OccurrenceCount: 2}, {Tag: "go", OccurrenceCount: 2}}, tc.TopN(10))
+	})
+
+	t.Run("ok, disabled", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithAutoPrune(2, 0))
+		for i := 0; i < 100; i++ {
+			tc.AddTag(fmt.Sprint(i))
+		}
+
+		assert.Equal(t, 100, tc.Len())
+	})
+}
@@ -0,0 +1,134 @@
+package tagcloud
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// sketchExactLimit is the smallest count kept by the quantile sketch approximately,
+	// smaller counts are kept exactly
+	sketchExactLimit = 128
+	// SketchRelativeError bounds the relative error of CountQuantiles answers of a cloud with WithQuantileSketch
+	SketchRelativeError = 0.01
+)
+
+// sketchLogGamma is the log of the ratio between bounds of a sketch bucket
+var sketchLogGamma = math.Log((1 + SketchRelativeError) / (1 - SketchRelativeError))
+
+// WithQuantileSketch makes the cloud maintain a sketch of tag counts, so CountQuantiles takes time
+// independent of the number of tags. answers for counts below 128 are exact, larger ones are within
+// SketchRelativeError of the exact answer
+func WithQuantileSketch() Option {
+	return func(cloud *TagCloud) {
+		cloud.quantileSketch = true
+	}
+}
+
+// CountQuantiles returns occurrence counts at quantiles qs using the nearest-rank method as Percentile does,
+// e.g. 0.99 gives the count which 99% of tags don't exceed. counts are sorted for every call
+// unless the cloud is created with WithQuantileSketch. quantiles must be within (0, 1),
+// ErrEmptyCloud is returned for an empty cloud
+func (cloud *TagCloud) CountQuantiles(qs ...float64) ([]int, error) {
+	for _, q := range qs {
+		if math.IsNaN(q) || q <= 0 || q >= 1 {
+			return nil, fmt.Errorf("quantile %v is out of range (0, 1)", q)
+		}
+	}
+	result := make([]int, len(qs))
+	if cloud.quantileSketch {
+		cloud.rlock()
+		defer cloud.runlock()
+		if cloud.tags.Len() == 0 {
+			return nil, ErrEmptyCloud
+		}
+		for i, q := range qs {
+			result[i] = cloud.sketch.quantile(q)
+		}
+		return result, nil
+	}
+	counts := cloud.sortedCounts()
+	if len(counts) == 0 {
+		return nil, ErrEmptyCloud
+	}
+	for i, q := range qs {
+		result[i] = counts[nearestRank(q, len(counts))-1]
+	}
+	return result, nil
+}
+
+// nearestRank returns the 1-based rank of the quantile q among n values
+func nearestRank(q float64, n int) int {
+	return max(int(math.Ceil(q*float64(n))), 1)
+}
+
+// countSketch is a histogram of tag counts, small counts have their own buckets and larger ones
+// share logarithmic buckets, so changing a count and finding a quantile don't depend on the number of tags
+type countSketch struct {
+	small []int
+	// large[i] is the number of counts within (gamma^(i-1), gamma^i] for bucket index i = base + offset
+	large []int
+	base  int
+	n     int
+}
+
+// updateSketch moves a tag count of a cloud with WithQuantileSketch from old to count, zero means no tag
+func (cloud *TagCloud) updateSketch(old, count int) {
+	if !cloud.quantileSketch {
+		return
+	}
+	if cloud.sketch == nil {
+		cloud.sketch = &countSketch{}
+	}
+	if old > 0 {
+		cloud.sketch.add(old, -1)
+	}
+	if count > 0 {
+		cloud.sketch.add(count, 1)
+	}
+}
+
+func (s *countSketch) add(count, delta int) {
+	s.n += delta
+	if count < sketchExactLimit {
+		if count >= len(s.small) {
+			s.small = append(s.small, make([]int, count+1-len(s.small))...)
+		}
+		s.small[count] += delta
+		return
+	}
+	index := sketchIndex(count)
+	switch {
+	case len(s.large) == 0:
+		s.base = index
+	case index < s.base:
+		s.large = append(make([]int, s.base-index), s.large...)
+		s.base = index
+	}
+	if index-s.base >= len(s.large) {
+		s.large = append(s.large, make([]int, index-s.base+1-len(s.large))...)
+	}
+	s.large[index-s.base] += delta
+}
+
+func sketchIndex(count int) int {
+	return int(math.Ceil(math.Log(float64(count)) / sketchLogGamma))
+}
+
+// quantile returns the count at the nearest rank of q, a count of a large bucket is estimated
+// by the value with the same relative error to both bucket bounds
+func (s *countSketch) quantile(q float64) int {
+	rank := nearestRank(q, s.n)
+	for count, n := range s.small {
+		if rank -= n; rank <= 0 {
+			return count
+		}
+	}
+	for offset, n := range s.large {
+		if rank -= n; rank <= 0 {
+			gamma := math.Exp(sketchLogGamma)
+			return int(math.Round(2 * math.Pow(gamma, float64(s.base+offset)) / (gamma + 1)))
+		}
+	}
+	return 0
+}
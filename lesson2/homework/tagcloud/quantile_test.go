@@ -0,0 +1,107 @@
+package tagcloud_test
+
+import (
+	"fmt"
+	"lecture02_homework/tagcloud"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountQuantiles(t *testing.T) {
+	t.Run("ok, exact", func(t *testing.T) {
+		tc := tagcloud.New()
+		for i := 1; i <= 100; i++ {
+			tc.AddTagN(fmt.Sprint(i), i)
+		}
+
+		quantiles, err := tc.CountQuantiles(0.5, 0.95, 0.99, 0.001)
+
+		require.NoError(t, err)
+		assert.Equal(t, []int{50, 95, 99, 1}, quantiles)
+	})
+
+	t.Run("ok, sketch of a large cloud", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(1))
+		sketched := tagcloud.New(tagcloud.WithQuantileSketch())
+		for i := 0; i < 200000; i++ {
+			sketched.AddTagN(fmt.Sprint(i), int(math.Pow(10, rnd.Float64()*6))+1)
+		}
+		for i := 0; i < 20000; i++ {
+			tag := fmt.Sprint(rnd.Intn(200000))
+			if i%2 == 0 {
+				sketched.DeleteTag(tag)
+			} else {
+				sketched.RemoveTag(tag)
+			}
+		}
+		exact := tagcloud.New()
+		exact.MergeInto(sketched)
+		qs := []float64{0.01, 0.1, 0.5, 0.9, 0.95, 0.99, 0.999}
+
+		want, err := exact.CountQuantiles(qs...)
+		require.NoError(t, err)
+		got, err := sketched.CountQuantiles(qs...)
+		require.NoError(t, err)
+
+		for i := range qs {
+			if want[i] < 128 {
+				assert.Equal(t, want[i], got[i], "quantile %v", qs[i])
+			} else {
+				assert.InEpsilon(t, want[i], got[i], tagcloud.SketchRelativeError, "quantile %v", qs[i])
+			}
+		}
+	})
+
+	t.Run("ok, sketch follows reset and clone", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithQuantileSketch())
+		tc.AddTagN("go", 1000)
+		tc.Reset()
+		tc.AddTagN("go", 3)
+		tc.AddTag("rust")
+
+		clone := tc.Clone()
+		clone.AddTagN("c", 7)
+
+		quantiles, err := tc.CountQuantiles(0.5, 0.9)
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 3}, quantiles)
+		quantiles, err = clone.CountQuantiles(0.5, 0.9)
+		require.NoError(t, err)
+		assert.Equal(t, []int{3, 7}, quantiles)
+	})
+
+	t.Run("error, invalid quantiles", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithQuantileSketch())
+		tc.AddTag("go")
+
+		for _, q := range []float64{0, 1, -0.5, math.NaN()} {
+			_, err := tc.CountQuantiles(0.5, q)
+			assert.EqualError(t, err, fmt.Sprintf("quantile %v is out of range (0, 1)", q))
+		}
+	})
+
+	t.Run("error, empty cloud", func(t *testing.T) {
+		for _, tc := range []*tagcloud.TagCloud{tagcloud.New(), tagcloud.New(tagcloud.WithQuantileSketch())} {
+			_, err := tc.CountQuantiles(0.5)
+			assert.ErrorIs(t, err, tagcloud.ErrEmptyCloud)
+		}
+	})
+}
+
+func BenchmarkCountQuantiles(b *testing.B) {
+	for name, options := range map[string][]tagcloud.Option{"exact": nil, "sketch": {tagcloud.WithQuantileSketch()}} {
+		tc := tagcloud.New(options...)
+		for i := 0; i < 100000; i++ {
+			tc.AddTagN(fmt.Sprint(i), i%1000+1)
+		}
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = tc.CountQuantiles(0.5, 0.95, 0.99)
+			}
+		})
+	}
+}
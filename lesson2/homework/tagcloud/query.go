@@ -0,0 +1,32 @@
+package tagcloud
+
+// Count returns occurrence count of the tag, zero if the tag is absent
+func (cloud *TagCloud) Count(tag string) int {
+	tag = cloud.normalize(tag)
+	cloud.rlock()
+	defer cloud.runlock()
+	return cloud.tags.Count(tag)
+}
+
+// Contains reports whether the tag is present in the cloud
+func (cloud *TagCloud) Contains(tag string) bool {
+	tag = cloud.normalize(tag)
+	cloud.rlock()
+	defer cloud.runlock()
+	_, ok := cloud.tags.get(tag)
+	return ok
+}
+
+// Len returns the number of distinct tags
+func (cloud *TagCloud) Len() int {
+	cloud.rlock()
+	defer cloud.runlock()
+	return cloud.tags.Len()
+}
+
+// Total returns the sum of all occurrence counts in O(1)
+func (cloud *TagCloud) Total() int {
+	cloud.rlock()
+	defer cloud.runlock()
+	return cloud.tags.Total()
+}
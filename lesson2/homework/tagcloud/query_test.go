@@ -0,0 +1,79 @@
+package tagcloud_test
+
+import (
+	"fmt"
+	"lecture02_homework/tagcloud"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery(t *testing.T) {
+	t.Run("ok, empty cloud", func(t *testing.T) {
+		tc := tagcloud.New()
+
+		assert.Equal(t, 0, tc.Count("t1"))
+		assert.False(t, tc.Contains("t1"))
+		assert.Equal(t, 0, tc.Len())
+		assert.Equal(t, 0, tc.Total())
+	})
+
+	t.Run("ok, added tags", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTags("t1", "t2", "t1")
+		tc.AddTagN("t3", 5)
+
+		assert.Equal(t, 2, tc.Count("t1"))
+		assert.Equal(t, 5, tc.Count("t3"))
+		assert.Equal(t, 0, tc.Count("missing"))
+		assert.True(t, tc.Contains("t2"))
+		assert.False(t, tc.Contains("missing"))
+		assert.Equal(t, 3, tc.Len())
+		assert.Equal(t, 8, tc.Total())
+	})
+
+	t.Run("ok, removed tags", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTags("t1", "t2", "t1")
+		tc.AddTagN("t3", 5)
+		tc.RemoveTag("t2")
+		tc.RemoveTag("t1")
+		tc.DeleteTag("t3")
+		tc.RemoveTag("missing")
+
+		assert.Equal(t, 1, tc.Count("t1"))
+		assert.False(t, tc.Contains("t2"))
+		assert.False(t, tc.Contains("t3"))
+		assert.Equal(t, 1, tc.Len())
+		assert.Equal(t, 1, tc.Total())
+	})
+}
+
+func TestTotalMatchesTopN(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	tc := tagcloud.New()
+	for i := 0; i < 10000; i++ {
+		tag := fmt.Sprintf("t%d", rnd.Intn(50))
+		switch rnd.Intn(4) {
+		case 0:
+			tc.RemoveTag(tag)
+		case 1:
+			tc.AddTagN(tag, rnd.Intn(10))
+		case 2:
+			if rnd.Intn(10) == 0 {
+				tc.DeleteTag(tag)
+			}
+		default:
+			tc.AddTag(tag)
+		}
+
+		sum := 0
+		for _, stat := range tc.TopN(tc.Len()) {
+			sum += stat.OccurrenceCount
+		}
+		if !assert.Equal(t, sum, tc.Total(), "total differs after operation %d", i) {
+			return
+		}
+	}
+}
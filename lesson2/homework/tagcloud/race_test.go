@@ -0,0 +1,6 @@
+//go:build race
+
+package tagcloud_test
+
+// raceEnabled is set when tests run with the race detector, which makes allocation counts unstable
+const raceEnabled = true
@@ -0,0 +1,220 @@
+package tagcloud
+
+import (
+	"bufio"
+	"io"
+	"runtime"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxTokenSize limits memory used by AddFromReader, it's far above the default bufio.Scanner limit
+const maxTokenSize = 16 << 20
+
+// maxInterned limits the number of distinct tokens AddFromReader keeps to avoid allocating a string per token
+const maxInterned = 4096
+
+// scanBuffers holds initial buffers of AddFromReader scanners
+var scanBuffers = sync.Pool{
+	New: func() any {
+		buffer := make([]byte, 0, 4096)
+		return &buffer
+	},
+}
+
+// WithSplitFunc sets how AddFromReader splits text into tokens, by default see ScanTokens
+func WithSplitFunc(split bufio.SplitFunc) Option {
+	return func(cloud *TagCloud) {
+		cloud.split = split
+	}
+}
+
+// ScanTokens is a bufio.SplitFunc returning runs of letters and digits, any other rune separates tokens
+func ScanTokens(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	start := 0
+	for start < len(data) {
+		// a rune split between reads may start a token
+		if !utf8.FullRune(data[start:]) && !atEOF {
+			return start, nil, nil
+		}
+		r, size := utf8.DecodeRune(data[start:])
+		if isTokenRune(r) {
+			break
+		}
+		start += size
+	}
+	for i := start; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		if !utf8.FullRune(data[i:]) && !atEOF {
+			break
+		}
+		if !isTokenRune(r) {
+			return i + size, data[start:i], nil
+		}
+		i += size
+	}
+	if atEOF && len(data) > start {
+		return len(data), data[start:], nil
+	}
+	return start, nil, nil
+}
+
+func isTokenRune(r rune) bool {
+	if r < utf8.RuneSelf {
+		return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9'
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// AddFromReader splits text of the reader into tokens and adds every token as a tag,
+// tokens are normalized and validated same as in AddTag.
+// the reader is scanned by blocks, so memory doesn't depend on the input size.
+// it returns the number of added tags
+func (cloud *TagCloud) AddFromReader(r io.Reader) (int, error) {
+	buffer := scanBuffers.Get().(*[]byte)
+	defer scanBuffers.Put(buffer)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer((*buffer)[:0], maxTokenSize)
+	if cloud.split != nil {
+		scanner.Split(cloud.split)
+	} else {
+		scanner.Split(ScanTokens)
+	}
+	interned := map[string]string{}
+	added := 0
+	for scanner.Scan() {
+		if cloud.addToken(scanner.Bytes(), interned) {
+			added++
+		}
+	}
+	return added, scanner.Err()
+}
+
+// AddFromRuneReader works as AddFromReader for a source of already decoded runes, e.g. bufio.Reader
+// or strings.Reader. tokens of ScanTokens are collected rune by rune in a single pass into a reused buffer
+// growing with the longest token, so no scanner buffer is filled and decoded again. tokens longer than
+// the AddFromReader limit fail with bufio.ErrTooLong. with WithSplitFunc runes are encoded back to bytes
+// for the split function
+func (cloud *TagCloud) AddFromRuneReader(r io.RuneReader) (int, error) {
+	if cloud.split != nil {
+		return cloud.AddFromReader(&runeBytes{r: r})
+	}
+	buffer := scanBuffers.Get().(*[]byte)
+	defer scanBuffers.Put(buffer)
+	token := (*buffer)[:0]
+	defer func() { *buffer = token[:0] }()
+	interned := map[string]string{}
+	added := 0
+	for {
+		char, _, err := r.ReadRune()
+		if err == nil && isTokenRune(char) {
+			if len(token) >= maxTokenSize {
+				return added, bufio.ErrTooLong
+			}
+			token = utf8.AppendRune(token, char)
+			continue
+		}
+		if len(token) > 0 && cloud.addToken(token, interned) {
+			added++
+		}
+		token = token[:0]
+		switch {
+		case err == io.EOF:
+			return added, nil
+		case err != nil:
+			return added, err
+		}
+	}
+}
+
+// addToken adds a token read by AddFromReader or AddFromRuneReader and reports whether it's counted.
+// lookup by converted bytes doesn't allocate, so repeated tokens reuse their string from interned
+func (cloud *TagCloud) addToken(bytes []byte, interned map[string]string) bool {
+	token, ok := interned[string(bytes)]
+	if !ok {
+		token = string(bytes)
+		if len(interned) < maxInterned {
+			interned[token] = token
+		}
+	}
+	key, ok := cloud.key(token)
+	if !ok {
+		return false
+	}
+	cloud.lock()
+	cloud.setCount(key, cloud.tags.Count(key)+1)
+	cloud.noteSurface(token, key, 1)
+	cloud.unlock()
+	return true
+}
+
+// runeBytes encodes runes of a reader back to UTF-8 for split functions of AddFromRuneReader
+type runeBytes struct {
+	r io.RuneReader
+}
+
+func (b *runeBytes) Read(p []byte) (int, error) {
+	n := 0
+	for len(p)-n >= utf8.UTFMax {
+		char, _, err := b.r.ReadRune()
+		if err != nil {
+			return n, err
+		}
+		n += utf8.EncodeRune(p[n:], char)
+	}
+	return n, nil
+}
+
+// AddFromReaders works as AddFromReader for every reader using up to workers goroutines,
+// non-positive workers means GOMAXPROCS. every reader is counted into its own local cloud,
+// so workers don't contend for the lock, and local clouds are merged in the order of readers.
+// a failed reader doesn't stop the others, tokens read before the failure are counted and the error
+// of the first failed reader is returned. the result is the total number of added tokens
+func (cloud *TagCloud) AddFromReaders(readers []io.Reader, workers int) (int, error) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	locals := make([]*TagCloud, len(readers))
+	added := make([]int, len(readers))
+	errs := make([]error, len(readers))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers && i < len(readers); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				locals[job] = cloud.local()
+				added[job], errs[job] = locals[job].AddFromReader(readers[job])
+			}
+		}()
+	}
+	for i := range readers {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	total := 0
+	var firstErr error
+	for i, local := range locals {
+		cloud.MergeInto(local)
+		total += added[i]
+		if errs[i] != nil && firstErr == nil {
+			firstErr = errs[i]
+		}
+	}
+	return total, firstErr
+}
+
+// local returns an empty cloud which tokenizes and normalizes tags like the receiver,
+// tags counted by it are limited, expired and split into namespaces only when merged into the receiver
+func (cloud *TagCloud) local() *TagCloud {
+	local := &TagCloud{aliases: cloud.copyAliases(), cloudConfig: cloud.cloudConfig}
+	local.maxTags = 0
+	local.ttl = 0
+	local.separator = ""
+	local.opLog = false
+	return local
+}
@@ -0,0 +1,272 @@
+package tagcloud_test
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"lecture02_homework/tagcloud"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddFromReader(t *testing.T) {
+	t.Run("ok, fixture paragraph", func(t *testing.T) {
+		file, err := os.Open("testdata/words/paragraph.txt")
+		assert.NoError(t, err)
+		defer file.Close()
+		tc := tagcloud.New(tagcloud.WithCaseInsensitive(), tagcloud.WithStopWords("the", "is", "and"))
+
+		added, err := tc.AddFromReader(file)
+
+		assert.NoError(t, err)
+		assert.Equal(t, tc.Total(), added)
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "go", OccurrenceCount: 5},
+			{Tag: "café", OccurrenceCount: 2},
+			{Tag: "1", OccurrenceCount: 1},
+		}, tc.TopN(3))
+		for _, tag := range []string{"язык", "программы", "быстрые", "multicore", "2024", "42", "23"} {
+			assert.True(t, tc.Contains(tag), tag)
+		}
+		assert.False(t, tc.Contains("the"))
+		assert.False(t, tc.Contains("go-программы"))
+	})
+
+	t.Run("ok, custom split func", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithSplitFunc(bufio.ScanLines))
+
+		added, err := tc.AddFromReader(strings.NewReader("go lang\n\n  go lang  \nrust\n"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, added)
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "go lang", OccurrenceCount: 2}, {Tag: "rust", OccurrenceCount: 1}}, tc.TopN(10))
+	})
+
+	t.Run("ok, token longer than scanner buffer", func(t *testing.T) {
+		long := strings.Repeat("я", 100000)
+		tc := tagcloud.New()
+
+		added, err := tc.AddFromReader(strings.NewReader("short " + long + " short"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, added)
+		assert.Equal(t, 1, tc.Count(long))
+	})
+
+	t.Run("ok, large input", func(t *testing.T) {
+		tc := tagcloud.New()
+		words := strings.NewReader(strings.Repeat("alpha, beta; gamma!\n", 1))
+		input := io.MultiReader(io.LimitReader(&repeatReader{text: "alpha, beta; gamma!\n"}, 20*400000), words)
+
+		added, err := tc.AddFromReader(input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, tc.Len())
+		assert.Equal(t, tc.Total(), added)
+		assert.Equal(t, tc.Count("alpha"), tc.Count("gamma"))
+	})
+}
+
+func TestAddFromRuneReader(t *testing.T) {
+	t.Run("ok, same tags as AddFromReader", func(t *testing.T) {
+		text, err := os.ReadFile("testdata/words/paragraph.txt")
+		assert.NoError(t, err)
+		options := []tagcloud.Option{tagcloud.WithCaseInsensitive(), tagcloud.WithStopWords("the", "is", "and")}
+		scanned, runes := tagcloud.New(options...), tagcloud.New(options...)
+
+		wantAdded, err := scanned.AddFromReader(bytes.NewReader(text))
+		assert.NoError(t, err)
+		added, err := runes.AddFromRuneReader(bytes.NewReader(text))
+
+		assert.NoError(t, err)
+		assert.Equal(t, wantAdded, added)
+		assert.Equal(t, scanned.ToMap(), runes.ToMap())
+	})
+
+	t.Run("ok, invalid UTF-8 separates tokens", func(t *testing.T) {
+		tc := tagcloud.New()
+
+		added, err := tc.AddFromRuneReader(strings.NewReader("go\xffgo\xd0"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, added)
+		assert.Equal(t, map[string]int{"go": 2}, tc.ToMap())
+	})
+
+	t.Run("ok, token longer than initial buffer", func(t *testing.T) {
+		long := strings.Repeat("я", 100000)
+		tc := tagcloud.New()
+
+		added, err := tc.AddFromRuneReader(bufio.NewReader(strings.NewReader("short " + long + " short")))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, added)
+		assert.Equal(t, 1, tc.Count(long))
+		assert.Equal(t, 2, tc.Count("short"))
+	})
+
+	t.Run("ok, custom split func", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithSplitFunc(bufio.ScanLines))
+
+		added, err := tc.AddFromRuneReader(strings.NewReader("go lang\nкафе\n"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, added)
+		assert.Equal(t, map[string]int{"go lang": 1, "кафе": 1}, tc.ToMap())
+	})
+
+	t.Run("error, failed reader keeps read tokens", func(t *testing.T) {
+		failure := errors.New("failure")
+		tc := tagcloud.New()
+
+		added, err := tc.AddFromRuneReader(bufio.NewReader(io.MultiReader(strings.NewReader("go rust"), iotest.ErrReader(failure))))
+
+		assert.ErrorIs(t, err, failure)
+		assert.Equal(t, 2, added)
+		assert.Equal(t, map[string]int{"go": 1, "rust": 1}, tc.ToMap())
+	})
+}
+
+// repeatReader endlessly repeats the text
+type repeatReader struct {
+	text string
+	pos  int
+}
+
+func (r *repeatReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		copied := copy(p[n:], r.text[r.pos:])
+		n += copied
+		r.pos = (r.pos + copied) % len(r.text)
+	}
+	return n, nil
+}
+
+func TestScanTokens(t *testing.T) {
+	scanner := bufio.NewScanner(strings.NewReader("  hello,мир!!42 x_y   "))
+	scanner.Split(tagcloud.ScanTokens)
+	var tokens []string
+	for scanner.Scan() {
+		tokens = append(tokens, scanner.Text())
+	}
+
+	assert.NoError(t, scanner.Err())
+	assert.Equal(t, []string{"hello", "мир", "42", "x", "y"}, tokens)
+
+	// the first rune of a token may be split between reads
+	advance, token, err := tagcloud.ScanTokens([]byte(", \xd0"), false)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, advance)
+	assert.Nil(t, token)
+}
+
+func TestAddFromReaders(t *testing.T) {
+	t.Run("ok, same counts as serial reading", func(t *testing.T) {
+		texts := []string{"go, rust; go", "Go gopher", "", "rust rust c"}
+		readers := make([]io.Reader, len(texts))
+		for i, text := range texts {
+			readers[i] = strings.NewReader(text)
+		}
+		tc := tagcloud.New(tagcloud.WithCaseInsensitive(), tagcloud.WithStopWords("c"))
+
+		added, err := tc.AddFromReaders(readers, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 7, added)
+		serial := tagcloud.New(tagcloud.WithCaseInsensitive(), tagcloud.WithStopWords("c"))
+		_, err = serial.AddFromReader(strings.NewReader(strings.Join(texts, " ")))
+		assert.NoError(t, err)
+		assert.Equal(t, serial.TopN(10), tc.TopN(10))
+	})
+
+	t.Run("ok, more workers than readers", func(t *testing.T) {
+		tc := tagcloud.NewConcurrent()
+		tc.AddTag("go")
+
+		added, err := tc.AddFromReaders([]io.Reader{strings.NewReader("go go")}, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, added)
+		assert.Equal(t, 3, tc.Count("go"))
+	})
+
+	t.Run("ok, no readers", func(t *testing.T) {
+		added, err := tagcloud.New().AddFromReaders(nil, 4)
+
+		assert.NoError(t, err)
+		assert.Zero(t, added)
+	})
+
+	t.Run("error, failed reader doesn't stop others", func(t *testing.T) {
+		first := errors.New("first failure")
+		readers := []io.Reader{
+			strings.NewReader("go"),
+			io.MultiReader(strings.NewReader("rust "), iotest.ErrReader(first)),
+			iotest.ErrReader(errors.New("second failure")),
+			strings.NewReader("go c"),
+		}
+		tc := tagcloud.New()
+
+		added, err := tc.AddFromReaders(readers, 3)
+
+		assert.ErrorIs(t, err, first)
+		assert.Equal(t, 4, added)
+		assert.Equal(t, map[string]int{"go": 2, "rust": 1, "c": 1}, tc.ToMap())
+	})
+}
+
+func BenchmarkAddFromReaders(b *testing.B) {
+	const files, size = 16, 1 << 20
+	newReaders := func() []io.Reader {
+		readers := make([]io.Reader, files)
+		for i := range readers {
+			text := fmt.Sprintf("alpha beta%d gamma, delta%d; epsilon\n", i, i%3)
+			readers[i] = io.LimitReader(&repeatReader{text: text}, size)
+		}
+		return readers
+	}
+	b.Run("serial", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			tc := tagcloud.New()
+			for _, reader := range newReaders() {
+				_, _ = tc.AddFromReader(reader)
+			}
+		}
+	})
+	for _, workers := range []int{1, 4} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = tagcloud.New().AddFromReaders(newReaders(), workers)
+			}
+		})
+	}
+}
+
+// BenchmarkAddFromRuneReader compares the scanner and the rune reader paths on a 100MB corpus in memory
+func BenchmarkAddFromRuneReader(b *testing.B) {
+	const size = 100 << 20
+	corpus := make([]byte, size)
+	_, _ = io.ReadFull(&repeatReader{text: "alpha beta, gamma; дельта эпсилон 42\n"}, corpus)
+	text := string(corpus)
+	b.Run("scanner", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(size)
+		for i := 0; i < b.N; i++ {
+			_, _ = tagcloud.New().AddFromReader(strings.NewReader(text))
+		}
+	})
+	b.Run("rune reader", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(size)
+		for i := 0; i < b.N; i++ {
+			_, _ = tagcloud.New().AddFromRuneReader(strings.NewReader(text))
+		}
+	})
+}
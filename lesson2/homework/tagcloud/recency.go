@@ -0,0 +1,82 @@
+package tagcloud
+
+import "cmp"
+
+// WithRecencyTieBreak makes TopN order tags with the same count by the time they were last added,
+// the most recently added first, instead of lexicographically.
+// tags replaced at once, e.g. by decoding, keep lexicographic order until they are added again.
+// an ordered cloud with this option sorts on every query instead of walking its ordering
+func WithRecencyTieBreak() Option {
+	return func(cloud *TagCloud) {
+		cloud.recencyTieBreak = true
+	}
+}
+
+// compare returns the TopN order of the cloud, the cloud must be locked while it's used
+func (cloud *TagCloud) compare() func(a, b TagStat) int {
+	if !cloud.recencyTieBreak {
+		return compareTagStats
+	}
+	return compareByRecency(cloud.added)
+}
+
+// compareCopy returns the TopN order of the cloud which can be used after the cloud is unlocked
+func (cloud *TagCloud) compareCopy() func(a, b TagStat) int {
+	if !cloud.recencyTieBreak {
+		return compareTagStats
+	}
+	return compareByRecency(cloud.copyAdded())
+}
+
+// compareByRecency orders stats by occurrence count descending, then by sequence number of the last addition
+// descending and then by tag
+func compareByRecency(added map[string]uint64) func(a, b TagStat) int {
+	return func(a, b TagStat) int {
+		if c := cmp.Compare(b.OccurrenceCount, a.OccurrenceCount); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(added[b.Tag], added[a.Tag]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Tag, b.Tag)
+	}
+}
+
+// markAdded remembers the tag as the most recently added one
+func (cloud *TagCloud) markAdded(tag string) {
+	if !cloud.recencyTieBreak {
+		return
+	}
+	if cloud.added == nil {
+		cloud.added = map[string]uint64{}
+	}
+	if cloud.sequence != nil {
+		cloud.added[tag] = cloud.sequence.Add(1)
+		return
+	}
+	cloud.seq++
+	cloud.added[tag] = cloud.seq
+}
+
+func (cloud *TagCloud) copyAdded() map[string]uint64 {
+	if cloud.added == nil {
+		return nil
+	}
+	added := make(map[string]uint64, len(cloud.added))
+	for tag, seq := range cloud.added {
+		added[tag] = seq
+	}
+	return added
+}
+
+// addedOf returns sequence numbers of the last addition of the tags
+func (cloud *TagCloud) addedOf(stats []TagStat) map[string]uint64 {
+	if cloud.added == nil {
+		return nil
+	}
+	added := make(map[string]uint64, len(stats))
+	for _, stat := range stats {
+		added[stat.Tag] = cloud.added[stat.Tag]
+	}
+	return added
+}
@@ -0,0 +1,75 @@
+package tagcloud_test
+
+import (
+	"lecture02_homework/tagcloud"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithRecencyTieBreak(t *testing.T) {
+	for name, newCloud := range map[string]func(...tagcloud.Option) *tagcloud.TagCloud{
+		"new":     tagcloud.New,
+		"ordered": tagcloud.NewOrdered,
+	} {
+		t.Run("ok, most recent first, "+name, func(t *testing.T) {
+			tc := newCloud(tagcloud.WithRecencyTieBreak())
+			tc.AddTags("a", "b", "c")
+
+			assert.Equal(t, []tagcloud.TagStat{
+				{Tag: "c", OccurrenceCount: 1},
+				{Tag: "b", OccurrenceCount: 1},
+				{Tag: "a", OccurrenceCount: 1},
+			}, tc.TopN(10))
+
+			tc.AddTags("a", "b")
+
+			assert.Equal(t, []tagcloud.TagStat{
+				{Tag: "b", OccurrenceCount: 2},
+				{Tag: "a", OccurrenceCount: 2},
+				{Tag: "c", OccurrenceCount: 1},
+			}, tc.TopN(10))
+			assert.Equal(t, tc.TopN(2), tc.TopNFrom(0, 2))
+
+			tc.AddTag("c")
+			tc.AddTag("a")
+
+			assert.Equal(t, []tagcloud.TagStat{{Tag: "a", OccurrenceCount: 3}, {Tag: "c", OccurrenceCount: 2}}, tc.TopN(2))
+		})
+	}
+
+	t.Run("ok, default order stays lexicographic", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTags("c", "b", "a")
+
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "a", OccurrenceCount: 1},
+			{Tag: "b", OccurrenceCount: 1},
+			{Tag: "c", OccurrenceCount: 1},
+		}, tc.TopN(10))
+	})
+
+	t.Run("ok, removal doesn't refresh", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithRecencyTieBreak())
+		tc.AddTagN("a", 2)
+		tc.AddTagN("b", 2)
+		tc.AddTag("c")
+		tc.RemoveTag("a")
+
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "b", OccurrenceCount: 2},
+			{Tag: "c", OccurrenceCount: 1},
+			{Tag: "a", OccurrenceCount: 1},
+		}, tc.TopN(10))
+	})
+
+	t.Run("ok, clone and snapshot keep recency", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithRecencyTieBreak())
+		tc.AddTags("a", "b")
+		expected := []tagcloud.TagStat{{Tag: "b", OccurrenceCount: 1}, {Tag: "a", OccurrenceCount: 1}}
+
+		assert.Equal(t, expected, tc.Clone().TopN(10))
+		assert.Equal(t, expected, tc.Snapshot().TopN(10))
+		assert.Equal(t, expected, tc.TagsWithPrefix("", 10))
+	})
+}
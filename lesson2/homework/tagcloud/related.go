@@ -0,0 +1,107 @@
+package tagcloud
+
+// DefaultMaxTagSet is the default number of tags of a set taking part in co-occurrence counting
+const DefaultMaxTagSet = 32
+
+// tagPair is a pair of co-occurring tags, first < second so every pair is stored once
+type tagPair struct {
+	first  string
+	second string
+}
+
+func newTagPair(a, b string) tagPair {
+	if b < a {
+		a, b = b, a
+	}
+	return tagPair{first: a, second: b}
+}
+
+// WithMaxTagSet limits the number of tags of a set passed to AddTagSet which take part in co-occurrence counting.
+// a set of n tags adds n*(n-1)/2 pairs, so only first n distinct tags of a larger set are paired,
+// though all of them are counted. non-positive n means DefaultMaxTagSet
+func WithMaxTagSet(n int) Option {
+	return func(cloud *TagCloud) {
+		cloud.maxTagSet = n
+	}
+}
+
+// AddTagSet adds every distinct tag of the set once and counts co-occurrence of every pair of them, see Related.
+// memory for co-occurrence grows quadratically with the set size, see WithMaxTagSet
+func (cloud *TagCloud) AddTagSet(tags ...string) {
+	limit := cloud.maxTagSet
+	if limit <= 0 {
+		limit = DefaultMaxTagSet
+	}
+	keys := make([]string, 0, len(tags))
+	// forms are the added tags of the keys
+	forms := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		key, ok := cloud.key(tag)
+		if ok && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+			forms = append(forms, tag)
+		}
+	}
+	cloud.lock()
+	defer cloud.unlock()
+	for i, key := range keys {
+		cloud.setCount(key, cloud.tags.Count(key)+1)
+		cloud.noteSurface(forms[i], key, 1)
+	}
+	// tags may be evicted by a bounded cloud, pairs of absent tags are not counted
+	paired := keys[:min(len(keys), limit)]
+	for i, first := range paired {
+		for _, second := range paired[i+1:] {
+			if cloud.tags.Count(first) > 0 && cloud.tags.Count(second) > 0 {
+				cloud.addPair(first, second, 1)
+			}
+		}
+	}
+}
+
+// Related returns n tags most frequently added in one set with the tag, OccurrenceCount is the number of such sets.
+// the order is the same as in TopN. it scans all pairs, so it takes time proportional to their number
+func (cloud *TagCloud) Related(tag string, n int) []TagStat {
+	tag = cloud.normalize(tag)
+	cloud.rlock()
+	related := &TagCloud{}
+	for pair, count := range cloud.pairs {
+		switch tag {
+		case pair.first:
+			related.tags.set(pair.second, count)
+		case pair.second:
+			related.tags.set(pair.first, count)
+		}
+	}
+	cloud.runlock()
+	return related.selectStats(n, 0, compareTagStats)
+}
+
+func (cloud *TagCloud) addPair(a, b string, count int) {
+	if cloud.pairs == nil {
+		cloud.pairs = map[tagPair]int{}
+	}
+	cloud.pairs[newTagPair(a, b)] += count
+}
+
+// deletePairs forgets co-occurrence of a removed tag
+func (cloud *TagCloud) deletePairs(tag string) {
+	for pair := range cloud.pairs {
+		if pair.first == tag || pair.second == tag {
+			delete(cloud.pairs, pair)
+		}
+	}
+}
+
+func (cloud *TagCloud) copyPairs() map[tagPair]int {
+	if len(cloud.pairs) == 0 {
+		return nil
+	}
+	pairs := make(map[tagPair]int, len(cloud.pairs))
+	for pair, count := range cloud.pairs {
+		pairs[pair] = count
+	}
+	return pairs
+}
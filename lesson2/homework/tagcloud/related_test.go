@@ -0,0 +1,88 @@
+package tagcloud_test
+
+import (
+	"lecture02_homework/tagcloud"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newRelatedCloud(options ...tagcloud.Option) *tagcloud.TagCloud {
+	tc := tagcloud.New(options...)
+	tc.AddTagSet("go", "concurrency", "channels")
+	tc.AddTagSet("go", "concurrency", "goroutines")
+	tc.AddTagSet("go", "generics")
+	tc.AddTagSet("rust", "concurrency", "ownership")
+	tc.AddTagSet("go", "channels", "go")
+	return tc
+}
+
+func TestRelated(t *testing.T) {
+	t.Run("ok, related rankings", func(t *testing.T) {
+		tc := newRelatedCloud()
+
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "channels", OccurrenceCount: 2},
+			{Tag: "concurrency", OccurrenceCount: 2},
+			{Tag: "generics", OccurrenceCount: 1},
+			{Tag: "goroutines", OccurrenceCount: 1},
+		}, tc.Related("go", 10))
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "go", OccurrenceCount: 2},
+			{Tag: "channels", OccurrenceCount: 1},
+		}, tc.Related("concurrency", 2))
+		assert.Empty(t, tc.Related("missing", 10))
+		assert.NotNil(t, tc.Related("go", 0))
+	})
+
+	t.Run("ok, repeated tags in a set are counted once", func(t *testing.T) {
+		tc := newRelatedCloud()
+
+		assert.Equal(t, 4, tc.Count("go"))
+		assert.Equal(t, 3, tc.Count("concurrency"))
+	})
+
+	t.Run("ok, deleted tag is forgotten", func(t *testing.T) {
+		tc := newRelatedCloud()
+
+		tc.DeleteTag("channels")
+		tc.AddTag("channels")
+
+		assert.Empty(t, tc.Related("channels", 10))
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "concurrency", OccurrenceCount: 2},
+			{Tag: "generics", OccurrenceCount: 1},
+			{Tag: "goroutines", OccurrenceCount: 1},
+		}, tc.Related("go", 10))
+	})
+
+	t.Run("ok, set size limit", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithMaxTagSet(2))
+		tc.AddTagSet("a", "b", "c", "d")
+
+		assert.Equal(t, 4, tc.Len())
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "b", OccurrenceCount: 1}}, tc.Related("a", 10))
+		assert.Empty(t, tc.Related("c", 10))
+	})
+
+	t.Run("ok, normalized tags", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithCaseInsensitive())
+		tc.AddTagSet("Go", "GO", "Channels")
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "channels", OccurrenceCount: 1}}, tc.Related("gO", 10))
+	})
+
+	t.Run("ok, clone, merge and reset", func(t *testing.T) {
+		tc := newRelatedCloud()
+		clone := tc.Clone()
+		clone.AddTagSet("go", "generics")
+		merged := tc.Merge(clone)
+
+		assert.Equal(t, 1, tc.Related("go", 10)[2].OccurrenceCount)
+		assert.Equal(t, tagcloud.TagStat{Tag: "generics", OccurrenceCount: 2}, clone.Related("go", 10)[2])
+		assert.Equal(t, tagcloud.TagStat{Tag: "channels", OccurrenceCount: 4}, merged.Related("go", 1)[0])
+
+		tc.Reset()
+		assert.Empty(t, tc.Related("go", 10))
+	})
+}
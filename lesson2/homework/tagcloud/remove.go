@@ -0,0 +1,26 @@
+package tagcloud
+
+// RemoveTag decreases tag occurrence count, the tag is removed from the cloud when its count reaches zero.
+// removing an absent tag does nothing
+func (cloud *TagCloud) RemoveTag(tag string) {
+	tag = cloud.normalize(tag)
+	cloud.lock()
+	defer cloud.unlock()
+	count, ok := cloud.tags.get(tag)
+	if !ok {
+		return
+	}
+	cloud.setCount(tag, count-1)
+}
+
+// DeleteTag removes the tag regardless of its occurrence count and reports whether it was present
+func (cloud *TagCloud) DeleteTag(tag string) bool {
+	tag = cloud.normalize(tag)
+	cloud.lock()
+	defer cloud.unlock()
+	if _, ok := cloud.tags.get(tag); !ok {
+		return false
+	}
+	cloud.setCount(tag, 0)
+	return true
+}
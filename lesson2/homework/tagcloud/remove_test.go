@@ -0,0 +1,77 @@
+package tagcloud_test
+
+import (
+	"lecture02_homework/tagcloud"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoveTag(t *testing.T) {
+	t.Run("ok, remove to zero", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag("t1")
+		tc.AddTag("t1")
+		tc.AddTag("t2")
+
+		tc.RemoveTag("t1")
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "t1", OccurrenceCount: 1}, {Tag: "t2", OccurrenceCount: 1}}, tc.TopN(10))
+
+		tc.RemoveTag("t1")
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "t2", OccurrenceCount: 1}}, tc.TopN(10))
+	})
+
+	t.Run("ok, remove missing tag", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag("t1")
+
+		tc.RemoveTag("missing")
+		tc.RemoveTag("t1")
+		tc.RemoveTag("t1")
+
+		assert.Empty(t, tc.TopN(10))
+
+		tc.AddTag("t1")
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "t1", OccurrenceCount: 1}}, tc.TopN(10))
+	})
+
+	t.Run("ok, interleaved add and remove", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag("a")
+		tc.AddTag("b")
+		tc.AddTag("a")
+		tc.RemoveTag("b")
+		tc.AddTag("c")
+		tc.RemoveTag("a")
+		tc.AddTag("c")
+		tc.AddTag("b")
+
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "c", OccurrenceCount: 2},
+			{Tag: "a", OccurrenceCount: 1},
+			{Tag: "b", OccurrenceCount: 1},
+		}, tc.TopN(10))
+	})
+}
+
+func TestDeleteTag(t *testing.T) {
+	t.Run("ok, delete existing tag", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag("t1")
+		tc.AddTag("t1")
+		tc.AddTag("t2")
+
+		assert.True(t, tc.DeleteTag("t1"))
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "t2", OccurrenceCount: 1}}, tc.TopN(10))
+	})
+
+	t.Run("ok, delete missing tag", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag("t1")
+
+		assert.False(t, tc.DeleteTag("missing"))
+		assert.True(t, tc.DeleteTag("t1"))
+		assert.False(t, tc.DeleteTag("t1"))
+		assert.Empty(t, tc.TopN(10))
+	})
+}
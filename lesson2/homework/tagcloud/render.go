@@ -0,0 +1,118 @@
+package tagcloud
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+const (
+	DefaultMinFontSize = 12
+	DefaultMaxFontSize = 36
+)
+
+// RenderOrder defines the order of tags in rendered cloud
+type RenderOrder int
+
+const (
+	// OrderByCount renders tags in TopN order
+	OrderByCount RenderOrder = iota
+	// OrderAlphabetical renders top tags sorted by tag
+	OrderAlphabetical
+)
+
+// RenderOptions controls rendering of the cloud
+type RenderOptions struct {
+	// N is the number of top tags to render, non-positive means all tags
+	N int
+	// MinFontSize and MaxFontSize are font sizes in pixels of the least and the most frequent rendered tag,
+	// zero means DefaultMinFontSize and DefaultMaxFontSize
+	MinFontSize float64
+	MaxFontSize float64
+	Order       RenderOrder
+	// LinkTemplate makes every tag a link, {tag} is replaced with the escaped tag, e.g. /tags/{tag}
+	LinkTemplate string
+}
+
+// renderedTag is a tag with its font size
+type renderedTag struct {
+	TagStat
+	FontSize float64
+}
+
+// layout selects tags to render and computes their font sizes scaling linearly with occurrence count
+func (cloud *TagCloud) layout(opts RenderOptions) ([]renderedTag, error) {
+	minSize, maxSize := opts.MinFontSize, opts.MaxFontSize
+	if minSize == 0 {
+		minSize = DefaultMinFontSize
+	}
+	if maxSize == 0 {
+		maxSize = DefaultMaxFontSize
+	}
+	if minSize < 0 || maxSize < minSize {
+		return nil, fmt.Errorf("invalid font sizes %v..%v", minSize, maxSize)
+	}
+	if opts.Order != OrderByCount && opts.Order != OrderAlphabetical {
+		return nil, fmt.Errorf("unknown render order %d", opts.Order)
+	}
+
+	cloud.rlock()
+	n := opts.N
+	if n <= 0 {
+		n = cloud.tags.Len()
+	}
+	stats := cloud.topStats(n, 0)
+	cloud.runlock()
+
+	tags := make([]renderedTag, len(stats))
+	if len(stats) == 0 {
+		return tags, nil
+	}
+	high, low := stats[0].OccurrenceCount, stats[len(stats)-1].OccurrenceCount
+	for i, stat := range stats {
+		size := (minSize + maxSize) / 2
+		if high != low {
+			size = minSize + (maxSize-minSize)*float64(stat.OccurrenceCount-low)/float64(high-low)
+		}
+		tags[i] = renderedTag{TagStat: stat, FontSize: size}
+	}
+	if opts.Order == OrderAlphabetical {
+		slices.SortFunc(tags, func(a, b renderedTag) int {
+			return cmp.Compare(a.Tag, b.Tag)
+		})
+	}
+	return tags, nil
+}
+
+// link returns the escaped link of the tag or an empty string without link template
+func (opts RenderOptions) link(tag string) string {
+	if opts.LinkTemplate == "" {
+		return ""
+	}
+	return strings.ReplaceAll(opts.LinkTemplate, "{tag}", url.PathEscape(tag))
+}
+
+// RenderHTML writes a <div> with a <span> of every top tag, font size of a span is set inline.
+// tags are HTML-escaped, links are URL-escaped
+func (cloud *TagCloud) RenderHTML(w io.Writer, opts RenderOptions) error {
+	tags, err := cloud.layout(opts)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(w)
+	fmt.Fprintln(writer, `<div class="tagcloud">`)
+	for _, tag := range tags {
+		text := html.EscapeString(tag.Tag)
+		if link := opts.link(tag.Tag); link != "" {
+			text = fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(link), text)
+		}
+		fmt.Fprintf(writer, "<span style=\"font-size: %.1fpx\" title=\"%d\">%s</span>\n", tag.FontSize, tag.OccurrenceCount, text)
+	}
+	fmt.Fprintln(writer, `</div>`)
+	return writer.Flush()
+}
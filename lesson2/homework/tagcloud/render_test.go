@@ -0,0 +1,63 @@
+package tagcloud_test
+
+import (
+	"bytes"
+	"lecture02_homework/tagcloud"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newRenderCloud() *tagcloud.TagCloud {
+	tc := tagcloud.New()
+	tc.AddTagN("go", 10)
+	tc.AddTagN("<script>alert(1)</script>", 4)
+	tc.AddTagN("c++ & c#", 7)
+	tc.AddTagN("тег", 1)
+	tc.AddTagN("rare", 1)
+	return tc
+}
+
+func assertGolden(t *testing.T, path string, actual []byte) {
+	t.Helper()
+	expected, err := os.ReadFile(path)
+	if assert.NoError(t, err) {
+		assert.Equal(t, string(expected), string(actual))
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	t.Run("ok, by count with links", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		err := newRenderCloud().RenderHTML(out, tagcloud.RenderOptions{N: 4, LinkTemplate: "/tags/{tag}?from=cloud"})
+
+		assert.NoError(t, err)
+		assertGolden(t, "testdata/render/links.html", out.Bytes())
+	})
+
+	t.Run("ok, alphabetical with custom sizes", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		err := newRenderCloud().RenderHTML(out, tagcloud.RenderOptions{Order: tagcloud.OrderAlphabetical, MinFontSize: 10, MaxFontSize: 20})
+
+		assert.NoError(t, err)
+		assertGolden(t, "testdata/render/alphabetical.html", out.Bytes())
+	})
+
+	t.Run("ok, empty cloud", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		assert.NoError(t, tagcloud.New().RenderHTML(out, tagcloud.RenderOptions{}))
+		assert.Equal(t, "<div class=\"tagcloud\">\n</div>\n", out.String())
+	})
+
+	t.Run("error, invalid options", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		assert.EqualError(t, newRenderCloud().RenderHTML(out, tagcloud.RenderOptions{MinFontSize: 30, MaxFontSize: 20}), "invalid font sizes 30..20")
+		assert.EqualError(t, newRenderCloud().RenderHTML(out, tagcloud.RenderOptions{Order: 5}), "unknown render order 5")
+		assert.Empty(t, out.String())
+	})
+}
@@ -0,0 +1,39 @@
+package tagcloud
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+)
+
+// WeightedSample returns n distinct tags sampled with probability proportional to their occurrence counts,
+// n greater than Len returns all tags in a weighted random order. the result depends only on the tags and rng,
+// n <= 0 gives an empty slice. every tag gets the key -ln(u)/count for a uniform u and tags with the smallest keys
+// are taken, so sampling costs O(m log m) for m tags
+func (cloud *TagCloud) WeightedSample(n int, rng *rand.Rand) []string {
+	if n <= 0 {
+		return []string{}
+	}
+	cloud.rlock()
+	// keys are drawn in TopN order, so the result doesn't depend on map iteration order
+	sorted := cloud.sortedStats()
+	type sampleKey struct {
+		tag string
+		key float64
+	}
+	keys := make([]sampleKey, len(sorted))
+	for i, stat := range sorted {
+		keys[i] = sampleKey{tag: stat.Tag, key: rng.ExpFloat64() / float64(stat.OccurrenceCount)}
+	}
+	cloud.runlock()
+
+	slices.SortStableFunc(keys, func(a, b sampleKey) int {
+		return cmp.Compare(a.key, b.key)
+	})
+	n = min(n, len(keys))
+	sample := make([]string, n)
+	for i := range sample {
+		sample[i] = keys[i].tag
+	}
+	return sample
+}
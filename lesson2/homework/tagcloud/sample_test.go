@@ -0,0 +1,63 @@
+package tagcloud_test
+
+import (
+	"lecture02_homework/tagcloud"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeightedSample(t *testing.T) {
+	t.Run("ok, frequencies follow weights", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"a": 5, "b": 3, "c": 2})
+		rng := rand.New(rand.NewSource(1))
+		const samples = 10000
+
+		first := map[string]int{}
+		for i := 0; i < samples; i++ {
+			sample := tc.WeightedSample(1, rng)
+			first[sample[0]]++
+		}
+
+		assert.InDelta(t, 0.5, float64(first["a"])/samples, 0.02)
+		assert.InDelta(t, 0.3, float64(first["b"])/samples, 0.02)
+		assert.InDelta(t, 0.2, float64(first["c"])/samples, 0.02)
+	})
+
+	t.Run("ok, without replacement", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"a": 5, "b": 3, "c": 2})
+		sample := tc.WeightedSample(2, rand.New(rand.NewSource(1)))
+
+		assert.Len(t, sample, 2)
+		assert.NotEqual(t, sample[0], sample[1])
+	})
+
+	t.Run("ok, deterministic for a seed", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"a": 5, "b": 3, "c": 2})
+		for _, tag := range []string{"d", "e", "f", "g"} {
+			tc.AddTag(tag)
+		}
+
+		first := tc.WeightedSample(4, rand.New(rand.NewSource(42)))
+		second := tc.Clone().WeightedSample(4, rand.New(rand.NewSource(42)))
+
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("ok, n greater than size shuffles all tags", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"a": 5, "b": 3, "c": 2})
+		sample := tc.WeightedSample(10, rand.New(rand.NewSource(1)))
+
+		assert.ElementsMatch(t, []string{"a", "b", "c"}, sample)
+	})
+
+	t.Run("ok, non-positive n and empty cloud", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"a": 5, "b": 3, "c": 2})
+		rng := rand.New(rand.NewSource(1))
+
+		assert.NotNil(t, tc.WeightedSample(0, rng))
+		assert.Empty(t, tc.WeightedSample(-1, rng))
+		assert.Empty(t, tagcloud.New().WeightedSample(5, rng))
+	})
+}
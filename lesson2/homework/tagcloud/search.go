@@ -0,0 +1,68 @@
+package tagcloud
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// TagsWithPrefix returns n most frequent tags starting with prefix in TopN order, empty prefix works as TopN.
+// the prefix is compared with normalized tags as is. tags sorted by name are cached until a tag is added or removed,
+// so a query only walks the tags with the prefix
+func (cloud *TagCloud) TagsWithPrefix(prefix string, n int) []TagStat {
+	cloud.rlock()
+	defer cloud.runlock()
+	if prefix == "" {
+		return cloud.topStats(n, 0)
+	}
+	names := cloud.sortedNames()
+	var stats []TagStat
+	for i := sort.SearchStrings(names, prefix); i < len(names) && strings.HasPrefix(names[i], prefix); i++ {
+		stats = append(stats, TagStat{Tag: names[i], OccurrenceCount: cloud.tags.Count(names[i])})
+	}
+	return cloud.firstStats(stats, n)
+}
+
+// TagsMatching returns n most frequent tags matching re in TopN order, every tag is checked
+func (cloud *TagCloud) TagsMatching(re *regexp.Regexp, n int) []TagStat {
+	cloud.rlock()
+	defer cloud.runlock()
+	var stats []TagStat
+	for tag, count := range cloud.tags.All() {
+		if re.MatchString(tag) {
+			stats = append(stats, TagStat{Tag: tag, OccurrenceCount: count})
+		}
+	}
+	return cloud.firstStats(stats, n)
+}
+
+// firstStats sorts stats in TopN order and returns first n of them
+func (cloud *TagCloud) firstStats(stats []TagStat, n int) []TagStat {
+	if n <= 0 {
+		return []TagStat{}
+	}
+	slices.SortFunc(stats, cloud.compare())
+	if len(stats) > n {
+		stats = stats[:n]
+	}
+	if stats == nil {
+		stats = []TagStat{}
+	}
+	cloud.fillErrorBounds(stats)
+	return stats
+}
+
+// sortedNames returns all tags sorted by tag, the result is shared and must not be changed
+func (cloud *TagCloud) sortedNames() []string {
+	cloud.sortedMu.Lock()
+	defer cloud.sortedMu.Unlock()
+	if cloud.names == nil {
+		cloud.names = make([]string, 0, cloud.tags.Len())
+		for tag := range cloud.tags.All() {
+			cloud.names = append(cloud.names, tag)
+		}
+		slices.Sort(cloud.names)
+	}
+	return cloud.names
+}
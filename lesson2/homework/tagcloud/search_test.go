@@ -0,0 +1,106 @@
+package tagcloud_test
+
+import (
+	"fmt"
+	"lecture02_homework/tagcloud"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagsWithPrefix(t *testing.T) {
+	t.Run("ok, most frequent matches", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{
+			"go": 5, "golang": 3, "gopher": 3, "goroutine": 1, "rust": 4, "Go": 2, "машинное обучение": 2, "машина": 6,
+		})
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "go", OccurrenceCount: 5},
+			{Tag: "golang", OccurrenceCount: 3},
+			{Tag: "gopher", OccurrenceCount: 3},
+		}, tc.TagsWithPrefix("go", 3))
+	})
+
+	t.Run("ok, prefix is case sensitive", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{
+			"go": 5, "golang": 3, "gopher": 3, "goroutine": 1, "rust": 4, "Go": 2, "машинное обучение": 2, "машина": 6,
+		})
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "Go", OccurrenceCount: 2}}, tc.TagsWithPrefix("G", 10))
+	})
+
+	t.Run("ok, unicode prefix", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{
+			"go": 5, "golang": 3, "gopher": 3, "goroutine": 1, "rust": 4, "Go": 2, "машинное обучение": 2, "машина": 6,
+		})
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "машина", OccurrenceCount: 6},
+			{Tag: "машинное обучение", OccurrenceCount: 2},
+		}, tc.TagsWithPrefix("маш", 10))
+	})
+
+	t.Run("ok, empty prefix works as TopN", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{
+			"go": 5, "golang": 3, "gopher": 3, "goroutine": 1, "rust": 4, "Go": 2, "машинное обучение": 2, "машина": 6,
+		})
+
+		assert.Equal(t, tc.TopN(4), tc.TagsWithPrefix("", 4))
+	})
+
+	t.Run("ok, no matches", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{
+			"go": 5, "golang": 3, "gopher": 3, "goroutine": 1, "rust": 4, "Go": 2, "машинное обучение": 2, "машина": 6,
+		})
+
+		assert.Empty(t, tc.TagsWithPrefix("python", 10))
+		assert.Empty(t, tc.TagsWithPrefix("go", 0))
+		assert.Empty(t, tagcloud.New().TagsWithPrefix("go", 10))
+	})
+
+	t.Run("ok, reflects added and removed tags", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{
+			"go": 5, "golang": 3, "gopher": 3, "goroutine": 1, "rust": 4, "Go": 2, "машинное обучение": 2, "машина": 6,
+		})
+		assert.Len(t, tc.TagsWithPrefix("go", 10), 4)
+
+		tc.AddTagN("goroutine", 10)
+		tc.AddTag("gofmt")
+		tc.DeleteTag("golang")
+
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "goroutine", OccurrenceCount: 11},
+			{Tag: "go", OccurrenceCount: 5},
+			{Tag: "gopher", OccurrenceCount: 3},
+			{Tag: "gofmt", OccurrenceCount: 1},
+		}, tc.TagsWithPrefix("go", 10))
+	})
+}
+
+func TestTagsMatching(t *testing.T) {
+	t.Run("ok, matching tags", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{
+			"go": 5, "golang": 3, "gopher": 3, "goroutine": 1, "rust": 4, "Go": 2, "машинное обучение": 2, "машина": 6,
+		})
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "rust", OccurrenceCount: 4},
+			{Tag: "golang", OccurrenceCount: 3},
+		}, tc.TagsMatching(regexp.MustCompile(`^(?:gol|ru)`), 10))
+	})
+
+	t.Run("ok, no matches", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{
+			"go": 5, "golang": 3, "gopher": 3, "goroutine": 1, "rust": 4, "Go": 2, "машинное обучение": 2, "машина": 6,
+		})
+		assert.Empty(t, tc.TagsMatching(regexp.MustCompile(`^\d+$`), 10))
+	})
+}
+
+func BenchmarkTagsWithPrefix(b *testing.B) {
+	tc := tagcloud.New()
+	for i := 0; i < 200000; i++ {
+		tc.AddTagN(fmt.Sprintf("tag%d", i), i%97+1)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tc.TagsWithPrefix("tag1234", 10)
+	}
+}
@@ -0,0 +1,170 @@
+package tagcloud
+
+import (
+	"container/heap"
+	"hash/maphash"
+	"runtime"
+	"sync/atomic"
+)
+
+// ShardedTagCloud is safe for concurrent use and spreads tags over independently locked shards,
+// so writers of different tags rarely wait for each other
+type ShardedTagCloud struct {
+	seed   maphash.Seed
+	shards []*TagCloud
+	// config normalizes tags to choose the shard, so different spellings of a tag never end up
+	// in different shards. shards get the original tags, so they keep surface forms like a single cloud
+	config *TagCloud
+}
+
+// NewSharded creates a sharded cloud with the given number of shards, non-positive number means GOMAXPROCS.
+// every shard is configured with the options, so limits such as WithMaxTags and WithAutoPrune apply
+// to each shard separately. changes recorded with WithOpLog are returned by Flush
+func NewSharded(shards int, options ...Option) *ShardedTagCloud {
+	if shards <= 0 {
+		shards = runtime.GOMAXPROCS(0)
+	}
+	cloud := &ShardedTagCloud{seed: maphash.MakeSeed(), shards: make([]*TagCloud, shards), config: newTagCloud(options)}
+	sequence := &atomic.Uint64{}
+	for i := range cloud.shards {
+		cloud.shards[i] = NewConcurrent(options...)
+		cloud.shards[i].sequence = sequence
+	}
+	return cloud
+}
+
+// shard returns the shard of a normalized tag
+func (cloud *ShardedTagCloud) shard(tag string) *TagCloud {
+	return cloud.shards[maphash.String(cloud.seed, tag)%uint64(len(cloud.shards))]
+}
+
+// AddTag adds a tag to its shard and increases tag occurrence count
+func (cloud *ShardedTagCloud) AddTag(tag string) {
+	if key, ok := cloud.config.key(tag); ok {
+		cloud.shard(key).AddTag(tag)
+	}
+}
+
+// AddTagN works the same as calling AddTag n times, non-positive n does nothing
+func (cloud *ShardedTagCloud) AddTagN(tag string, n int) {
+	if key, ok := cloud.config.key(tag); ok {
+		cloud.shard(key).AddTagN(tag, n)
+	}
+}
+
+// RemoveTag decreases tag occurrence count, removing an absent tag does nothing
+func (cloud *ShardedTagCloud) RemoveTag(tag string) {
+	cloud.shard(cloud.config.normalize(tag)).RemoveTag(tag)
+}
+
+// Count returns occurrence count of the tag, zero if the tag is absent
+func (cloud *ShardedTagCloud) Count(tag string) int {
+	return cloud.shard(cloud.config.normalize(tag)).Count(tag)
+}
+
+// Contains reports whether the tag is present in the cloud
+func (cloud *ShardedTagCloud) Contains(tag string) bool {
+	return cloud.shard(cloud.config.normalize(tag)).Contains(tag)
+}
+
+// Len returns the number of distinct tags.
+// shards are read one by one, so the result isn't a consistent snapshot under concurrent writes
+func (cloud *ShardedTagCloud) Len() int {
+	length := 0
+	for _, shard := range cloud.shards {
+		length += shard.Len()
+	}
+	return length
+}
+
+// Total returns the sum of all occurrence counts, with the same consistency as Len
+func (cloud *ShardedTagCloud) Total() int {
+	total := 0
+	for _, shard := range cloud.shards {
+		total += shard.Total()
+	}
+	return total
+}
+
+// TopN returns top n tags in the same order as TagCloud.TopN.
+// top n of every shard is taken and sorted results are merged using a heap.
+// with WithRecencyTieBreak shards number additions with one shared sequence, so ties are merged by recency too
+func (cloud *ShardedTagCloud) TopN(n int) []TagStat {
+	if n <= 0 {
+		return []TagStat{}
+	}
+	merge := statsHeap{lists: make([][]TagStat, 0, len(cloud.shards)), compare: compareTagStats}
+	var added map[string]uint64
+	if cloud.config.recencyTieBreak {
+		added = map[string]uint64{}
+		merge.compare = compareByRecency(added)
+	}
+	for _, shard := range cloud.shards {
+		top, shardAdded := shard.topNWithAdded(n)
+		for tag, seq := range shardAdded {
+			added[tag] = seq
+		}
+		if len(top) > 0 {
+			merge.lists = append(merge.lists, top)
+		}
+	}
+	heap.Init(&merge)
+	result := make([]TagStat, 0, n)
+	for len(result) < n && len(merge.lists) > 0 {
+		result = append(result, merge.lists[0][0])
+		merge.lists[0] = merge.lists[0][1:]
+		if len(merge.lists[0]) == 0 {
+			heap.Pop(&merge)
+		} else {
+			heap.Fix(&merge, 0)
+		}
+	}
+	return result
+}
+
+// topNWithAdded returns TopN of a shard and sequence numbers of the last addition of returned tags
+// taken under the same lock, see WithRecencyTieBreak
+func (cloud *TagCloud) topNWithAdded(n int) ([]TagStat, map[string]uint64) {
+	cloud.rlock()
+	defer cloud.runlock()
+	top := cloud.appendTopStats([]TagStat{}, n, 0)
+	return top, cloud.addedOf(top)
+}
+
+// Flush returns changes recorded by shards with WithOpLog since the previous Flush and forgets them.
+// changes of a tag keep their order, changes of different tags are grouped by shard
+func (cloud *ShardedTagCloud) Flush() []Op {
+	ops := []Op{}
+	for _, shard := range cloud.shards {
+		ops = append(ops, shard.Flush()...)
+	}
+	return ops
+}
+
+// statsHeap holds sorted non-empty stat lists ordered by their first element
+type statsHeap struct {
+	lists   [][]TagStat
+	compare func(a, b TagStat) int
+}
+
+func (h *statsHeap) Len() int {
+	return len(h.lists)
+}
+
+func (h *statsHeap) Less(i, j int) bool {
+	return h.compare(h.lists[i][0], h.lists[j][0]) < 0
+}
+
+func (h *statsHeap) Swap(i, j int) {
+	h.lists[i], h.lists[j] = h.lists[j], h.lists[i]
+}
+
+func (h *statsHeap) Push(x any) {
+	h.lists = append(h.lists, x.([]TagStat))
+}
+
+func (h *statsHeap) Pop() any {
+	last := h.lists[len(h.lists)-1]
+	h.lists = h.lists[:len(h.lists)-1]
+	return last
+}
@@ -0,0 +1,149 @@
+package tagcloud_test
+
+import (
+	"fmt"
+	"lecture02_homework/tagcloud"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShardedTagCloud(t *testing.T) {
+	t.Run("ok, same results as basic cloud", func(t *testing.T) {
+		for _, shards := range []int{0, 1, 3, 16} {
+			clouds := []tagcloud.Counter{tagcloud.New(), tagcloud.NewSharded(shards)}
+			rnd := rand.New(rand.NewSource(7))
+			for i := 0; i < 5000; i++ {
+				tag := fmt.Sprintf("t%d", rnd.Intn(100))
+				remove := rnd.Intn(5) == 0
+				n := rnd.Intn(3)
+				for _, cloud := range clouds {
+					if remove {
+						cloud.RemoveTag(tag)
+					} else {
+						cloud.AddTagN(tag, n)
+					}
+				}
+			}
+
+			basic, sharded := clouds[0], clouds[1]
+			for _, n := range []int{-1, 0, 1, 10, 1000} {
+				assert.Equal(t, basic.TopN(n), sharded.TopN(n), "TopN(%d) with %d shards", n, shards)
+			}
+			assert.Equal(t, basic.Len(), sharded.Len())
+			assert.Equal(t, basic.Total(), sharded.Total())
+			assert.Equal(t, basic.Count("t5"), sharded.Count("t5"))
+			assert.Equal(t, basic.Contains("t5"), sharded.Contains("t5"))
+		}
+	})
+
+	t.Run("ok, recency tie-break is merged across shards", func(t *testing.T) {
+		basic, sharded := tagcloud.New(tagcloud.WithRecencyTieBreak()), tagcloud.NewSharded(4, tagcloud.WithRecencyTieBreak())
+		for _, tag := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "c", "a"} {
+			basic.AddTag(tag)
+			sharded.AddTag(tag)
+		}
+
+		assert.Equal(t, basic.TopN(10), sharded.TopN(10))
+		assert.Equal(t, basic.TopN(3), sharded.TopN(3))
+	})
+
+	t.Run("ok, concurrent writers", func(t *testing.T) {
+		tc := tagcloud.NewSharded(4)
+
+		var wg sync.WaitGroup
+		for w := 0; w < 100; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				for i := 0; i < 200; i++ {
+					tc.AddTag(fmt.Sprintf("t%d", (w+i)%10))
+					if i%50 == 0 {
+						tc.TopN(3)
+					}
+				}
+			}(w)
+		}
+		wg.Wait()
+
+		assert.Equal(t, 20000, tc.Total())
+		for _, stat := range tc.TopN(10) {
+			assert.Equal(t, 2000, stat.OccurrenceCount)
+		}
+	})
+
+	t.Run("ok, shards are configured with options", func(t *testing.T) {
+		now := time.Unix(1000, 0)
+		clock := func() time.Time { return now }
+		options := []tagcloud.Option{tagcloud.WithTTL(time.Minute), tagcloud.WithClock(clock), tagcloud.WithMaxTags(2)}
+		basic, sharded := tagcloud.New(options...), tagcloud.NewSharded(1, options...)
+		for _, cloud := range []tagcloud.Counter{basic, sharded} {
+			cloud.AddTagN("go", 3)
+			cloud.AddTag("rust")
+			cloud.AddTag("c")
+		}
+
+		assert.Equal(t, basic.TopN(10), sharded.TopN(10))
+		assert.Equal(t, 2, sharded.Len())
+
+		now = now.Add(2 * time.Minute)
+		assert.Zero(t, sharded.Len())
+		assert.False(t, sharded.Contains("go"))
+	})
+
+	t.Run("ok, surface forms of stems", func(t *testing.T) {
+		options := []tagcloud.Option{tagcloud.WithCaseInsensitive(), tagcloud.WithEnglishStemming(), tagcloud.WithSurfaceForms()}
+		basic, sharded := tagcloud.New(options...), tagcloud.NewSharded(4, options...)
+		for _, cloud := range []tagcloud.Counter{basic, sharded} {
+			cloud.AddTagN("Running", 2)
+			cloud.AddTag("runs")
+		}
+
+		assert.Equal(t, 3, sharded.Count("run"))
+		assert.Equal(t, basic.TopN(10), sharded.TopN(10))
+	})
+
+	t.Run("ok, flushed changes replay the cloud", func(t *testing.T) {
+		tc := tagcloud.NewSharded(4, tagcloud.WithOpLog(), tagcloud.WithCaseInsensitive())
+		for i := 0; i < 100; i++ {
+			tc.AddTag(fmt.Sprintf("T%d", i%7))
+			if i%5 == 0 {
+				tc.RemoveTag(fmt.Sprintf("t%d", i%3))
+			}
+		}
+
+		replica := tagcloud.New()
+		assert.NoError(t, replica.Apply(tc.Flush()))
+
+		assert.Equal(t, tc.TopN(10), replica.TopN(10))
+		assert.Empty(t, tc.Flush())
+	})
+}
+
+func BenchmarkParallelAddTag(b *testing.B) {
+	clouds := map[string]func() tagcloud.Counter{
+		"mutex":   func() tagcloud.Counter { return tagcloud.NewConcurrent() },
+		"sharded": func() tagcloud.Counter { return tagcloud.NewSharded(0) },
+	}
+	tags := make([]string, 1024)
+	for i := range tags {
+		tags[i] = fmt.Sprintf("tag-%d", i)
+	}
+	for _, name := range []string{"mutex", "sharded"} {
+		b.Run(name, func(b *testing.B) {
+			cloud := clouds[name]()
+			var next atomic.Int64
+			b.RunParallel(func(pb *testing.PB) {
+				i := int(next.Add(1)) * 7919
+				for pb.Next() {
+					cloud.AddTag(tags[i%len(tags)])
+					i++
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package tagcloud
+
+import (
+	"cmp"
+	"slices"
+	"unicode/utf8"
+)
+
+// SuggestMergeRatio is how many times more occurrences a tag needs to be suggested as a merge target of a similar tag
+const SuggestMergeRatio = 5
+
+// MergeSuggestion proposes to merge the rare tag From into the similar frequent tag To
+type MergeSuggestion struct {
+	From     TagStat
+	To       TagStat
+	Distance int
+}
+
+// SimilarTags returns at most n tags within maxDistance edits of the tag, the tag itself isn't included.
+// the distance is Damerau-Levenshtein (optimal string alignment) over runes, a transposition of adjacent runes is one edit.
+// tags are ranked by distance, then by TopN order. n <= 0 or negative maxDistance give an empty slice
+func (cloud *TagCloud) SimilarTags(tag string, maxDistance int, n int) []TagStat {
+	if n <= 0 || maxDistance < 0 {
+		return []TagStat{}
+	}
+	tag = cloud.normalize(tag)
+	query := []rune(tag)
+	type similar struct {
+		stat     TagStat
+		distance int
+	}
+	var found []similar
+	var d distance
+	cloud.rlock()
+	for other, count := range cloud.tags.All() {
+		if other == tag {
+			continue
+		}
+		if dist := d.Within(query, other, maxDistance); dist <= maxDistance {
+			found = append(found, similar{stat: TagStat{Tag: other, OccurrenceCount: count, ErrorBound: cloud.errorBounds[other]}, distance: dist})
+		}
+	}
+	cloud.runlock()
+
+	slices.SortFunc(found, func(a, b similar) int {
+		if c := cmp.Compare(a.distance, b.distance); c != 0 {
+			return c
+		}
+		return compareTagStats(a.stat, b.stat)
+	})
+	result := make([]TagStat, 0, min(n, len(found)))
+	for _, s := range found[:min(n, len(found))] {
+		result = append(result, s.stat)
+	}
+	return result
+}
+
+// SuggestMerges finds tags within maxDistance edits of a tag with at least SuggestMergeRatio times more occurrences,
+// every rare tag is suggested once to its closest target, the most frequent one among equally close.
+// comparing all pairs is quadratic, so tags are compared only inside buckets of the same first rune,
+// typos in the first letter aren't found. suggestions are sorted by distance, then by From in TopN order
+func (cloud *TagCloud) SuggestMerges(maxDistance int) []MergeSuggestion {
+	if maxDistance < 0 {
+		return []MergeSuggestion{}
+	}
+	buckets := map[rune][]TagStat{}
+	cloud.rlock()
+	for tag, count := range cloud.tags.All() {
+		first, _ := utf8.DecodeRuneInString(tag)
+		buckets[first] = append(buckets[first], TagStat{Tag: tag, OccurrenceCount: count, ErrorBound: cloud.errorBounds[tag]})
+	}
+	cloud.runlock()
+
+	suggestions := []MergeSuggestion{}
+	var d distance
+	for _, bucket := range buckets {
+		// frequent tags go first, so the first close enough target is the most frequent one
+		slices.SortFunc(bucket, compareTagStats)
+		for i, rare := range bucket {
+			query := []rune(rare.Tag)
+			best := MergeSuggestion{Distance: maxDistance + 1}
+			for _, target := range bucket[:i] {
+				if target.OccurrenceCount < SuggestMergeRatio*rare.OccurrenceCount {
+					break
+				}
+				if dist := d.Within(query, target.Tag, maxDistance); dist < best.Distance {
+					best = MergeSuggestion{From: rare, To: target, Distance: dist}
+				}
+			}
+			if best.Distance <= maxDistance {
+				suggestions = append(suggestions, best)
+			}
+		}
+	}
+	slices.SortFunc(suggestions, func(a, b MergeSuggestion) int {
+		if c := cmp.Compare(a.Distance, b.Distance); c != 0 {
+			return c
+		}
+		return compareTagStats(a.From, b.From)
+	})
+	return suggestions
+}
+
+// distance computes edit distances reusing its rows between calls
+type distance struct {
+	target            []rune
+	prev, curr, older []int
+}
+
+// Within returns the optimal string alignment distance between a and b,
+// any result greater than limit means the strings are farther apart than limit
+func (d *distance) Within(a []rune, b string, limit int) int {
+	d.target = append(d.target[:0], []rune(b)...)
+	bs := d.target
+	if diff := len(a) - len(bs); diff > limit || -diff > limit {
+		return limit + 1
+	}
+	d.older = resize(d.older, len(bs)+1)
+	d.prev = resize(d.prev, len(bs)+1)
+	d.curr = resize(d.curr, len(bs)+1)
+	for j := range d.prev {
+		d.prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		d.curr[0] = i
+		rowMin := i
+		for j := 1; j <= len(bs); j++ {
+			cost := 1
+			if a[i-1] == bs[j-1] {
+				cost = 0
+			}
+			value := min(d.prev[j]+1, d.curr[j-1]+1, d.prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == bs[j-2] && a[i-2] == bs[j-1] {
+				value = min(value, d.older[j-2]+1)
+			}
+			d.curr[j] = value
+			rowMin = min(rowMin, value)
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		d.older, d.prev, d.curr = d.prev, d.curr, d.older
+	}
+	return d.prev[len(bs)]
+}
+
+func resize(row []int, n int) []int {
+	if cap(row) < n {
+		return make([]int, n)
+	}
+	return row[:n]
+}
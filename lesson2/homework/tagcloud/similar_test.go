@@ -0,0 +1,114 @@
+package tagcloud_test
+
+import (
+	"lecture02_homework/tagcloud"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimilarTags(t *testing.T) {
+	t.Run("ok, ranked by distance then count", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{
+			"kubernetes": 50, "kubernets": 3, "kuberentes": 2, "kubernete": 1, "golang": 40, "golnag": 2, "python": 30,
+			"pyhton": 10, "docker": 20,
+		})
+
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "kubernets", OccurrenceCount: 3},
+			{Tag: "kuberentes", OccurrenceCount: 2},
+			{Tag: "kubernete", OccurrenceCount: 1},
+		}, tc.SimilarTags("kubernetes", 1, 10))
+	})
+
+	t.Run("ok, absent tag", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{
+			"kubernetes": 50, "kubernets": 3, "kuberentes": 2, "kubernete": 1, "golang": 40, "golnag": 2, "python": 30,
+			"pyhton": 10, "docker": 20,
+		})
+
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "kubernetes", OccurrenceCount: 50},
+			{Tag: "kubernets", OccurrenceCount: 3},
+		}, tc.SimilarTags("kubernetse", 1, 2))
+	})
+
+	t.Run("ok, transposition is one edit", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{
+			"kubernetes": 50, "kubernets": 3, "kuberentes": 2, "kubernete": 1, "golang": 40, "golnag": 2, "python": 30,
+			"pyhton": 10, "docker": 20,
+		})
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "golnag", OccurrenceCount: 2}}, tc.SimilarTags("golang", 1, 10))
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "python", OccurrenceCount: 30}}, tc.SimilarTags("pyhton", 1, 10))
+	})
+
+	t.Run("ok, runes", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag("машина")
+		tc.AddTag("машины")
+		tc.AddTag("мошины")
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "машины", OccurrenceCount: 1}}, tc.SimilarTags("машина", 1, 10))
+	})
+
+	t.Run("ok, empty results", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{
+			"kubernetes": 50, "kubernets": 3, "kuberentes": 2, "kubernete": 1, "golang": 40, "golnag": 2, "python": 30,
+			"pyhton": 10, "docker": 20,
+		})
+
+		assert.Equal(t, []tagcloud.TagStat{}, tc.SimilarTags("kubernetes", 1, 0))
+		assert.Equal(t, []tagcloud.TagStat{}, tc.SimilarTags("kubernetes", -1, 10))
+		assert.Equal(t, []tagcloud.TagStat{}, tc.SimilarTags("kubernetes", 0, 10))
+		assert.Equal(t, []tagcloud.TagStat{}, tc.SimilarTags("rust", 1, 10))
+	})
+}
+
+func TestSuggestMerges(t *testing.T) {
+	t.Run("ok, rare typos", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{
+			"kubernetes": 50, "kubernets": 3, "kuberentes": 2, "kubernete": 1, "golang": 40, "golnag": 2, "python": 30,
+			"pyhton": 10, "docker": 20,
+		})
+		kubernetes := tagcloud.TagStat{Tag: "kubernetes", OccurrenceCount: 50}
+
+		assert.Equal(t, []tagcloud.MergeSuggestion{
+			{From: tagcloud.TagStat{Tag: "kubernets", OccurrenceCount: 3}, To: kubernetes, Distance: 1},
+			{From: tagcloud.TagStat{Tag: "golnag", OccurrenceCount: 2}, To: tagcloud.TagStat{Tag: "golang", OccurrenceCount: 40}, Distance: 1},
+			{From: tagcloud.TagStat{Tag: "kuberentes", OccurrenceCount: 2}, To: kubernetes, Distance: 1},
+			{From: tagcloud.TagStat{Tag: "kubernete", OccurrenceCount: 1}, To: kubernetes, Distance: 1},
+		}, tc.SuggestMerges(1))
+	})
+
+	t.Run("ok, closest target wins over more frequent", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("colour", 100)
+		tc.AddTagN("color", 60)
+		tc.AddTagN("colr", 10)
+
+		assert.Equal(t, []tagcloud.MergeSuggestion{{
+			From:     tagcloud.TagStat{Tag: "colr", OccurrenceCount: 10},
+			To:       tagcloud.TagStat{Tag: "color", OccurrenceCount: 60},
+			Distance: 1,
+		}}, tc.SuggestMerges(2))
+	})
+
+	t.Run("ok, typo in first letter isn't found", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("golang", 100)
+		tc.AddTag("kolang")
+
+		assert.Equal(t, []tagcloud.MergeSuggestion{}, tc.SuggestMerges(1))
+	})
+
+	t.Run("ok, no suggestions", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{
+			"kubernetes": 50, "kubernets": 3, "kuberentes": 2, "kubernete": 1, "golang": 40, "golnag": 2, "python": 30,
+			"pyhton": 10, "docker": 20,
+		})
+		assert.Equal(t, []tagcloud.MergeSuggestion{}, tc.SuggestMerges(-1))
+		assert.Equal(t, []tagcloud.MergeSuggestion{}, tc.SuggestMerges(0))
+		assert.Equal(t, []tagcloud.MergeSuggestion{}, tagcloud.New().SuggestMerges(2))
+	})
+}
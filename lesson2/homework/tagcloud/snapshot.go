@@ -0,0 +1,214 @@
+package tagcloud
+
+import (
+	"hash/maphash"
+	"iter"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// snapshotBuckets is the number of buckets holding counts of a cloud
+const snapshotBuckets = 1024
+
+// countBucket holds counts of keys with the same hash, gen is the generation of bucketCounts
+// when the bucket was created. a bucket of an older generation may be referenced by snapshots,
+// so it's cloned before changing
+type countBucket[K comparable] struct {
+	counts map[K]int
+	gen    uint64
+}
+
+// bucketCounts stores counts split into copy-on-write buckets by the hash of a key,
+// so a snapshot copies only references to buckets. the zero value is empty and ready to use
+type bucketCounts[K comparable] struct {
+	buckets []*countBucket[K]
+	seed    maphash.Seed
+	length  int
+	gen     uint64
+}
+
+func (counts *bucketCounts[K]) bucketIndex(key K) int {
+	return int(maphash.Comparable(counts.seed, key) % snapshotBuckets)
+}
+
+// get returns the count of the key and whether it's present
+func (counts *bucketCounts[K]) get(key K) (int, bool) {
+	if counts.buckets == nil {
+		return 0, false
+	}
+	bucket := counts.buckets[counts.bucketIndex(key)]
+	if bucket == nil {
+		return 0, false
+	}
+	count, ok := bucket.counts[key]
+	return count, ok
+}
+
+// set changes the count of the key cloning its bucket if it's shared, zero count removes the key
+func (counts *bucketCounts[K]) set(key K, count int) {
+	if counts.buckets == nil {
+		if count == 0 {
+			return
+		}
+		counts.buckets = make([]*countBucket[K], snapshotBuckets)
+		counts.seed = maphash.MakeSeed()
+	}
+	i := counts.bucketIndex(key)
+	bucket := counts.buckets[i]
+	switch {
+	case bucket == nil:
+		if count == 0 {
+			return
+		}
+		bucket = &countBucket[K]{counts: map[K]int{}, gen: counts.gen}
+		counts.buckets[i] = bucket
+	case bucket.gen < counts.gen:
+		bucket = &countBucket[K]{counts: maps.Clone(bucket.counts), gen: counts.gen}
+		counts.buckets[i] = bucket
+	}
+	_, ok := bucket.counts[key]
+	switch {
+	case count == 0 && ok:
+		delete(bucket.counts, key)
+		counts.length--
+	case count != 0:
+		bucket.counts[key] = count
+		if !ok {
+			counts.length++
+		}
+	}
+}
+
+// all yields every key with its count in no particular order, the counts may be changed while iterating
+func (counts *bucketCounts[K]) all() iter.Seq2[K, int] {
+	buckets := counts.buckets
+	return func(yield func(K, int) bool) {
+		for _, bucket := range buckets {
+			if bucket == nil {
+				continue
+			}
+			for key, count := range bucket.counts {
+				if !yield(key, count) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// clone returns a deep copy of counts
+func (counts *bucketCounts[K]) clone() bucketCounts[K] {
+	cloned := bucketCounts[K]{seed: counts.seed, length: counts.length}
+	if counts.buckets == nil {
+		return cloned
+	}
+	cloned.buckets = make([]*countBucket[K], snapshotBuckets)
+	for i, bucket := range counts.buckets {
+		if bucket != nil {
+			cloned.buckets[i] = &countBucket[K]{counts: maps.Clone(bucket.counts)}
+		}
+	}
+	return cloned
+}
+
+// share returns read-only counts referencing the same buckets, later changes of counts clone the buckets they touch
+func (counts *bucketCounts[K]) share() bucketCounts[K] {
+	counts.gen++
+	shared := *counts
+	shared.buckets = slices.Clone(counts.buckets)
+	return shared
+}
+
+// compact rebuilds buckets sized to their current number of keys and drops empty ones
+func (counts *bucketCounts[K]) compact() {
+	if counts.length == 0 {
+		*counts = bucketCounts[K]{}
+		return
+	}
+	for i, bucket := range counts.buckets {
+		switch {
+		case bucket == nil:
+		case len(bucket.counts) == 0:
+			counts.buckets[i] = nil
+		default:
+			counts.buckets[i] = &countBucket[K]{counts: maps.Clone(bucket.counts), gen: counts.gen}
+		}
+	}
+}
+
+// TagCloudSnapshot is a read-only copy of a cloud, it's safe for concurrent use without any locking
+type TagCloudSnapshot struct {
+	counts  Cloud[string]
+	compare func(a, b TagStat) int
+	// sorted holds all stats in TopN order, it's built by the first call of TopN or All
+	sorted     []TagStat
+	sortedOnce sync.Once
+	// keys is an empty cloud normalizing tags like the original one, it isn't concurrent, so it takes no locks
+	keys *TagCloud
+}
+
+// Snapshot returns a read-only view of current counts, later changes of the cloud are not visible in the snapshot.
+// counts are kept by the cloud in copy-on-write buckets, so taking a snapshot copies only references to them
+// and a cloud change copies a bucket referenced by a snapshot once. the first TopN or All of a snapshot
+// sorts its tags. with WithRecencyTieBreak the order of additions is copied by every Snapshot
+func (cloud *TagCloud) Snapshot() *TagCloudSnapshot {
+	cloud.lock()
+	snapshot := &TagCloudSnapshot{
+		counts:  cloud.tags.share(),
+		compare: cloud.compareCopy(),
+	}
+	cloud.unlock()
+	snapshot.keys = &TagCloud{aliases: cloud.copyAliases(), cloudConfig: cloud.cloudConfig}
+	return snapshot
+}
+
+// sortedStats returns all stats in TopN order sorting them on the first call
+func (snapshot *TagCloudSnapshot) sortedStats() []TagStat {
+	snapshot.sortedOnce.Do(func() {
+		snapshot.sorted = make([]TagStat, 0, snapshot.counts.Len())
+		for tag, count := range snapshot.counts.All() {
+			snapshot.sorted = append(snapshot.sorted, TagStat{Tag: tag, OccurrenceCount: count})
+		}
+		slices.SortFunc(snapshot.sorted, snapshot.compare)
+	})
+	return snapshot.sorted
+}
+
+// TopN returns top n most frequent tags in the same order as TagCloud.TopN
+func (snapshot *TagCloudSnapshot) TopN(n int) []TagStat {
+	sorted := snapshot.sortedStats()
+	if n > len(sorted) {
+		n = len(sorted)
+	}
+	if n < 0 {
+		n = 0
+	}
+	return append([]TagStat{}, sorted[:n]...)
+}
+
+// Count returns occurrence count of the tag normalized as by the original cloud, 0 if it's absent
+func (snapshot *TagCloudSnapshot) Count(tag string) int {
+	return snapshot.counts.Count(snapshot.keys.normalize(tag))
+}
+
+// Len returns the number of distinct tags
+func (snapshot *TagCloudSnapshot) Len() int {
+	return snapshot.counts.Len()
+}
+
+// Total returns the sum of all occurrence counts
+func (snapshot *TagCloudSnapshot) Total() int {
+	return snapshot.counts.Total()
+}
+
+// All yields every tag in TopN order
+func (snapshot *TagCloudSnapshot) All() iter.Seq[TagStat] {
+	return func(yield func(TagStat) bool) {
+		for _, stat := range snapshot.sortedStats() {
+			if !yield(stat) {
+				return
+			}
+		}
+	}
+}
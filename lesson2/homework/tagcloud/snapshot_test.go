@@ -0,0 +1,174 @@
+package tagcloud_test
+
+import (
+	"fmt"
+	"lecture02_homework/tagcloud"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+	t.Run("ok, isolated from later changes", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("go", 2)
+		tc.AddTag("rust")
+
+		snapshot := tc.Snapshot()
+		tc.AddTagN("rust", 5)
+		tc.AddTag("c")
+		tc.DeleteTag("go")
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "go", OccurrenceCount: 2}, {Tag: "rust", OccurrenceCount: 1}}, snapshot.TopN(10))
+		assert.Equal(t, 2, snapshot.Count("go"))
+		assert.Equal(t, 0, snapshot.Count("c"))
+		assert.Equal(t, 2, snapshot.Len())
+		assert.Equal(t, 3, snapshot.Total())
+	})
+
+	t.Run("ok, same order as the cloud", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTags("b", "a", "c", "c", "d")
+		snapshot := tc.Snapshot()
+
+		var all []tagcloud.TagStat
+		for stat := range snapshot.All() {
+			all = append(all, stat)
+		}
+
+		assert.Equal(t, tc.TopN(10), all)
+		assert.Equal(t, tc.TopN(2), snapshot.TopN(2))
+		assert.Empty(t, snapshot.TopN(-1))
+	})
+
+	t.Run("ok, tags are normalized like in the cloud", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithCaseInsensitive())
+		tc.AddTagN("Golang", 3)
+		assert.NoError(t, tc.AddAlias("go", "golang"))
+
+		snapshot := tc.Snapshot()
+
+		assert.Equal(t, 3, snapshot.Count(" GO "))
+	})
+
+	t.Run("ok, concurrent readers and writer", func(t *testing.T) {
+		tc := tagcloud.NewConcurrent()
+		tc.AddTagN("go", 10)
+		snapshot := tc.Snapshot()
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				tc.AddTag(fmt.Sprint("tag", i%10))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				assert.Equal(t, 10, snapshot.Count("go"))
+				assert.Len(t, snapshot.TopN(5), 1)
+			}
+		}()
+		wg.Wait()
+	})
+
+	t.Run("ok, concurrent snapshots and writers", func(t *testing.T) {
+		tc := tagcloud.NewConcurrent()
+		for i := 0; i < 5000; i++ {
+			tc.AddTag(fmt.Sprint("tag", i))
+		}
+		var wg sync.WaitGroup
+		for w := 0; w < 4; w++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 2000; i++ {
+					tag := fmt.Sprint("tag", (i*7+w)%6000)
+					if i%5 == 0 {
+						tc.DeleteTag(tag)
+					} else {
+						tc.AddTag(tag)
+					}
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 20; i++ {
+					snapshot := tc.Snapshot()
+					before := snapshot.Count("tag1")
+					total, length := 0, 0
+					for stat := range snapshot.All() {
+						total += stat.OccurrenceCount
+						length++
+						if stat.Tag == "tag1" {
+							assert.Equal(t, before, stat.OccurrenceCount)
+						}
+					}
+					assert.Equal(t, snapshot.Total(), total)
+					assert.Equal(t, snapshot.Len(), length)
+					assert.Equal(t, before, snapshot.Count("tag1"))
+				}
+			}()
+		}
+		wg.Wait()
+
+		snapshot := tc.Snapshot()
+		assert.Equal(t, tc.TopN(tc.Len()), snapshot.TopN(tc.Len()))
+	})
+
+	t.Run("ok, reset after snapshot", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("go", 2)
+		first := tc.Snapshot()
+		tc.Reset()
+		tc.AddTag("rust")
+		second := tc.Snapshot()
+		tc.AddTag("rust")
+
+		assert.Equal(t, 2, first.Count("go"))
+		assert.Equal(t, 0, first.Count("rust"))
+		assert.Equal(t, 0, second.Count("go"))
+		assert.Equal(t, 1, second.Count("rust"))
+		assert.Equal(t, 2, tc.Snapshot().Count("rust"))
+	})
+
+	t.Run("ok, empty cloud", func(t *testing.T) {
+		snapshot := tagcloud.New().Snapshot()
+
+		assert.Empty(t, snapshot.TopN(10))
+		assert.Equal(t, 0, snapshot.Len())
+	})
+}
+
+func BenchmarkSnapshot(b *testing.B) {
+	// snapshots share buckets with the cloud, so the latency doesn't depend on the size.
+	// the first snapshot is measured on a fresh clone made for every iteration, cloning isn't timed,
+	// but it's slow for large clouds, so run it with a fixed -benchtime like 100x
+	for _, size := range []int{1000, 100000, 1000000} {
+		tc := tagcloud.NewConcurrent()
+		for i := 0; i < size; i++ {
+			tc.AddTagN(fmt.Sprintf("tag-%d", i), i%1000+1)
+		}
+		b.Run(fmt.Sprintf("size=%d/first snapshot", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				fresh := tc.Clone()
+				b.StartTimer()
+				fresh.Snapshot()
+			}
+		})
+		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				tc.Snapshot()
+			}
+		})
+		b.Run(fmt.Sprintf("size=%d/with write", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				tc.Snapshot()
+				tc.AddTag("tag-1")
+			}
+		})
+	}
+}
@@ -1,46 +1,296 @@
 package tagcloud
 
 import (
-	"slices"
+	"bufio"
+	"cmp"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/text/unicode/norm"
 )
 
-// TagCloud aggregates statistics about used tags
+// TagCloud aggregates statistics about used tags. it's a wrapper over Cloud[string] counting normalized tags,
+// which adds normalization, ordering and other string specific features.
+// the zero value is an empty cloud without options ready to use, so a TagCloud can be embedded or declared
+// with var instead of New. all methods have pointer receivers and a cloud must not be copied after first use,
+// go vet reports copies as the cloud holds a mutex
 type TagCloud struct {
-	tags map[string]int
+	// tags is the counting core of TagCloud, its buckets are shared with snapshots, see Snapshot.
+	// TagCloud changes it only by setCount and replaceTags
+	tags Cloud[string]
+	// extraWeights holds weight of a tag above its occurrence count, see AddWeighted
+	extraWeights map[string]float64
+	// pairs counts co-occurrence of tags, see AddTagSet
+	pairs map[tagPair]int
+	// aliases maps a cleaned tag to its canonical tag, they are resolved before the cloud is locked,
+	// so concurrent clouds guard them with aliasMu
+	aliases map[string]string
+	aliasMu sync.RWMutex
+	// concurrent clouds guard all methods with mu, see NewConcurrent
+	concurrent bool
+	mu         sync.RWMutex
+	// sorted caches all stats in TopN order until the next change, see TopNFrom.
+	// readers of a concurrent cloud fill it under the read lock, so it has its own mutex
+	sorted   []TagStat
+	sortedMu sync.Mutex
+	// names caches all tags sorted by tag until a tag is added or removed, see TagsWithPrefix.
+	// it's guarded by sortedMu as well
+	names []string
+	// ordered keeps all tags in TopN order, it's set only for clouds created by NewOrdered
+	ordered *skipList
+	// bounded keeps the least frequent tag on top for eviction and errorBounds holds counts inherited
+	// from evicted tags, both are set only with WithMaxTags
+	bounded     *candidateHeap
+	errorBounds map[string]int
+	// thresholds are registered by OnThreshold, their callbacks are collected in events
+	// and called when the cloud is unlocked
+	thresholds []*threshold
+	events     []thresholdEvent
+	// recent orders tags by the time they were last added, it's set only with WithTTL
+	recent *recency
+	// namespaces holds counts of tags by namespace, it's set only with WithNamespace
+	namespaces map[string]map[string]int
+	// added holds the sequence number of the last addition of every tag, it's set only with WithRecencyTieBreak
+	added map[string]uint64
+	seq   uint64
+	// seen holds the times of the first and the last addition of every tag, it's set only with WithTimestamps
+	seen map[string]seenTimes
+	// sketch is maintained only with WithQuantileSketch
+	sketch *countSketch
+	// surfaces counts added forms of every stem, see WithSurfaceForms
+	surfaces map[string]map[string]int
+	// peaks hold the largest counts of tags in the cloud and retiredPeaks of removed tags, see WithHighWaterMarks
+	peaks        map[string]int
+	retiredPeaks map[string]int
+	// velocity counts additions of tags in the bucket started at velocityStart, see WithVelocity
+	velocity      map[string]int
+	velocityStart time.Time
+	// ops are changes recorded since the last Flush, they are recorded only with WithOpLog
+	ops []Op
+	// additions counts additions since the last automatic pruning, see WithAutoPrune
+	additions int
+	// peak is the largest number of tags since the cloud was filled or compacted, see Compact
+	peak int
+	cloudConfig
+}
+
+// cloudConfig is set by options and never changes after the cloud is created
+type cloudConfig struct {
+	normalizers []func(string) string
+	stopWords   []string
+	stop        map[string]struct{}
+	strict      bool
+	maxTagLen   int
+	// validators are added by WithValidator, invalidBucket is set by WithInvalidBucket
+	validators    []func(string) error
+	invalidBucket bool
+	split         bufio.SplitFunc
+	maxTags       int
+	maxTagSet     int
+	ttl           time.Duration
+	clock         func() time.Time
+	// separator splits namespaces, see WithNamespace
+	separator       string
+	recencyTieBreak bool
+	// sequence is shared by the shards of a sharded cloud, so their WithRecencyTieBreak orders are comparable
+	sequence *atomic.Uint64
+	// autoCompact is the ratio of WithAutoCompact
+	autoCompact float64
+	timestamps  bool
+	opLog       bool
+	pruneMin    int
+	pruneEvery  int
+	// unicodeForm is applied if unicodeNormalization is set by WithUnicodeNormalization
+	unicodeNormalization bool
+	unicodeForm          norm.Form
+	// quantileSketch is set by WithQuantileSketch
+	quantileSketch bool
+	// stemmer is set by WithStemmer
+	stemmer      func(string) string
+	surfaceForms bool
+	// highWaterMarks is set by WithHighWaterMarks
+	highWaterMarks bool
+	// velocityBucket is set by WithVelocity
+	velocityBucket time.Duration
 }
 
 // TagStat represents statistics regarding single tag
 type TagStat struct {
 	Tag             string
 	OccurrenceCount int
+	// Frequency is the share of the tag in all occurrences, it's filled only by TopNWithFrequency
+	Frequency float64
+	// ErrorBound is the part of OccurrenceCount which may belong to evicted tags, see WithMaxTags
+	ErrorBound int
+	// DisplayForm is the most frequent added form of a stem, it's filled only by clouds with WithSurfaceForms
+	DisplayForm string
 }
 
 // New should create a valid TagCloud instance
-func New() *TagCloud {
-	return &TagCloud{tags: map[string]int{}}
+// the cloud isn't safe for concurrent use, see NewConcurrent
+// options configure tag preprocessing, New without options counts tags as is
+func New(options ...Option) *TagCloud {
+	return newTagCloud(options)
 }
 
 // AddTag should add a tag to the cloud if it wasn't present and increase tag occurrence count
-// thread-safety is not needed
+// safe for concurrent use only if the cloud is created by NewConcurrent
 func (cloud *TagCloud) AddTag(tag string) {
-	cloud.tags[tag]++
+	key, ok := cloud.key(tag)
+	if !ok {
+		return
+	}
+	cloud.lock()
+	defer cloud.unlock()
+	cloud.setCount(key, cloud.tags.Count(key)+1)
+	cloud.noteSurface(tag, key, 1)
+}
+
+// setCount and replaceTags are the only places where counts are changed,
+// a tag with zero count is removed from the cloud
+func (cloud *TagCloud) setCount(tag string, count int) {
+	if count < 0 {
+		count = 0
+	}
+	// the tag is looked up once, eviction never removes the tag itself
+	old, ok := cloud.tags.get(tag)
+	if !ok && count > 0 && cloud.maxTags > 0 && cloud.tags.Len() >= cloud.maxTags {
+		count += cloud.evict(tag)
+	}
+	cloud.recordOp(tag, old, count)
+	cloud.sorted = nil
+	if ok == (count == 0) {
+		cloud.names = nil
+	}
+	if cloud.ordered != nil {
+		switch {
+		case ok && count > 0:
+			cloud.ordered.Update(TagStat{Tag: tag, OccurrenceCount: old}, TagStat{Tag: tag, OccurrenceCount: count})
+		case ok:
+			cloud.ordered.Remove(TagStat{Tag: tag, OccurrenceCount: old})
+		case count > 0:
+			cloud.ordered.Insert(TagStat{Tag: tag, OccurrenceCount: count})
+		}
+	}
+	if cloud.bounded != nil {
+		cloud.bounded.Update(tag, count)
+	}
+	cloud.updateSketch(old, count)
+	if count > old {
+		cloud.checkThresholds(tag, old, count)
+		cloud.touch(tag)
+		cloud.markAdded(tag)
+		cloud.markSeen(tag)
+		cloud.markVelocity(tag, count-old)
+		if cloud.pruneEvery > 0 {
+			cloud.additions++
+		}
+	}
+	cloud.setNamespaceCount(tag, count)
+	cloud.updatePeak(tag, count)
+	cloud.tags.set(tag, count)
+	if count == 0 {
+		delete(cloud.extraWeights, tag)
+		delete(cloud.errorBounds, tag)
+		cloud.deletePairs(tag)
+		if cloud.recent != nil {
+			cloud.recent.Forget(tag)
+		}
+		delete(cloud.added, tag)
+		delete(cloud.seen, tag)
+		delete(cloud.surfaces, tag)
+		delete(cloud.velocity, tag)
+		return
+	}
+	cloud.peak = max(cloud.peak, cloud.tags.Len())
+}
+
+func (cloud *TagCloud) replaceTags(tags map[string]int) {
+	cloud.recordReplace(tags)
+	cloud.tags = newCloudOf(tags)
+	cloud.extraWeights = nil
+	cloud.errorBounds = nil
+	cloud.pairs = nil
+	cloud.sorted = nil
+	cloud.names = nil
+	cloud.recent = nil
+	cloud.namespaces = nil
+	cloud.added = nil
+	cloud.seen = nil
+	cloud.surfaces = nil
+	cloud.peaks = nil
+	cloud.retiredPeaks = nil
+	cloud.velocity = nil
+	cloud.indexTags()
+}
+
+// indexTags rebuilds namespaces, ordering of an ordered cloud, eviction heap of a bounded cloud, quantile sketch,
+// peaks of tags and expiration order of a cloud with TTL after tags are replaced, the replaced tags start a new peak
+func (cloud *TagCloud) indexTags() {
+	cloud.peak = cloud.tags.Len()
+	if cloud.highWaterMarks {
+		for tag, count := range cloud.tags.All() {
+			cloud.updatePeak(tag, count)
+		}
+	}
+	if cloud.separator != "" {
+		cloud.namespaces = nil
+		for tag, count := range cloud.tags.All() {
+			cloud.setNamespaceCount(tag, count)
+		}
+	}
+	if cloud.ttl > 0 && cloud.recent == nil {
+		for tag := range cloud.tags.All() {
+			cloud.touch(tag)
+		}
+	}
+	if cloud.ordered != nil {
+		cloud.ordered = newSkipList()
+		for tag, count := range cloud.tags.All() {
+			cloud.ordered.Insert(TagStat{Tag: tag, OccurrenceCount: count})
+		}
+	}
+	if cloud.quantileSketch {
+		cloud.sketch = nil
+		for _, count := range cloud.tags.All() {
+			cloud.updateSketch(0, count)
+		}
+	}
+	if cloud.maxTags > 0 {
+		cloud.bounded = newCandidateHeap(0)
+		for tag, count := range cloud.tags.All() {
+			cloud.bounded.Update(tag, count)
+		}
+		for cloud.tags.Len() > cloud.maxTags {
+			cloud.setCount(cloud.bounded.stats[0].Tag, 0)
+		}
+	}
 }
 
 // TopN should return top N most frequent tags ordered in descending order by occurrence count
-// if there are multiple tags with the same occurrence count then the order is defined by implementation
+// if there are multiple tags with the same occurrence count then they are ordered lexicographically by tag,
+// so the result is the same between runs
 // if n is greater that TagCloud size then all elements should be returned
-// thread-safety is not needed
+// n <= 0 gives an empty slice, the result is never nil
+// safe for concurrent use only if the cloud is created by NewConcurrent
 // there are no restrictions on time complexity
 func (cloud *TagCloud) TopN(n int) []TagStat {
-	tags := make([]TagStat, 0, len(cloud.tags))
-	for tag, count := range cloud.tags {
-		tags = append(tags, TagStat{Tag: tag, OccurrenceCount: count})
-	}
-	slices.SortFunc(tags, func(a, b TagStat) int {
-		return b.OccurrenceCount - a.OccurrenceCount
-	})
-	if len(tags) < n {
-		n = len(tags)
+	return cloud.AppendTopN([]TagStat{}, n)
+}
+
+// AppendTopN appends the TopN result to dst and returns the extended slice like append,
+// dst with enough spare capacity is reused, so repeated calls with the same buffer don't allocate
+func (cloud *TagCloud) AppendTopN(dst []TagStat, n int) []TagStat {
+	cloud.rlock()
+	defer cloud.runlock()
+	return cloud.appendTopStats(dst, n, 0)
+}
+
+// compareTagStats orders stats by occurrence count descending and then by tag
+func compareTagStats(a, b TagStat) int {
+	if c := cmp.Compare(b.OccurrenceCount, a.OccurrenceCount); c != 0 {
+		return c
 	}
-	return tags[:n]
+	return cmp.Compare(a.Tag, b.Tag)
 }
@@ -82,3 +82,25 @@ func TestTopNWithRepeatedOccurrence(t *testing.T) {
 
 	assert.Len(t, distinctMap, requestCount, "TopN(%d) returned array with non-distinct tags: %v", requestCount, topN)
 }
+
+func TestTopNTieBreak(t *testing.T) {
+	expected := []tagcloud.TagStat{
+		{Tag: "alpha", OccurrenceCount: 2},
+		{Tag: "bravo", OccurrenceCount: 2},
+		{Tag: "charlie", OccurrenceCount: 2},
+		{Tag: "delta", OccurrenceCount: 2},
+		{Tag: "echo", OccurrenceCount: 2},
+	}
+
+	for run := 0; run < 100; run++ {
+		tc := tagcloud.New()
+		for _, tag := range []string{"echo", "charlie", "alpha", "delta", "bravo"} {
+			tc.AddTag(tag)
+			tc.AddTag(tag)
+		}
+		tc.AddTag("zulu")
+
+		topN := tc.TopN(5)
+		assert.Equal(t, expected, topN, "TopN(5) returned unstable order on run %d", run)
+	}
+}
@@ -0,0 +1,73 @@
+package tagcloud
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// ErrEmptyCloud is returned by statistics which aren't defined for a cloud without tags
+var ErrEmptyCloud = errors.New("cloud is empty")
+
+// Mean returns the average occurrence count of a tag, 0 for an empty cloud
+func (cloud *TagCloud) Mean() float64 {
+	cloud.rlock()
+	defer cloud.runlock()
+	if cloud.tags.Len() == 0 {
+		return 0
+	}
+	return float64(cloud.tags.Total()) / float64(cloud.tags.Len())
+}
+
+// Median returns the middle occurrence count, the mean of two middle counts for even number of tags
+// and 0 for an empty cloud
+func (cloud *TagCloud) Median() float64 {
+	counts := cloud.sortedCounts()
+	if len(counts) == 0 {
+		return 0
+	}
+	middle := len(counts) / 2
+	if len(counts)%2 == 1 {
+		return float64(counts[middle])
+	}
+	return float64(counts[middle-1]+counts[middle]) / 2
+}
+
+// Percentile returns the occurrence count at the p-th percentile using the nearest-rank method,
+// p must be within [0, 100], 0 gives the smallest count. ErrEmptyCloud is returned for an empty cloud
+func (cloud *TagCloud) Percentile(p float64) (int, error) {
+	if math.IsNaN(p) || p < 0 || p > 100 {
+		return 0, fmt.Errorf("percentile %v is out of range [0, 100]", p)
+	}
+	counts := cloud.sortedCounts()
+	if len(counts) == 0 {
+		return 0, ErrEmptyCloud
+	}
+	return counts[nearestRank(p/100, len(counts))-1], nil
+}
+
+// Entropy returns Shannon entropy in bits of the tag frequency distribution, 0 for an empty cloud.
+// it's log2 of the number of tags when all tags are equally frequent and decreases as few tags dominate
+func (cloud *TagCloud) Entropy() float64 {
+	cloud.rlock()
+	defer cloud.runlock()
+	entropy := 0.0
+	for _, count := range cloud.tags.All() {
+		p := float64(count) / float64(cloud.tags.Total())
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// sortedCounts returns occurrence counts in ascending order
+func (cloud *TagCloud) sortedCounts() []int {
+	cloud.rlock()
+	counts := make([]int, 0, cloud.tags.Len())
+	for _, count := range cloud.tags.All() {
+		counts = append(counts, count)
+	}
+	cloud.runlock()
+	slices.Sort(counts)
+	return counts
+}
@@ -0,0 +1,93 @@
+package tagcloud_test
+
+import (
+	"lecture02_homework/tagcloud"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMean(t *testing.T) {
+	t.Run("ok, small distribution", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"a": 1, "b": 2, "c": 3, "d": 10})
+		assert.Equal(t, 4.0, tc.Mean())
+	})
+
+	t.Run("ok, empty cloud", func(t *testing.T) {
+		assert.Equal(t, 0.0, tagcloud.New().Mean())
+	})
+}
+
+func TestMedian(t *testing.T) {
+	t.Run("ok, even number of tags", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"a": 1, "b": 2, "c": 3, "d": 10})
+		assert.Equal(t, 2.5, tc.Median())
+	})
+
+	t.Run("ok, odd number of tags", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"a": 1, "b": 2, "c": 3, "d": 10})
+		tc.AddTagN("e", 7)
+
+		assert.Equal(t, 3.0, tc.Median())
+	})
+
+	t.Run("ok, empty cloud", func(t *testing.T) {
+		assert.Equal(t, 0.0, tagcloud.New().Median())
+	})
+}
+
+func TestPercentile(t *testing.T) {
+	t.Run("ok, nearest rank", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"a": 1, "b": 2, "c": 3, "d": 10})
+		for p, expected := range map[float64]int{0: 1, 10: 1, 25: 1, 26: 2, 50: 2, 75: 3, 76: 10, 100: 10} {
+			count, err := tc.Percentile(p)
+
+			assert.NoError(t, err)
+			assert.Equal(t, expected, count, "percentile %v", p)
+		}
+	})
+
+	t.Run("error, empty cloud", func(t *testing.T) {
+		count, err := tagcloud.New().Percentile(50)
+
+		assert.ErrorIs(t, err, tagcloud.ErrEmptyCloud)
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("error, out of range", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"a": 1, "b": 2, "c": 3, "d": 10})
+		for _, p := range []float64{-1, 100.5, math.NaN()} {
+			_, err := tc.Percentile(p)
+
+			assert.Error(t, err)
+		}
+	})
+}
+
+func TestEntropy(t *testing.T) {
+	t.Run("ok, uniform distribution", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTags("a", "b", "c", "d")
+
+		assert.InDelta(t, 2.0, tc.Entropy(), 1e-9)
+	})
+
+	t.Run("ok, skewed distribution", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("a", 2)
+		tc.AddTag("b")
+		tc.AddTag("c")
+
+		// -(1/2*log2(1/2) + 2 * 1/4*log2(1/4)) = 1.5
+		assert.InDelta(t, 1.5, tc.Entropy(), 1e-9)
+	})
+
+	t.Run("ok, single tag and empty cloud", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("a", 5)
+
+		assert.Equal(t, 0.0, tc.Entropy())
+		assert.Equal(t, 0.0, tagcloud.New().Entropy())
+	})
+}
@@ -0,0 +1,106 @@
+package tagcloud
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// WithStemmer makes the cloud count the stem of every tag returned by stemmer, it's applied after normalizers,
+// so lookups by any form of a word find its stem. tags merged from a cloud with a stemmer aren't stemmed again
+func WithStemmer(stemmer func(string) string) Option {
+	return func(cloud *TagCloud) {
+		cloud.stemmer = stemmer
+	}
+}
+
+// WithEnglishStemming stems tags by the Porter algorithm, e.g. running and runs are counted as run.
+// irregular forms like ran aren't stemmed, only lower case words are, so it's usually combined with WithCaseInsensitive
+func WithEnglishStemming() Option {
+	return WithStemmer(porterStem)
+}
+
+// WithSurfaceForms makes the cloud remember how many times every form of a stem was added,
+// so TagStat.DisplayForm of a stem shows its most frequent form, e.g. "running (stem: run)"
+func WithSurfaceForms() Option {
+	return func(cloud *TagCloud) {
+		cloud.surfaceForms = true
+	}
+}
+
+// stem applies the stemmer to a cleaned tag
+func (cloud *TagCloud) stem(tag string) string {
+	if cloud.stemmer == nil {
+		return tag
+	}
+	return cloud.stemmer(tag)
+}
+
+// copiedKey returns the key of a tag copied from another cloud, stems of a cloud with a stemmer aren't stemmed again
+func (cloud *TagCloud) copiedKey(tag string, stemmed bool) (string, bool) {
+	if !stemmed {
+		return cloud.key(tag)
+	}
+	key, err := cloud.checkKey(tag, cloud.resolve(cloud.clean(tag)))
+	return key, err == nil
+}
+
+// noteSurface counts the cleaned but not stemmed form of a tag added n times as key
+func (cloud *TagCloud) noteSurface(tag, key string, n int) {
+	if !cloud.surfaceForms || n <= 0 {
+		return
+	}
+	cloud.addSurface(key, cloud.clean(tag), n)
+}
+
+func (cloud *TagCloud) addSurface(key, surface string, n int) {
+	if _, ok := cloud.tags.get(key); !ok {
+		// the tag was dropped by validation or evicted
+		return
+	}
+	if cloud.surfaces == nil {
+		cloud.surfaces = map[string]map[string]int{}
+	}
+	forms := cloud.surfaces[key]
+	if forms == nil {
+		forms = map[string]int{}
+		cloud.surfaces[key] = forms
+	}
+	forms[surface] += n
+}
+
+// displayForm returns the most frequent surface form of the tag, ties are broken by the smaller form
+func (cloud *TagCloud) displayForm(tag string) string {
+	form, best := tag, 0
+	for surface, count := range cloud.surfaces[tag] {
+		if count > best || count == best && cmp.Less(surface, form) {
+			form, best = surface, count
+		}
+	}
+	if form == tag {
+		return tag
+	}
+	return fmt.Sprintf("%s (stem: %s)", form, tag)
+}
+
+func (cloud *TagCloud) fillDisplayForms(stats []TagStat) {
+	if !cloud.surfaceForms {
+		return
+	}
+	for i := range stats {
+		stats[i].DisplayForm = cloud.displayForm(stats[i].Tag)
+	}
+}
+
+func (cloud *TagCloud) copySurfaces() map[string]map[string]int {
+	if len(cloud.surfaces) == 0 {
+		return nil
+	}
+	surfaces := make(map[string]map[string]int, len(cloud.surfaces))
+	for tag, forms := range cloud.surfaces {
+		surfaces[tag] = make(map[string]int, len(forms))
+		for surface, count := range forms {
+			surfaces[tag][surface] = count
+		}
+	}
+	return surfaces
+}
@@ -0,0 +1,77 @@
+package tagcloud_test
+
+import (
+	"lecture02_homework/tagcloud"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithEnglishStemming(t *testing.T) {
+	t.Run("ok, porter stems", func(t *testing.T) {
+		stems := map[string]string{
+			"caresses": "caress", "ponies": "poni", "cats": "cat", "agreed": "agre", "plastered": "plaster",
+			"motoring": "motor", "sing": "sing", "conflated": "conflat", "sized": "size", "hopping": "hop",
+			"falling": "fall", "fizzed": "fizz", "filing": "file", "happy": "happi", "sky": "sky",
+			"relational": "relat", "conditional": "condit", "rational": "ration", "digitizer": "digit",
+			"vietnamization": "vietnam", "operator": "oper", "decisiveness": "decis", "hopefulness": "hope",
+			"sensibiliti": "sensibl", "triplicate": "triplic", "formative": "form", "electrical": "electr",
+			"goodness": "good", "allowance": "allow", "replacement": "replac", "adoption": "adopt",
+			"homologous": "homolog", "effective": "effect", "bowdlerize": "bowdler", "cease": "ceas",
+			"controll": "control", "roll": "roll", "generalizations": "gener", "go": "go",
+		}
+		for word, stem := range stems {
+			tc := tagcloud.New(tagcloud.WithEnglishStemming())
+			tc.AddTag(word)
+
+			assert.Equal(t, []tagcloud.TagStat{{Tag: stem, OccurrenceCount: 1}}, tc.TopN(1), word)
+		}
+	})
+
+	t.Run("ok, inflections of a paragraph", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithCaseInsensitive(), tagcloud.WithEnglishStemming(), tagcloud.WithSurfaceForms())
+		paragraph := "Running is fun. She runs daily and ran yesterday, running late again. " +
+			"Runs are connected: connecting runs, connection of running."
+
+		_, err := tc.AddFromReader(strings.NewReader(paragraph))
+		require.NoError(t, err)
+
+		top := tc.TopN(3)
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "run", OccurrenceCount: 6, DisplayForm: "running (stem: run)"},
+			{Tag: "connect", OccurrenceCount: 3, DisplayForm: "connected (stem: connect)"},
+			{Tag: "again", OccurrenceCount: 1, DisplayForm: "again"},
+		}, top)
+		assert.Equal(t, top[1:], tc.TopNFrom(1, 2))
+		assert.Equal(t, top, slices.Collect(tc.SortedAll())[:3])
+		assert.Equal(t, 1, tc.Count("ran"))
+		assert.Equal(t, 6, tc.Count("RUNS"))
+	})
+
+	t.Run("ok, stemmed clouds are merged as is", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithEnglishStemming(), tagcloud.WithSurfaceForms())
+		tc.AddTags("agreed", "agreed", "agreement")
+		other := tagcloud.New(tagcloud.WithEnglishStemming(), tagcloud.WithSurfaceForms())
+
+		other.MergeInto(tc)
+		other.MergeInto(tc.Clone())
+
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "agre", OccurrenceCount: 4, DisplayForm: "agreed (stem: agre)"},
+			{Tag: "agreement", OccurrenceCount: 2, DisplayForm: "agreement"},
+		}, other.TopN(10))
+	})
+}
+
+func TestWithStemmer(t *testing.T) {
+	tc := tagcloud.New(tagcloud.WithStemmer(func(tag string) string {
+		return strings.TrimSuffix(tag, "s")
+	}), tagcloud.WithStopWords("tags"))
+	tc.AddTags(" tags ", "cats", "cat", "dogs")
+
+	assert.Equal(t, []tagcloud.TagStat{{Tag: "cat", OccurrenceCount: 2}, {Tag: "dog", OccurrenceCount: 1}}, tc.TopN(10))
+	assert.Equal(t, 2, tc.Count("cats"))
+}
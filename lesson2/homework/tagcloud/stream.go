@@ -0,0 +1,149 @@
+package tagcloud
+
+import (
+	"bufio"
+	"container/heap"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Format defines how WriteTopN encodes tags
+type Format int
+
+const (
+	// JSONLines writes a {"tag": ..., "count": ...} object per line
+	JSONLines Format = iota
+	// JSONArray writes the same objects as a single JSON array
+	JSONArray
+	// TSV writes "tag<TAB>count" lines, tags containing a tab or a line feed are reported as an error
+	TSV
+)
+
+// WriteTopN writes top n tags in TopN order one by one, n <= 0 writes all tags.
+// for n > 0 only n tags are selected like by TopN, all tags are copied into a heap which is cheaper than sorting them.
+// the cloud is locked only while tags are selected or copied, and tags are encoded straight into a buffered writer,
+// so no encoded copy of the whole cloud is kept in memory
+func (cloud *TagCloud) WriteTopN(w io.Writer, n int, format Format) error {
+	if format != JSONLines && format != JSONArray && format != TSV {
+		return fmt.Errorf("unknown format %d", format)
+	}
+	cloud.rlock()
+	if n > 0 {
+		top := cloud.appendTopStats([]TagStat{}, n, 0)
+		cloud.runlock()
+		return writeStats(w, format, len(top), func(i int) TagStat {
+			return top[i]
+		})
+	}
+	stats := &statHeap{stats: make([]TagStat, 0, cloud.tags.Len())}
+	for tag, count := range cloud.tags.All() {
+		stats.stats = append(stats.stats, TagStat{Tag: tag, OccurrenceCount: count})
+	}
+	cloud.fillErrorBounds(stats.stats)
+	compare := cloud.compareCopy()
+	cloud.runlock()
+	// statHeap keeps the worst stat on top, so the order is reversed to pop the best one
+	stats.compare = func(a, b TagStat) int {
+		return -compare(a, b)
+	}
+	heap.Init(stats)
+	return writeStats(w, format, stats.Len(), func(int) TagStat {
+		return popStat(stats)
+	})
+}
+
+// writeStats writes n stats returned by stat in the format
+func writeStats(w io.Writer, format Format, n int, stat func(i int) TagStat) error {
+	writer := bufio.NewWriter(w)
+	if format == JSONArray {
+		writer.WriteString("[")
+	}
+	var buf []byte
+	for i := 0; i < n; i++ {
+		stat := stat(i)
+		if format == TSV && strings.ContainsAny(stat.Tag, "\t\n") {
+			return fmt.Errorf("tag %q can't be written as TSV", stat.Tag)
+		}
+		buf = appendStat(buf[:0], stat, format, i)
+		writer.Write(buf)
+	}
+	if format == JSONArray {
+		writer.WriteString("]\n")
+	}
+	return writer.Flush()
+}
+
+// popStat removes the top stat of the heap, it avoids boxing of heap.Pop
+func popStat(stats *statHeap) TagStat {
+	top := stats.stats[0]
+	last := len(stats.stats) - 1
+	stats.stats[0] = stats.stats[last]
+	stats.stats = stats.stats[:last]
+	if last > 0 {
+		heap.Fix(stats, 0)
+	}
+	return top
+}
+
+// appendStat appends i-th stat in the format
+func appendStat(buf []byte, stat TagStat, format Format, i int) []byte {
+	if format == TSV {
+		buf = append(buf, stat.Tag...)
+		buf = append(buf, '\t')
+		buf = strconv.AppendInt(buf, int64(stat.OccurrenceCount), 10)
+		return append(buf, '\n')
+	}
+	if format == JSONArray && i > 0 {
+		buf = append(buf, ',')
+	}
+	buf = append(buf, `{"tag":`...)
+	buf = appendJSONString(buf, stat.Tag)
+	buf = append(buf, `,"count":`...)
+	buf = strconv.AppendInt(buf, int64(stat.OccurrenceCount), 10)
+	buf = append(buf, '}')
+	if format == JSONLines {
+		buf = append(buf, '\n')
+	}
+	return buf
+}
+
+// appendJSONString appends s as a JSON string escaped the same way as by encoding/json
+func appendJSONString(buf []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+	buf = append(buf, '"')
+	for i := 0; i < len(s); {
+		b := s[i]
+		if b < utf8.RuneSelf {
+			switch {
+			case b == '"' || b == '\\':
+				buf = append(buf, '\\', b)
+			case b == '\n':
+				buf = append(buf, '\\', 'n')
+			case b == '\r':
+				buf = append(buf, '\\', 'r')
+			case b == '\t':
+				buf = append(buf, '\\', 't')
+			case b < 0x20 || b == '<' || b == '>' || b == '&':
+				buf = append(buf, '\\', 'u', '0', '0', hex[b>>4], hex[b&0xF])
+			default:
+				buf = append(buf, b)
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			buf = append(buf, string(utf8.RuneError)...)
+		case r == '\u2028' || r == '\u2029':
+			buf = append(buf, '\\', 'u', '2', '0', '2', hex[r&0xF])
+		default:
+			buf = append(buf, s[i:i+size]...)
+		}
+		i += size
+	}
+	return append(buf, '"')
+}
@@ -0,0 +1,140 @@
+package tagcloud_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"lecture02_homework/tagcloud"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTopN(t *testing.T) {
+	t.Run("ok, JSON lines", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"go": 3, "rust": 2, "a \"quoted\" tag": 2, "c": 1})
+		out := &bytes.Buffer{}
+
+		err := tc.WriteTopN(out, 2, tagcloud.JSONLines)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "{\"tag\":\"go\",\"count\":3}\n{\"tag\":\"a \\\"quoted\\\" tag\",\"count\":2}\n", out.String())
+	})
+
+	t.Run("ok, JSON array of all tags", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"go": 3, "rust": 2, "a \"quoted\" tag": 2, "c": 1})
+		out := &bytes.Buffer{}
+
+		err := tc.WriteTopN(out, 0, tagcloud.JSONArray)
+
+		require.NoError(t, err)
+		var decoded []struct {
+			Tag   string `json:"tag"`
+			Count int    `json:"count"`
+		}
+		require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+		assert.Len(t, decoded, 4)
+		assert.Equal(t, "go", decoded[0].Tag)
+		assert.Equal(t, "c", decoded[3].Tag)
+	})
+
+	t.Run("ok, TSV", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"go": 3, "rust": 2, "a \"quoted\" tag": 2, "c": 1})
+		out := &bytes.Buffer{}
+
+		err := tc.WriteTopN(out, 10, tagcloud.TSV)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "go\t3\na \"quoted\" tag\t2\nrust\t2\nc\t1\n", out.String())
+	})
+
+	t.Run("ok, same order as TopN", func(t *testing.T) {
+		tc := tagcloud.New()
+		for i := 0; i < 500; i++ {
+			tc.AddTagN(fmt.Sprint("tag", i), i%7+1)
+		}
+		out := &bytes.Buffer{}
+		expected := &bytes.Buffer{}
+		for _, stat := range tc.TopN(100) {
+			fmt.Fprintf(expected, "%s\t%d\n", stat.Tag, stat.OccurrenceCount)
+		}
+
+		err := tc.WriteTopN(out, 100, tagcloud.TSV)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected.String(), out.String())
+	})
+
+	t.Run("ok, escaping matches encoding/json", func(t *testing.T) {
+		for _, tag := range []string{"a\\b", "<b>&", "line\nfeed\r\t\x01", "\xff\xfe", "машина 😊", "\u2028\u2029"} {
+			tc := tagcloud.New(tagcloud.WithNormalizer(func(tag string) string { return "x" + tag + "x" }))
+			tc.AddTag(tag)
+			out := &bytes.Buffer{}
+			expected, err := json.Marshal(map[string]any{"tag": "x" + tag + "x"})
+			require.NoError(t, err)
+
+			require.NoError(t, tc.WriteTopN(out, 0, tagcloud.JSONLines))
+
+			assert.Equal(t, string(expected[:len(expected)-1])+",\"count\":1}\n", out.String(), "tag %q", tag)
+		}
+	})
+
+	t.Run("ok, empty cloud", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		assert.NoError(t, tagcloud.New().WriteTopN(out, 0, tagcloud.JSONArray))
+		assert.Equal(t, "[]\n", out.String())
+	})
+
+	t.Run("error, tag with a tab in TSV", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag("a\tb")
+
+		assert.Error(t, tc.WriteTopN(io.Discard, 0, tagcloud.TSV))
+	})
+
+	t.Run("error, unknown format", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"go": 3, "rust": 2, "a \"quoted\" tag": 2, "c": 1})
+		assert.Error(t, tc.WriteTopN(io.Discard, 0, tagcloud.Format(42)))
+	})
+
+	t.Run("error, failing writer", func(t *testing.T) {
+		tc := tagcloud.FromMap(map[string]int{"go": 3, "rust": 2, "a \"quoted\" tag": 2, "c": 1})
+		assert.Error(t, tc.WriteTopN(failingWriter{}, 0, tagcloud.JSONLines))
+	})
+}
+
+func BenchmarkWriteTopN(b *testing.B) {
+	tc := tagcloud.New()
+	for i := 0; i < 1000000; i++ {
+		tc.AddTagN(fmt.Sprintf("tag-%d", i), i%1000+1)
+	}
+	b.Run("WriteTopN", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = tc.WriteTopN(io.Discard, 0, tagcloud.JSONLines)
+		}
+	})
+	for _, size := range []int{1000, 1000000} {
+		// allocations of top 10 don't depend on the size of the cloud
+		cloud := tagcloud.New()
+		for i := 0; i < size; i++ {
+			cloud.AddTagN(fmt.Sprintf("tag-%d", i), i%1000+1)
+		}
+		b.Run(fmt.Sprintf("WriteTopN 10 of %d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = cloud.WriteTopN(io.Discard, 10, tagcloud.JSONLines)
+			}
+		})
+	}
+	b.Run("TopN and Marshal", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			data, _ := json.Marshal(tc.TopN(tc.Len()))
+			_, _ = io.Discard.Write(data)
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package tagcloud
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+const (
+	// svgCharWidth approximates width of a rune as a share of the font size
+	svgCharWidth = 0.6
+	// svgLineHeight is the height of a row as a share of its largest font size
+	svgLineHeight = 1.2
+	// svgPadding is the gap in pixels around the cloud and between tags in a row
+	svgPadding = 8
+)
+
+// RenderSVG writes a standalone SVG image of width x height pixels with tags laid out in rows.
+// a row is wrapped when the next tag doesn't fit, tags which don't fit into the height are skipped.
+// the output is the same for the same cloud and options
+func (cloud *TagCloud) RenderSVG(w io.Writer, width, height int, opts RenderOptions) error {
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("invalid SVG size %dx%d", width, height)
+	}
+	tags, err := cloud.layout(opts)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(w)
+	fmt.Fprintln(writer, `<?xml version="1.0" encoding="UTF-8"?>`)
+	fmt.Fprintf(writer, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n", width, height, width, height)
+
+	// row holds tags of the current row with their x positions until the row height is known
+	type placed struct {
+		renderedTag
+		x float64
+	}
+	var row []placed
+	x, top, rowSize := float64(svgPadding), float64(svgPadding), 0.0
+	flush := func() bool {
+		if len(row) == 0 {
+			return true
+		}
+		bottom := top + rowSize*svgLineHeight
+		if bottom > float64(height-svgPadding) {
+			return false
+		}
+		for _, tag := range row {
+			writeSVGText(writer, tag.renderedTag, tag.x, bottom-rowSize*(svgLineHeight-1), opts)
+		}
+		row, x, top, rowSize = row[:0], svgPadding, bottom, 0
+		return true
+	}
+	for _, tag := range tags {
+		tagWidth := float64(utf8.RuneCountInString(tag.Tag)) * tag.FontSize * svgCharWidth
+		if len(row) > 0 && x+tagWidth > float64(width-svgPadding) && !flush() {
+			break
+		}
+		row = append(row, placed{renderedTag: tag, x: x})
+		x += tagWidth + svgPadding
+		if tag.FontSize > rowSize {
+			rowSize = tag.FontSize
+		}
+	}
+	flush()
+	fmt.Fprintln(writer, `</svg>`)
+	return writer.Flush()
+}
+
+func writeSVGText(writer *bufio.Writer, tag renderedTag, x, y float64, opts RenderOptions) {
+	link := opts.link(tag.Tag)
+	if link != "" {
+		writer.WriteString(`<a href="`)
+		xml.EscapeText(writer, []byte(link))
+		writer.WriteString(`">`)
+	}
+	fmt.Fprintf(writer, `<text x="%.1f" y="%.1f" font-size="%.1f">`, x, y, tag.FontSize)
+	xml.EscapeText(writer, []byte(tag.Tag))
+	writer.WriteString(`</text>`)
+	if link != "" {
+		writer.WriteString(`</a>`)
+	}
+	writer.WriteString("\n")
+}
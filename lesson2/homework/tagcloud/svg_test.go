@@ -0,0 +1,60 @@
+package tagcloud_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"lecture02_homework/tagcloud"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func assertValidXML(t *testing.T, data []byte) {
+	t.Helper()
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			return
+		}
+		if !assert.NoError(t, err) {
+			return
+		}
+	}
+}
+
+func TestRenderSVG(t *testing.T) {
+	t.Run("ok, rows are wrapped", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		err := newRenderCloud().RenderSVG(out, 300, 200, tagcloud.RenderOptions{LinkTemplate: "/tags/{tag}"})
+
+		assert.NoError(t, err)
+		assertValidXML(t, out.Bytes())
+		assertGolden(t, "testdata/render/cloud.svg", out.Bytes())
+	})
+
+	t.Run("ok, output is deterministic", func(t *testing.T) {
+		first, second := &bytes.Buffer{}, &bytes.Buffer{}
+
+		assert.NoError(t, newRenderCloud().RenderSVG(first, 300, 200, tagcloud.RenderOptions{}))
+		assert.NoError(t, newRenderCloud().RenderSVG(second, 300, 200, tagcloud.RenderOptions{}))
+
+		assert.Equal(t, first.String(), second.String())
+	})
+
+	t.Run("ok, tags which don't fit are skipped", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		err := newRenderCloud().RenderSVG(out, 120, 60, tagcloud.RenderOptions{})
+
+		assert.NoError(t, err)
+		assertValidXML(t, out.Bytes())
+		assertGolden(t, "testdata/render/small.svg", out.Bytes())
+	})
+
+	t.Run("error, invalid size", func(t *testing.T) {
+		assert.EqualError(t, newRenderCloud().RenderSVG(io.Discard, 0, 100, tagcloud.RenderOptions{}), "invalid SVG size 0x100")
+	})
+}
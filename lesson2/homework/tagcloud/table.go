@@ -0,0 +1,71 @@
+package tagcloud
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// stringTopN is the number of top tags shown by String
+const stringTopN = 5
+
+// String summarizes the cloud for debugging, e.g. TagCloud{distinct: 412, total: 9,301, top: go(120), http(88)}
+func (cloud *TagCloud) String() string {
+	cloud.rlock()
+	distinct, total := cloud.tags.Len(), cloud.tags.Total()
+	stats := cloud.topStats(stringTopN, 0)
+	cloud.runlock()
+
+	builder := &strings.Builder{}
+	fmt.Fprintf(builder, "TagCloud{distinct: %s, total: %s", groupThousands(distinct), groupThousands(total))
+	if len(stats) > 0 {
+		builder.WriteString(", top: ")
+		for i, stat := range stats {
+			if i > 0 {
+				builder.WriteString(", ")
+			}
+			fmt.Fprintf(builder, "%s(%s)", stat.Tag, groupThousands(stat.OccurrenceCount))
+		}
+		if distinct > len(stats) {
+			builder.WriteString(", ...")
+		}
+	}
+	builder.WriteString("}")
+	return builder.String()
+}
+
+// groupThousands formats n with comma separated groups of digits
+func groupThousands(n int) string {
+	digits := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	var groups []string
+	for len(digits) > 3 {
+		groups = append([]string{digits[len(digits)-3:]}, groups...)
+		digits = digits[:len(digits)-3]
+	}
+	groups = append([]string{digits}, groups...)
+	return sign + strings.Join(groups, ",")
+}
+
+// WriteTable writes top n tags as an aligned table with TAG and COUNT columns,
+// non-positive n writes all tags. columns are aligned by rune count, so wide characters may shift them
+func (cloud *TagCloud) WriteTable(w io.Writer, n int) error {
+	cloud.rlock()
+	if n <= 0 {
+		n = cloud.tags.Len()
+	}
+	stats := cloud.topStats(n, 0)
+	cloud.runlock()
+
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "TAG\tCOUNT")
+	for _, stat := range stats {
+		fmt.Fprintf(writer, "%s\t%d\n", stat.Tag, stat.OccurrenceCount)
+	}
+	return writer.Flush()
+}
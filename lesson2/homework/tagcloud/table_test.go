@@ -0,0 +1,81 @@
+package tagcloud_test
+
+import (
+	"bytes"
+	"errors"
+	"lecture02_homework/tagcloud"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestString(t *testing.T) {
+	t.Run("ok, top is capped", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("go", 1200)
+		tc.AddTagN("http", 88)
+		tc.AddTags("a", "b", "c", "d")
+
+		assert.Equal(t, "TagCloud{distinct: 6, total: 1,292, top: go(1,200), http(88), a(1), b(1), c(1), ...}", tc.String())
+	})
+
+	t.Run("ok, small cloud", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("go", 2)
+
+		assert.Equal(t, "TagCloud{distinct: 1, total: 2, top: go(2)}", tc.String())
+	})
+
+	t.Run("ok, empty cloud", func(t *testing.T) {
+		assert.Equal(t, "TagCloud{distinct: 0, total: 0}", tagcloud.New().String())
+	})
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestWriteTable(t *testing.T) {
+	newTableCloud := func() *tagcloud.TagCloud {
+		tc := tagcloud.New()
+		tc.AddTagN("go", 120)
+		tc.AddTagN("машинное обучение", 88)
+		tc.AddTagN("日本語", 7)
+		tc.AddTagN("😊", 3)
+		tc.AddTag("é")
+		return tc
+	}
+
+	t.Run("ok, unicode tags", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		err := newTableCloud().WriteTable(out, 0)
+
+		assert.NoError(t, err)
+		assertGolden(t, "testdata/table/unicode.txt", out.Bytes())
+	})
+
+	t.Run("ok, top n", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		err := newTableCloud().WriteTable(out, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "TAG                COUNT\ngo                 120\nмашинное обучение  88\n", out.String())
+	})
+
+	t.Run("ok, empty cloud", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		err := tagcloud.New().WriteTable(out, 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "TAG  COUNT\n", out.String())
+	})
+
+	t.Run("error, failing writer", func(t *testing.T) {
+		assert.Error(t, newTableCloud().WriteTable(failingWriter{}, 10))
+	})
+}
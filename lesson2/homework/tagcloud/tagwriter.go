@@ -0,0 +1,103 @@
+package tagcloud
+
+import (
+	"bufio"
+	"errors"
+)
+
+// errTagWriterClosed is returned by Write after Close
+var errTagWriterClosed = errors.New("tag writer is closed")
+
+// TagWriter is an io.Writer adding tokens of written bytes to a cloud, see CollectorWriter
+type TagWriter struct {
+	cloud *TagCloud
+	split bufio.SplitFunc
+	// pending holds a copy of written bytes which aren't split into tokens yet
+	pending []byte
+	added   int
+	err     error
+	closed  bool
+	final   bool
+}
+
+// CollectorWriter returns a writer splitting written bytes into tokens by tokenizer and adding every token
+// by AddTag, so a cloud is filled while the data is copied elsewhere, e.g. as a destination of io.Copy,
+// io.MultiWriter or the copy pipeline of lesson3. nil tokenizer means the split function of the cloud
+// as in AddFromReader. a token may be split between Write calls, written slices are never retained.
+// Close must be called after the last Write to add the last token
+func CollectorWriter(cloud *TagCloud, tokenizer bufio.SplitFunc) *TagWriter {
+	if tokenizer == nil {
+		tokenizer = cloud.split
+	}
+	if tokenizer == nil {
+		tokenizer = ScanTokens
+	}
+	return &TagWriter{cloud: cloud, split: tokenizer}
+}
+
+// Write adds complete tokens of p and keeps the rest until the next Write or Close.
+// it fails if the tokenizer fails or a token is longer than 16 MiB
+func (c *TagWriter) Write(p []byte) (int, error) {
+	if c.closed {
+		return 0, errTagWriterClosed
+	}
+	if c.err != nil {
+		return 0, c.err
+	}
+	if c.final {
+		return len(p), nil
+	}
+	c.pending = append(c.pending, p...)
+	c.scan(false)
+	if c.err == nil && len(c.pending) > maxTokenSize {
+		c.err = bufio.ErrTooLong
+	}
+	return len(p), c.err
+}
+
+// Close adds the last token, it returns the error of the tokenizer if any
+func (c *TagWriter) Close() error {
+	if !c.closed && c.err == nil && !c.final {
+		c.scan(true)
+	}
+	c.closed = true
+	c.pending = nil
+	return c.err
+}
+
+// Added returns the number of tokens added to the cloud so far, tokens ignored by the cloud included
+func (c *TagWriter) Added() int {
+	return c.added
+}
+
+// scan adds tokens of pending bytes the way bufio.Scanner splits them
+func (c *TagWriter) scan(atEOF bool) {
+	start := 0
+	for !c.final {
+		advance, token, err := c.split(c.pending[start:], atEOF)
+		if err == bufio.ErrFinalToken {
+			c.final, err = true, nil
+		}
+		switch {
+		case err != nil:
+			c.err = err
+			return
+		case advance < 0:
+			c.err = bufio.ErrNegativeAdvance
+			return
+		case advance > len(c.pending)-start:
+			c.err = bufio.ErrAdvanceTooFar
+			return
+		}
+		start += advance
+		if token != nil {
+			c.cloud.AddTag(string(token))
+			c.added++
+		}
+		// no progress means more data is needed, at EOF it means the data is over
+		if advance == 0 {
+			break
+		}
+	}
+	c.pending = c.pending[:copy(c.pending, c.pending[start:])]
+}
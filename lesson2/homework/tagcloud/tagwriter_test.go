@@ -0,0 +1,99 @@
+package tagcloud_test
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"lecture02_homework/tagcloud"
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectorWriter(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/words/paragraph.txt")
+	require.NoError(t, err)
+	want := tagcloud.New(tagcloud.WithCaseInsensitive())
+	wantAdded, err := want.AddFromReader(strings.NewReader(string(fixture)))
+	require.NoError(t, err)
+
+	t.Run("ok, random chunks", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(1))
+		for i := 0; i < 20; i++ {
+			tc := tagcloud.New(tagcloud.WithCaseInsensitive())
+			collector := tagcloud.CollectorWriter(tc, nil)
+			chunk := make([]byte, 0, 16)
+			for data := fixture; len(data) > 0; {
+				size := min(rnd.Intn(16)+1, len(data))
+				chunk = append(chunk[:0], data[:size]...)
+				n, err := collector.Write(chunk)
+				require.NoError(t, err)
+				assert.Equal(t, size, n)
+				// the collector mustn't retain the written slice
+				for j := range chunk {
+					chunk[j] = '#'
+				}
+				data = data[size:]
+			}
+			require.NoError(t, collector.Close())
+
+			assert.True(t, want.Equal(tc), "%v", want.Diff(tc))
+			assert.Equal(t, wantAdded, collector.Added())
+		}
+	})
+
+	t.Run("ok, destination of io.Copy", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithCaseInsensitive())
+		collector := tagcloud.CollectorWriter(tc, nil)
+		copied := &strings.Builder{}
+
+		_, err := io.Copy(io.MultiWriter(copied, collector), iotest.OneByteReader(strings.NewReader(string(fixture))))
+		require.NoError(t, err)
+		require.NoError(t, collector.Close())
+
+		assert.Equal(t, string(fixture), copied.String())
+		assert.True(t, want.Equal(tc), "%v", want.Diff(tc))
+	})
+
+	t.Run("ok, custom tokenizer", func(t *testing.T) {
+		tc := tagcloud.New()
+		collector := tagcloud.CollectorWriter(tc, bufio.ScanLines)
+
+		_, _ = collector.Write([]byte("go lang\nru"))
+		_, _ = collector.Write([]byte("st\ngo lang"))
+		require.NoError(t, collector.Close())
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "go lang", OccurrenceCount: 2}, {Tag: "rust", OccurrenceCount: 1}}, tc.TopN(10))
+	})
+
+	t.Run("error, tokenizer failure", func(t *testing.T) {
+		tc := tagcloud.New()
+		failure := errors.New("bad token")
+		collector := tagcloud.CollectorWriter(tc, func(data []byte, atEOF bool) (int, []byte, error) {
+			if len(data) > 0 && data[0] == '!' {
+				return 0, nil, failure
+			}
+			return bufio.ScanWords(data, atEOF)
+		})
+
+		_, err := collector.Write([]byte("go rust !c"))
+		assert.ErrorIs(t, err, failure)
+		_, err = collector.Write([]byte("zig"))
+		assert.ErrorIs(t, err, failure)
+		assert.ErrorIs(t, collector.Close(), failure)
+		assert.Equal(t, 2, tc.Len())
+	})
+
+	t.Run("error, write after close", func(t *testing.T) {
+		collector := tagcloud.CollectorWriter(tagcloud.New(), nil)
+		require.NoError(t, collector.Close())
+
+		_, err := collector.Write([]byte("go"))
+		assert.EqualError(t, err, "tag writer is closed")
+	})
+}
@@ -0,0 +1,125 @@
+package tagcloud
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MarshalText encodes the cloud as "tag<TAB>count" lines sorted by tag, a tag with timestamps
+// of a cloud with WithTimestamps adds "<TAB>first<TAB>last" times in RFC 3339 format
+// and a cloud with WithHighWaterMarks ends every line with "<TAB>peak", retired peaks have zero count.
+// tags containing a tab or a line feed can't be encoded and are reported as an error
+func (cloud *TagCloud) MarshalText() ([]byte, error) {
+	cloud.rlock()
+	stats := make([]TagStatFull, 0, cloud.tags.Len())
+	for tag, count := range cloud.tags.All() {
+		times := cloud.seen[tag]
+		stats = append(stats, TagStatFull{TagStat: TagStat{Tag: tag, OccurrenceCount: count}, FirstSeen: times.first, LastSeen: times.last, Peak: cloud.peaks[tag]})
+	}
+	peaks := cloud.highWaterMarks
+	if peaks {
+		stats = append(stats, cloud.retiredStats()...)
+	}
+	cloud.runlock()
+	slices.SortFunc(stats, func(a, b TagStatFull) int {
+		return cmp.Compare(a.Tag, b.Tag)
+	})
+
+	buf := &bytes.Buffer{}
+	for _, stat := range stats {
+		if strings.ContainsAny(stat.Tag, "\t\n") {
+			return nil, fmt.Errorf("tag %q can't be encoded as text", stat.Tag)
+		}
+		fmt.Fprintf(buf, "%s\t%d", stat.Tag, stat.OccurrenceCount)
+		if !stat.FirstSeen.IsZero() {
+			fmt.Fprintf(buf, "\t%s\t%s", stat.FirstSeen.Format(time.RFC3339Nano), stat.LastSeen.Format(time.RFC3339Nano))
+		}
+		if peaks {
+			fmt.Fprintf(buf, "\t%d", stat.Peak)
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalText replaces content of the cloud with tags encoded by MarshalText.
+// non-positive counts other than ones of retired peaks and repeated tags are rejected,
+// a zero value TagCloud can be used as the target.
+// timestamps are kept only by a cloud with WithTimestamps and peaks only by a cloud with WithHighWaterMarks
+func (cloud *TagCloud) UnmarshalText(text []byte) error {
+	tags := map[string]int{}
+	seen := map[string]seenTimes{}
+	peaks := map[string]int{}
+	var lines []string
+	if trimmed := strings.TrimSuffix(string(text), "\n"); trimmed != "" {
+		lines = strings.Split(trimmed, "\n")
+	}
+	for i, line := range lines {
+		fields := strings.Split(line, "\t")
+		if len(fields) < 2 {
+			return fmt.Errorf("line %d: missing tab between tag and count", i+1)
+		}
+		if len(fields) > 5 {
+			return fmt.Errorf("line %d: too many fields", i+1)
+		}
+		tag, value := fields[0], fields[1]
+		// times come in pairs, so an odd field after the count is the peak
+		peak := 0
+		if len(fields)%2 == 1 {
+			var err error
+			peak, err = strconv.Atoi(fields[len(fields)-1])
+			switch {
+			case err != nil && len(fields) == 3:
+				return fmt.Errorf("line %d: missing last time in %q", i+1, fields[2])
+			case err != nil || peak < 0:
+				return fmt.Errorf("line %d: invalid peak %q of tag %q", i+1, fields[len(fields)-1], tag)
+			}
+			fields = fields[:len(fields)-1]
+		}
+		count, err := strconv.Atoi(value)
+		if err != nil || count < 0 || count == 0 && peak == 0 {
+			return fmt.Errorf("line %d: invalid count %q of tag %q", i+1, value, tag)
+		}
+		_, ok := tags[tag]
+		if _, retired := peaks[tag]; ok || retired {
+			return fmt.Errorf("line %d: duplicate tag %q", i+1, tag)
+		}
+		if peak > 0 {
+			peaks[tag] = peak
+		}
+		if count > 0 {
+			tags[tag] = count
+		}
+		if len(fields) == 4 {
+			times, err := parseTimes(fields[2], fields[3])
+			if err != nil {
+				return fmt.Errorf("line %d: %w", i+1, err)
+			}
+			seen[tag] = times
+		}
+	}
+	cloud.lock()
+	defer cloud.unlock()
+	cloud.replaceTags(tags)
+	cloud.restoreSeen(seen)
+	cloud.restorePeaks(peaks)
+	return nil
+}
+
+// parseTimes parses first and last times written by MarshalText
+func parseTimes(firstText, lastText string) (seenTimes, error) {
+	first, err := time.Parse(time.RFC3339Nano, firstText)
+	if err != nil {
+		return seenTimes{}, fmt.Errorf("invalid first time: %w", err)
+	}
+	last, err := time.Parse(time.RFC3339Nano, lastText)
+	if err != nil {
+		return seenTimes{}, fmt.Errorf("invalid last time: %w", err)
+	}
+	return seenTimes{first: first, last: last}, nil
+}
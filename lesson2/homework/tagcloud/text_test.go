@@ -0,0 +1,123 @@
+package tagcloud_test
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"lecture02_homework/tagcloud"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ encoding.TextMarshaler     = (*tagcloud.TagCloud)(nil)
+	_ encoding.TextUnmarshaler   = (*tagcloud.TagCloud)(nil)
+	_ encoding.BinaryMarshaler   = (*tagcloud.TagCloud)(nil)
+	_ encoding.BinaryUnmarshaler = (*tagcloud.TagCloud)(nil)
+)
+
+func TestMarshalText(t *testing.T) {
+	t.Run("ok, sorted lines", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("rust", 2)
+		tc.AddTagN("go", 3)
+		tc.AddTag("машинное обучение")
+
+		text, err := tc.MarshalText()
+
+		require.NoError(t, err)
+		assert.Equal(t, "go\t3\nrust\t2\nмашинное обучение\t1\n", string(text))
+	})
+
+	t.Run("ok, round trip", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("go", 3)
+		tc.AddTag("a b")
+		text, err := tc.MarshalText()
+		require.NoError(t, err)
+
+		var decoded tagcloud.TagCloud
+		err = decoded.UnmarshalText(text)
+
+		require.NoError(t, err)
+		assert.Equal(t, tc.TopN(10), decoded.TopN(10))
+		assert.Equal(t, 4, decoded.Total())
+	})
+
+	t.Run("ok, empty cloud", func(t *testing.T) {
+		text, err := tagcloud.New().MarshalText()
+		require.NoError(t, err)
+		assert.Empty(t, text)
+
+		tc := tagcloud.New()
+		tc.AddTag("go")
+		assert.NoError(t, tc.UnmarshalText(text))
+		assert.Equal(t, 0, tc.Len())
+	})
+
+	t.Run("error, tag with a tab", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag("a\tb")
+
+		_, err := tc.MarshalText()
+
+		assert.Error(t, err)
+	})
+
+	t.Run("error, invalid text", func(t *testing.T) {
+		for _, text := range []string{"go", "go\tmany\n", "go\t0\n", "go\t1\ngo\t2\n", "go\t1\n\n"} {
+			var tc tagcloud.TagCloud
+
+			assert.Error(t, tc.UnmarshalText([]byte(text)), text)
+		}
+	})
+}
+
+func TestMarshalBinary(t *testing.T) {
+	t.Run("ok, round trip", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("go", 3)
+		tc.AddTag("rust")
+		data, err := tc.MarshalBinary()
+		require.NoError(t, err)
+
+		var decoded tagcloud.TagCloud
+		err = decoded.UnmarshalBinary(data)
+
+		require.NoError(t, err)
+		assert.Equal(t, tc.TopN(10), decoded.TopN(10))
+		saved := &bytes.Buffer{}
+		require.NoError(t, tc.Save(saved))
+		assert.Equal(t, saved.Bytes(), data)
+	})
+
+	t.Run("error, truncated data", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag("go")
+		data, err := tc.MarshalBinary()
+		require.NoError(t, err)
+
+		var decoded tagcloud.TagCloud
+		assert.Error(t, decoded.UnmarshalBinary(data[:len(data)-1]))
+	})
+
+	t.Run("ok, gob encoding of a struct with a cloud", func(t *testing.T) {
+		type session struct {
+			User  string
+			Cloud *tagcloud.TagCloud
+		}
+		tc := tagcloud.New()
+		tc.AddTagN("go", 2)
+		tc.AddTag("rust")
+		buf := &bytes.Buffer{}
+
+		require.NoError(t, gob.NewEncoder(buf).Encode(session{User: "gopher", Cloud: tc}))
+		var decoded session
+		require.NoError(t, gob.NewDecoder(buf).Decode(&decoded))
+
+		assert.Equal(t, "gopher", decoded.User)
+		assert.Equal(t, tc.TopN(10), decoded.Cloud.TopN(10))
+	})
+}
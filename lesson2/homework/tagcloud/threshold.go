@@ -0,0 +1,54 @@
+package tagcloud
+
+// threshold calls fn the first time a tag's count reaches n
+type threshold struct {
+	n  int
+	fn func(tag string, count int)
+	// fired holds tags the callback was called for, they are never forgotten
+	fired map[string]struct{}
+}
+
+// thresholdEvent is a callback call postponed until the cloud is unlocked
+type thresholdEvent struct {
+	fn    func(tag string, count int)
+	tag   string
+	count int
+}
+
+// OnThreshold registers fn to be called the first time a tag's count reaches or passes n.
+// fn is called at most once per tag: when the count drops below n and then reaches it again, fn isn't called.
+// tags already having n occurrences at registration are treated as reported.
+// fn is called synchronously by the method which changed the count, after the cloud is unlocked,
+// so it may use the cloud. counts replaced at once by Reset or decoding don't call fn
+func (cloud *TagCloud) OnThreshold(n int, fn func(tag string, count int)) {
+	cloud.lock()
+	defer cloud.unlock()
+	t := &threshold{n: n, fn: fn, fired: map[string]struct{}{}}
+	for tag, count := range cloud.tags.All() {
+		if count >= n {
+			t.fired[tag] = struct{}{}
+		}
+	}
+	cloud.thresholds = append(cloud.thresholds, t)
+}
+
+// checkThresholds postpones callbacks of thresholds crossed by the count change
+func (cloud *TagCloud) checkThresholds(tag string, old, count int) {
+	for _, t := range cloud.thresholds {
+		if count < t.n || old >= t.n {
+			continue
+		}
+		if _, ok := t.fired[tag]; ok {
+			continue
+		}
+		t.fired[tag] = struct{}{}
+		cloud.events = append(cloud.events, thresholdEvent{fn: t.fn, tag: tag, count: count})
+	}
+}
+
+// fireEvents calls postponed callbacks, the cloud must be unlocked
+func fireEvents(events []thresholdEvent) {
+	for _, event := range events {
+		event.fn(event.tag, event.count)
+	}
+}
@@ -0,0 +1,98 @@
+package tagcloud_test
+
+import (
+	"fmt"
+	"lecture02_homework/tagcloud"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// thresholdRecorder records callback calls
+type thresholdRecorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *thresholdRecorder) record(tag string, count int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, fmt.Sprintf("%s=%d", tag, count))
+}
+
+func TestOnThreshold(t *testing.T) {
+	t.Run("ok, fires exactly once per tag", func(t *testing.T) {
+		tc := tagcloud.New()
+		recorder := &thresholdRecorder{}
+		tc.OnThreshold(3, recorder.record)
+
+		tc.AddTags("go", "go", "rust")
+		assert.Empty(t, recorder.calls)
+
+		tc.AddTag("go")
+		tc.AddTag("go")
+		tc.RemoveTag("go")
+		tc.RemoveTag("go")
+		tc.AddTagN("go", 5)
+
+		assert.Equal(t, []string{"go=3"}, recorder.calls)
+	})
+
+	t.Run("ok, count passing the threshold", func(t *testing.T) {
+		tc := tagcloud.New()
+		recorder := &thresholdRecorder{}
+		tc.OnThreshold(3, recorder.record)
+
+		tc.AddTagN("go", 10)
+
+		assert.Equal(t, []string{"go=10"}, recorder.calls)
+	})
+
+	t.Run("ok, multiple thresholds", func(t *testing.T) {
+		tc := tagcloud.New()
+		recorder := &thresholdRecorder{}
+		tc.OnThreshold(2, recorder.record)
+		tc.OnThreshold(4, recorder.record)
+
+		tc.AddTags("go", "go", "rust", "go", "go", "rust")
+
+		assert.Equal(t, []string{"go=2", "go=4", "rust=2"}, recorder.calls)
+	})
+
+	t.Run("ok, tags above threshold at registration are not reported", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("go", 5)
+		recorder := &thresholdRecorder{}
+		tc.OnThreshold(3, recorder.record)
+
+		tc.AddTag("go")
+		tc.AddTagN("rust", 3)
+
+		assert.Equal(t, []string{"rust=3"}, recorder.calls)
+	})
+
+	t.Run("ok, callback may use concurrent cloud", func(t *testing.T) {
+		tc := tagcloud.NewConcurrent()
+		recorder := &thresholdRecorder{}
+		tc.OnThreshold(100, func(tag string, count int) {
+			recorder.record(tag, tc.Count(tag))
+			tc.AddTag("alert")
+		})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 50; j++ {
+					tc.AddTag("go")
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Len(t, recorder.calls, 1)
+		assert.Equal(t, 1, tc.Count("alert"))
+	})
+}
@@ -0,0 +1,99 @@
+package tagcloud
+
+import "time"
+
+// WithTimestamps makes the cloud remember when every tag was added for the first and the last time, see TopNFull.
+// clock gives the time of an addition, nil means the clock set by WithClock or time.Now
+func WithTimestamps(clock func() time.Time) Option {
+	return func(cloud *TagCloud) {
+		cloud.timestamps = true
+		if clock != nil {
+			cloud.clock = clock
+		}
+	}
+}
+
+// TagStatFull is TagStat with the times of the first and the last addition of the tag,
+// they are zero for a cloud without WithTimestamps
+type TagStatFull struct {
+	TagStat
+	FirstSeen time.Time
+	LastSeen  time.Time
+	// Peak is the largest count of the tag, it's zero for a cloud without WithHighWaterMarks
+	Peak int
+}
+
+// seenTimes holds the times of the first and the last addition of a tag
+type seenTimes struct {
+	first, last time.Time
+}
+
+// join returns times covering both times
+func (times seenTimes) join(other seenTimes) seenTimes {
+	if other.first.Before(times.first) {
+		times.first = other.first
+	}
+	if other.last.After(times.last) {
+		times.last = other.last
+	}
+	return times
+}
+
+// TopNFull works as TopN and adds the times of the first and the last addition and the peak of every tag
+func (cloud *TagCloud) TopNFull(n int) []TagStatFull {
+	cloud.rlock()
+	defer cloud.runlock()
+	stats := cloud.topStats(n, 0)
+	result := make([]TagStatFull, len(stats))
+	for i, stat := range stats {
+		times := cloud.seen[stat.Tag]
+		result[i] = TagStatFull{TagStat: stat, FirstSeen: times.first, LastSeen: times.last, Peak: cloud.peaks[stat.Tag]}
+	}
+	return result
+}
+
+// markSeen remembers the current time as the last addition of the tag
+func (cloud *TagCloud) markSeen(tag string) {
+	if !cloud.timestamps {
+		return
+	}
+	now := cloud.now()
+	if cloud.seen == nil {
+		cloud.seen = map[string]seenTimes{}
+	}
+	times, ok := cloud.seen[tag]
+	if !ok {
+		times.first = now
+	}
+	times.last = now
+	cloud.seen[tag] = times
+}
+
+// mergeSeen joins times of the tag merged from another cloud with the times the tag had before the merge
+func (cloud *TagCloud) mergeSeen(tag string, before seenTimes, existed bool, times seenTimes) {
+	if !cloud.timestamps {
+		return
+	}
+	if existed {
+		times = times.join(before)
+	}
+	cloud.seen[tag] = times
+}
+
+// restoreSeen sets decoded times of a cloud with WithTimestamps after its tags are replaced
+func (cloud *TagCloud) restoreSeen(seen map[string]seenTimes) {
+	if cloud.timestamps {
+		cloud.seen = seen
+	}
+}
+
+func (cloud *TagCloud) copySeen() map[string]seenTimes {
+	if cloud.seen == nil {
+		return nil
+	}
+	seen := make(map[string]seenTimes, len(cloud.seen))
+	for tag, times := range cloud.seen {
+		seen[tag] = times
+	}
+	return seen
+}
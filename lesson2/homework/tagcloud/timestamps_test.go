@@ -0,0 +1,195 @@
+package tagcloud_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"lecture02_homework/tagcloud"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// inUTC converts times of the stats to UTC, so decoded times can be compared with assert.Equal
+func inUTC(stats []tagcloud.TagStatFull) []tagcloud.TagStatFull {
+	for i := range stats {
+		stats[i].FirstSeen = stats[i].FirstSeen.UTC()
+		stats[i].LastSeen = stats[i].LastSeen.UTC()
+	}
+	return stats
+}
+
+func full(tag string, count int, first, last time.Time) tagcloud.TagStatFull {
+	return tagcloud.TagStatFull{TagStat: tagcloud.TagStat{Tag: tag, OccurrenceCount: count}, FirstSeen: first, LastSeen: last}
+}
+
+func TestWithTimestamps(t *testing.T) {
+	t.Run("ok, first and last addition", func(t *testing.T) {
+		clock := newFakeClock()
+		start := clock.Now()
+		tc := tagcloud.New(tagcloud.WithTimestamps(clock.Now))
+
+		tc.AddTag("go")
+		clock.Advance(time.Minute)
+		tc.AddTagN("rust", 2)
+		clock.Advance(time.Minute)
+		tc.AddTag("go")
+		tc.RemoveTag("rust")
+		clock.Advance(time.Minute)
+		tc.AddTag("zig")
+		tc.DeleteTag("zig")
+
+		assert.Equal(t, []tagcloud.TagStatFull{
+			full("go", 2, start, start.Add(2*time.Minute)),
+			full("rust", 1, start.Add(time.Minute), start.Add(time.Minute)),
+		}, tc.TopNFull(10))
+	})
+
+	t.Run("ok, removed tag starts again", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := tagcloud.New(tagcloud.WithTimestamps(clock.Now))
+		tc.AddTag("go")
+		tc.RemoveTag("go")
+		clock.Advance(time.Hour)
+
+		tc.AddTag("go")
+
+		assert.Equal(t, []tagcloud.TagStatFull{full("go", 1, clock.Now(), clock.Now())}, tc.TopNFull(1))
+	})
+
+	t.Run("ok, clock of WithClock", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := tagcloud.New(tagcloud.WithClock(clock.Now), tagcloud.WithTimestamps(nil))
+
+		tc.AddTag("go")
+
+		assert.Equal(t, clock.Now(), tc.TopNFull(1)[0].FirstSeen)
+	})
+
+	t.Run("ok, without option", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag("go")
+
+		assert.Equal(t, []tagcloud.TagStatFull{{TagStat: tagcloud.TagStat{Tag: "go", OccurrenceCount: 1}}}, tc.TopNFull(10))
+		assert.Equal(t, []tagcloud.TagStatFull{}, tc.TopNFull(0))
+	})
+}
+
+func TestTimestampsMerge(t *testing.T) {
+	clock := newFakeClock()
+	start := clock.Now()
+	first := tagcloud.New(tagcloud.WithTimestamps(clock.Now))
+	second := tagcloud.New(tagcloud.WithTimestamps(clock.Now))
+	first.AddTag("go")
+	clock.Advance(time.Minute)
+	second.AddTag("go")
+	second.AddTag("rust")
+	clock.Advance(time.Minute)
+	first.AddTag("go")
+	clock.Advance(time.Hour)
+
+	t.Run("ok, merge takes earliest first and latest last", func(t *testing.T) {
+		merged := second.Merge(first)
+
+		assert.Equal(t, []tagcloud.TagStatFull{
+			full("go", 3, start, start.Add(2*time.Minute)),
+			full("rust", 1, start.Add(time.Minute), start.Add(time.Minute)),
+		}, merged.TopNFull(10))
+	})
+
+	t.Run("ok, merge into cloud without timestamps", func(t *testing.T) {
+		plain := tagcloud.New()
+		plain.MergeInto(first)
+
+		assert.True(t, plain.TopNFull(1)[0].FirstSeen.IsZero())
+	})
+
+	t.Run("ok, merging cloud without timestamps marks the merge time", func(t *testing.T) {
+		plain := tagcloud.New()
+		plain.AddTag("go")
+		merged := first.Clone()
+
+		merged.MergeInto(plain)
+
+		assert.Equal(t, []tagcloud.TagStatFull{full("go", 3, start, clock.Now())}, merged.TopNFull(1))
+	})
+
+	t.Run("ok, mapped tags join times", func(t *testing.T) {
+		mapped := first.Merge(second).MapTags(func(string) string { return "lang" })
+
+		assert.Equal(t, []tagcloud.TagStatFull{full("lang", 4, start, start.Add(2*time.Minute))}, mapped.TopNFull(1))
+	})
+}
+
+func TestTimestampsEncoding(t *testing.T) {
+	clock := newFakeClock()
+	start := clock.Now()
+	tc := tagcloud.New(tagcloud.WithTimestamps(clock.Now))
+	tc.AddTag("go")
+	clock.Advance(90 * time.Second)
+	tc.AddTag("go")
+	tc.AddTag("rust")
+	expected := []tagcloud.TagStatFull{
+		full("go", 2, start, start.Add(90*time.Second)),
+		full("rust", 1, start.Add(90*time.Second), start.Add(90*time.Second)),
+	}
+
+	t.Run("ok, binary", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, tc.Save(buf))
+
+		loaded, err := tagcloud.Load(buf)
+		require.NoError(t, err)
+
+		assert.Equal(t, expected, inUTC(loaded.TopNFull(10)))
+		loaded.AddTag("zig")
+		assert.False(t, loaded.TopNFull(3)[2].FirstSeen.IsZero())
+	})
+
+	t.Run("ok, json", func(t *testing.T) {
+		data, err := json.Marshal(tc)
+		require.NoError(t, err)
+		assert.JSONEq(t, `[
+			{"tag": "go", "count": 2, "first_seen": "2024-01-01T12:00:00Z", "last_seen": "2024-01-01T12:01:30Z"},
+			{"tag": "rust", "count": 1, "first_seen": "2024-01-01T12:01:30Z", "last_seen": "2024-01-01T12:01:30Z"}
+		]`, string(data))
+
+		decoded := tagcloud.New(tagcloud.WithTimestamps(nil))
+		require.NoError(t, json.Unmarshal(data, decoded))
+
+		assert.Equal(t, expected, inUTC(decoded.TopNFull(10)))
+	})
+
+	t.Run("ok, text", func(t *testing.T) {
+		text, err := tc.MarshalText()
+		require.NoError(t, err)
+		assert.Equal(t, "go\t2\t2024-01-01T12:00:00Z\t2024-01-01T12:01:30Z\nrust\t1\t2024-01-01T12:01:30Z\t2024-01-01T12:01:30Z\n", string(text))
+
+		decoded := tagcloud.New(tagcloud.WithTimestamps(nil))
+		require.NoError(t, decoded.UnmarshalText(text))
+
+		assert.Equal(t, expected, inUTC(decoded.TopNFull(10)))
+	})
+
+	t.Run("ok, cloud without timestamps drops them", func(t *testing.T) {
+		data, err := tc.MarshalBinary()
+		require.NoError(t, err)
+		decoded := tagcloud.New()
+
+		require.NoError(t, decoded.UnmarshalBinary(data))
+
+		assert.True(t, decoded.TopNFull(1)[0].LastSeen.IsZero())
+		assert.Equal(t, 3, decoded.Total())
+	})
+
+	t.Run("error, invalid time", func(t *testing.T) {
+		decoded := tagcloud.New(tagcloud.WithTimestamps(nil))
+
+		assert.ErrorContains(t, decoded.UnmarshalText([]byte("go\t2\tyesterday\ttoday\n")), "line 1: invalid first time")
+		assert.EqualError(t, decoded.UnmarshalText([]byte("go\t2\t2024-01-01T12:00:00Z\n")), `line 1: missing last time in "2024-01-01T12:00:00Z"`)
+		_, err := tagcloud.Load(bytes.NewReader([]byte("TGCL\x02\x01\x02go\x02\x01\x02")))
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	})
+}
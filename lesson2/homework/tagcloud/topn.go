@@ -0,0 +1,147 @@
+package tagcloud
+
+import (
+	"slices"
+)
+
+// heapSelectRatio is the smallest cloud size to n ratio which is worth a heap,
+// for larger n sorting all tags is cheaper
+const heapSelectRatio = 4
+
+// TopNFunc returns the first n tags ordered by compare, which returns a negative number when a goes before b
+// as in slices.SortFunc. nil compare means the TopN order. compare sees stats without ErrorBound and DisplayForm,
+// they are filled in the result. n <= 0 gives an empty slice
+func (cloud *TagCloud) TopNFunc(n int, compare func(a, b TagStat) int) []TagStat {
+	cloud.rlock()
+	defer cloud.runlock()
+	if compare == nil {
+		return cloud.topStats(n, 0)
+	}
+	stats := cloud.selectStats(n, 0, compare)
+	cloud.fillErrorBounds(stats)
+	cloud.fillDisplayForms(stats)
+	return stats
+}
+
+// topStats returns stats in TopN order, the result is never nil
+func (cloud *TagCloud) topStats(n int, minCount int) []TagStat {
+	return cloud.appendTopStats([]TagStat{}, n, minCount)
+}
+
+// appendTopStats appends stats in TopN order to dst, an ordered cloud just walks its ordering
+// and stats sorted by TopNFrom or LoadSorted are reused until the next change
+func (cloud *TagCloud) appendTopStats(dst []TagStat, n int, minCount int) []TagStat {
+	start := len(dst)
+	if cloud.walksOrdering() {
+		dst = cloud.ordered.AppendTop(dst, 0, n, minCount)
+	} else if sorted := cloud.cachedStats(); sorted != nil {
+		for _, stat := range sorted[:max(0, min(n, len(sorted)))] {
+			if stat.OccurrenceCount < minCount {
+				break
+			}
+			dst = append(dst, stat)
+		}
+	} else {
+		dst = cloud.appendSelectStats(dst, n, minCount, cloud.compare())
+	}
+	cloud.fillErrorBounds(dst[start:])
+	cloud.fillDisplayForms(dst[start:])
+	return dst
+}
+
+// selectStats returns first n stats of tags with count at least minCount in the given order.
+// the result is never nil, n <= 0 gives an empty slice
+func (cloud *TagCloud) selectStats(n int, minCount int, compare func(a, b TagStat) int) []TagStat {
+	return cloud.appendSelectStats([]TagStat{}, n, minCount, compare)
+}
+
+// appendSelectStats appends selected stats to dst, the heap of selected stats lives in the spare capacity of dst
+func (cloud *TagCloud) appendSelectStats(dst []TagStat, n int, minCount int, compare func(a, b TagStat) int) []TagStat {
+	if n <= 0 {
+		return dst
+	}
+	if n > cloud.tags.Len()/heapSelectRatio {
+		return cloud.appendSortStats(dst, n, minCount, compare)
+	}
+	start := len(dst)
+	dst = slices.Grow(dst, n)
+	selected := dst[start:start]
+	for tag, count := range cloud.tags.All() {
+		if count < minCount {
+			continue
+		}
+		stat := TagStat{Tag: tag, OccurrenceCount: count}
+		switch {
+		case len(selected) < n:
+			selected = append(selected, stat)
+			if len(selected) == n {
+				for i := n/2 - 1; i >= 0; i-- {
+					siftWorst(selected, i, compare)
+				}
+			}
+		case compare(stat, selected[0]) < 0:
+			selected[0] = stat
+			siftWorst(selected, 0, compare)
+		}
+	}
+	slices.SortFunc(selected, compare)
+	return dst[:start+len(selected)]
+}
+
+// siftWorst restores the heap of stats with the worst stat on top below i, unlike statHeap
+// it works on a plain slice, so selecting into dst doesn't allocate
+func siftWorst(stats []TagStat, i int, compare func(a, b TagStat) int) {
+	for {
+		worst := i
+		for _, child := range []int{2*i + 1, 2*i + 2} {
+			if child < len(stats) && compare(stats[child], stats[worst]) > 0 {
+				worst = child
+			}
+		}
+		if worst == i {
+			return
+		}
+		stats[i], stats[worst] = stats[worst], stats[i]
+		i = worst
+	}
+}
+
+// appendSortStats selects stats by sorting all of them after the existing stats of dst
+func (cloud *TagCloud) appendSortStats(dst []TagStat, n int, minCount int, compare func(a, b TagStat) int) []TagStat {
+	start := len(dst)
+	for tag, count := range cloud.tags.All() {
+		if count >= minCount {
+			dst = append(dst, TagStat{Tag: tag, OccurrenceCount: count})
+		}
+	}
+	slices.SortStableFunc(dst[start:], compare)
+	return dst[:start+min(n, len(dst)-start)]
+}
+
+// statHeap keeps the worst of selected stats on top, so it's the one replaced by a better stat
+type statHeap struct {
+	stats   []TagStat
+	compare func(a, b TagStat) int
+}
+
+func (h *statHeap) Len() int {
+	return len(h.stats)
+}
+
+func (h *statHeap) Less(i, j int) bool {
+	return h.compare(h.stats[i], h.stats[j]) > 0
+}
+
+func (h *statHeap) Swap(i, j int) {
+	h.stats[i], h.stats[j] = h.stats[j], h.stats[i]
+}
+
+func (h *statHeap) Push(x any) {
+	h.stats = append(h.stats, x.(TagStat))
+}
+
+func (h *statHeap) Pop() any {
+	last := h.stats[len(h.stats)-1]
+	h.stats = h.stats[:len(h.stats)-1]
+	return last
+}
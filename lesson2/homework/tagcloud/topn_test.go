@@ -0,0 +1,223 @@
+package tagcloud_test
+
+import (
+	"fmt"
+	"lecture02_homework/tagcloud"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeapTopN(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	tc := tagcloud.New()
+	for i := 0; i < 2000; i++ {
+		// few distinct counts, so most tags are ordered by the tie-break
+		tc.AddTagN(fmt.Sprintf("t%d", rnd.Intn(1500)), rnd.Intn(4)+1)
+	}
+	// TopN of the whole cloud sorts all tags, smaller n goes through the heap
+	all := tc.TopN(tc.Len())
+	bottom := tc.BottomN(tc.Len())
+
+	for _, n := range []int{1, 2, 3, 10, 50, 100, 250, 300, 1000} {
+		assert.Equal(t, all[:n], tc.TopN(n), "TopN(%d)", n)
+		assert.Equal(t, bottom[:n], tc.BottomN(n), "BottomN(%d)", n)
+	}
+
+	var withMin []tagcloud.TagStat
+	for _, stat := range all {
+		if stat.OccurrenceCount >= 5 {
+			withMin = append(withMin, stat)
+		}
+	}
+	for _, n := range []int{1, 10, 100} {
+		assert.Equal(t, withMin[:n], tc.TopNWithMin(n, 5), "TopNWithMin(%d, 5)", n)
+	}
+	assert.Empty(t, tc.TopNWithMin(10, 1000))
+}
+
+func TestTopNBounds(t *testing.T) {
+	approximate, err := tagcloud.NewApproximate(0.01, 0.01)
+	assert.NoError(t, err)
+	counters := map[string]tagcloud.Counter{
+		"new":         tagcloud.New(),
+		"concurrent":  tagcloud.NewConcurrent(),
+		"ordered":     tagcloud.NewOrdered(),
+		"sharded":     tagcloud.NewSharded(4),
+		"approximate": approximate,
+	}
+	for name, counter := range counters {
+		t.Run("ok, empty cloud, "+name, func(t *testing.T) {
+			for _, n := range []int{-1, 0, 3} {
+				stats := counter.TopN(n)
+
+				assert.NotNil(t, stats, "TopN(%d)", n)
+				assert.Empty(t, stats, "TopN(%d)", n)
+			}
+		})
+	}
+	for name, counter := range counters {
+		t.Run("ok, "+name, func(t *testing.T) {
+			counter.AddTagN("a", 2)
+			counter.AddTag("b")
+			counter.AddTag("c")
+
+			for _, n := range []int{-5, -1, 0} {
+				stats := counter.TopN(n)
+
+				assert.NotNil(t, stats, "TopN(%d)", n)
+				assert.Empty(t, stats, "TopN(%d)", n)
+			}
+			assert.Equal(t, []tagcloud.TagStat{
+				{Tag: "a", OccurrenceCount: 2},
+				{Tag: "b", OccurrenceCount: 1},
+				{Tag: "c", OccurrenceCount: 1},
+			}, counter.TopN(counter.Len()))
+			assert.Equal(t, counter.TopN(counter.Len()), counter.TopN(counter.Len()+1))
+		})
+	}
+}
+
+func TestAppendTopN(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	clouds := map[string]*tagcloud.TagCloud{"new": tagcloud.New(), "ordered": tagcloud.NewOrdered()}
+	for _, tc := range clouds {
+		for i := 0; i < 1000; i++ {
+			tc.AddTagN(fmt.Sprintf("t%d", i), rnd.Intn(20)+1)
+		}
+	}
+	for name, tc := range clouds {
+		t.Run("ok, same as TopN, "+name, func(t *testing.T) {
+			prefix := []tagcloud.TagStat{{Tag: "kept", OccurrenceCount: 1}}
+			for _, n := range []int{-1, 0, 1, 50, 300, 1000, 1001} {
+				stats := tc.AppendTopN(slices.Clone(prefix), n)
+
+				assert.Equal(t, prefix, stats[:1], "AppendTopN(%d)", n)
+				assert.Equal(t, tc.TopN(n), stats[1:], "AppendTopN(%d)", n)
+			}
+		})
+
+		t.Run("ok, no allocations with enough capacity, "+name, func(t *testing.T) {
+			dst := make([]tagcloud.TagStat, 0, 50)
+
+			allocs := testing.AllocsPerRun(100, func() {
+				dst = tc.AppendTopN(dst[:0], 50)
+			})
+
+			assert.Zero(t, allocs)
+			assert.Equal(t, tc.TopN(50), dst)
+		})
+	}
+}
+
+func BenchmarkTopN(b *testing.B) {
+	for _, size := range []int{10000, 1000000} {
+		tc := tagcloud.New()
+		rnd := rand.New(rand.NewSource(1))
+		for i := 0; i < size; i++ {
+			tc.AddTagN(fmt.Sprintf("tag-%d", i), rnd.Intn(1000)+1)
+		}
+		for _, n := range []int{10, 100, 1000} {
+			b.Run(fmt.Sprintf("size=%d n=%d", size, n), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					tc.TopN(n)
+				}
+			})
+		}
+		b.Run(fmt.Sprintf("size=%d full sort", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				tc.TopN(size)
+			}
+		})
+	}
+}
+
+func BenchmarkTopN10of1M(b *testing.B) {
+	tc := tagcloud.New()
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000000; i++ {
+		tc.AddTagN(fmt.Sprintf("tag-%d", i), rnd.Intn(1000)+1)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tc.TopN(10)
+	}
+}
+
+func TestTopNFunc(t *testing.T) {
+	alphabetical := func(a, b tagcloud.TagStat) int {
+		return strings.Compare(a.Tag, b.Tag)
+	}
+	shortestFirst := func(a, b tagcloud.TagStat) int {
+		if c := len(a.Tag) - len(b.Tag); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Tag, b.Tag)
+	}
+
+	t.Run("ok, alphabetical", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("rust", 5)
+		tc.AddTagN("go", 3)
+		tc.AddTag("c")
+		tc.AddTag("zig")
+
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "c", OccurrenceCount: 1},
+			{Tag: "go", OccurrenceCount: 3},
+			{Tag: "rust", OccurrenceCount: 5},
+		}, tc.TopNFunc(3, alphabetical))
+	})
+
+	t.Run("ok, shortest tags first", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("kubernetes", 10)
+		tc.AddTag("go")
+		tc.AddTagN("rust", 2)
+		tc.AddTag("c")
+
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "c", OccurrenceCount: 1},
+			{Tag: "go", OccurrenceCount: 1},
+		}, tc.TopNFunc(2, shortestFirst))
+	})
+
+	t.Run("ok, heap selection agrees with sorting", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(1))
+		tc := tagcloud.NewOrdered()
+		for i := 0; i < 2000; i++ {
+			tc.AddTagN(fmt.Sprintf("t%d", rnd.Intn(1500)), rnd.Intn(4)+1)
+		}
+		all := tc.TopN(tc.Len())
+		slices.SortFunc(all, shortestFirst)
+
+		for _, n := range []int{1, 10, 100, 1000, tc.Len()} {
+			assert.Equal(t, all[:n], tc.TopNFunc(n, shortestFirst), "TopNFunc(%d)", n)
+		}
+	})
+
+	t.Run("ok, nil compare is TopN order", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithMaxTags(2))
+		tc.AddTagN("go", 3)
+		tc.AddTag("rust")
+		tc.AddTag("zig")
+
+		assert.Equal(t, tc.TopN(2), tc.TopNFunc(2, nil))
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: "go", OccurrenceCount: 3},
+			{Tag: "zig", OccurrenceCount: 2, ErrorBound: 1},
+		}, tc.TopNFunc(2, alphabetical))
+	})
+
+	t.Run("ok, non-positive n", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag("go")
+
+		assert.Equal(t, []tagcloud.TagStat{}, tc.TopNFunc(0, alphabetical))
+		assert.Equal(t, []tagcloud.TagStat{}, tc.TopNFunc(-1, nil))
+	})
+}
@@ -0,0 +1,93 @@
+package tagcloud
+
+// Filter returns a new cloud with tags for which pred returns true, the receiver isn't changed.
+// the result is configured like the receiver
+func (cloud *TagCloud) Filter(pred func(TagStat) bool) *TagCloud {
+	result := cloud.Clone()
+	result.lock()
+	defer result.unlock()
+	for tag, count := range result.tags.All() {
+		if !pred(TagStat{Tag: tag, OccurrenceCount: count, ErrorBound: result.errorBounds[tag]}) {
+			result.setCount(tag, 0)
+		}
+	}
+	return result
+}
+
+// MapTags returns a new cloud with every tag renamed by f, counts of tags mapped to the same tag are summed.
+// mapped tags are normalized by the result, tags mapped to an ignored tag are dropped
+func (cloud *TagCloud) MapTags(f func(string) string) *TagCloud {
+	data := cloud.snapshot()
+	mapped := tagData{tags: make(map[string]int, len(data.tags)), pairs: make(map[tagPair]int, len(data.pairs))}
+	for tag, count := range data.tags {
+		mapped.tags[f(tag)] += count
+		if weight := data.extraWeights[tag]; weight != 0 {
+			if mapped.extraWeights == nil {
+				mapped.extraWeights = map[string]float64{}
+			}
+			mapped.extraWeights[f(tag)] += weight
+		}
+		if times, ok := data.seen[tag]; ok {
+			if mapped.seen == nil {
+				mapped.seen = map[string]seenTimes{}
+			}
+			if other, ok := mapped.seen[f(tag)]; ok {
+				times = times.join(other)
+			}
+			mapped.seen[f(tag)] = times
+		}
+		for surface, n := range data.surfaces[tag] {
+			if mapped.surfaces == nil {
+				mapped.surfaces = map[string]map[string]int{}
+			}
+			if mapped.surfaces[f(tag)] == nil {
+				mapped.surfaces[f(tag)] = map[string]int{}
+			}
+			mapped.surfaces[f(tag)][surface] += n
+		}
+	}
+	for pair, count := range data.pairs {
+		mapped.pairs[tagPair{first: f(pair.first), second: f(pair.second)}] += count
+	}
+	result := cloud.blank()
+	result.lock()
+	defer result.unlock()
+	result.mergeData(mapped)
+	return result
+}
+
+// Without returns a new cloud with counts of other subtracted, tags without remaining occurrences are dropped.
+// the receiver isn't changed, nil other is treated as an empty cloud
+func (cloud *TagCloud) Without(other *TagCloud) *TagCloud {
+	result := cloud.Clone()
+	if other == nil {
+		return result
+	}
+	data := other.snapshot()
+	result.lock()
+	defer result.unlock()
+	for tag, count := range data.tags {
+		key, ok := result.copiedKey(tag, data.stemmed)
+		if !ok {
+			continue
+		}
+		if current, ok := result.tags.get(key); ok {
+			result.setCount(key, current-count)
+		}
+	}
+	return result
+}
+
+// blank returns an empty cloud configured like the receiver
+func (cloud *TagCloud) blank() *TagCloud {
+	result := &TagCloud{
+		aliases:     cloud.copyAliases(),
+		concurrent:  cloud.concurrent,
+		cloudConfig: cloud.cloudConfig,
+	}
+	if cloud.ordered != nil {
+		result.ordered = newSkipList()
+	}
+	result.replaceTags(map[string]int{})
+	return result
+}
@@ -0,0 +1,116 @@
+package tagcloud_test
+
+import (
+	"lecture02_homework/tagcloud"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter(t *testing.T) {
+	t.Run("ok, keeps matching tags", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("go", 3)
+		tc.AddTagN("the", 5)
+		tc.AddTag("a")
+
+		filtered := tc.Filter(func(stat tagcloud.TagStat) bool {
+			return stat.OccurrenceCount > 1 && stat.Tag != "the"
+		})
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "go", OccurrenceCount: 3}}, filtered.TopN(10))
+		assert.Equal(t, 3, filtered.Total())
+		assert.Equal(t, 3, tc.Len())
+		assert.Equal(t, 9, tc.Total())
+	})
+
+	t.Run("ok, empty cloud", func(t *testing.T) {
+		filtered := tagcloud.New().Filter(func(tagcloud.TagStat) bool { return true })
+
+		assert.Equal(t, 0, filtered.Len())
+	})
+}
+
+func TestMapTags(t *testing.T) {
+	t.Run("ok, collapsing map sums counts", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("cat", 2)
+		tc.AddTagN("cats", 3)
+		tc.AddTag("dog")
+
+		mapped := tc.MapTags(func(tag string) string {
+			return strings.TrimSuffix(tag, "s")
+		})
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "cat", OccurrenceCount: 5}, {Tag: "dog", OccurrenceCount: 1}}, mapped.TopN(10))
+		assert.Equal(t, 6, mapped.Total())
+		assert.Equal(t, 3, tc.Count("cats"))
+	})
+
+	t.Run("ok, mapped tags are normalized", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithCaseInsensitive())
+		tc.AddTagN("go", 2)
+		tc.AddTag("rust")
+
+		mapped := tc.MapTags(func(tag string) string {
+			if tag == "rust" {
+				return " "
+			}
+			return strings.ToUpper(tag)
+		})
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "go", OccurrenceCount: 2}}, mapped.TopN(10))
+	})
+
+	t.Run("ok, weights and pairs follow tags", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddWeighted("go", 2.5)
+		tc.AddTagSet("go", "gopher")
+
+		mapped := tc.MapTags(strings.ToUpper)
+
+		assert.Equal(t, 3.5, mapped.Weight("GO"))
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "GOPHER", OccurrenceCount: 1}}, mapped.Related("GO", 10))
+	})
+}
+
+func TestWithout(t *testing.T) {
+	t.Run("ok, subtracts counts", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("go", 5)
+		tc.AddTagN("the", 2)
+		other := tagcloud.New()
+		other.AddTagN("go", 2)
+		other.AddTag("rust")
+
+		result := tc.Without(other)
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "go", OccurrenceCount: 3}, {Tag: "the", OccurrenceCount: 2}}, result.TopN(10))
+		assert.Equal(t, 5, result.Total())
+		assert.Equal(t, 5, tc.Count("go"))
+		assert.Equal(t, 3, other.Total())
+	})
+
+	t.Run("ok, subtraction to zero drops tags", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN("go", 2)
+		tc.AddTag("the")
+		other := tagcloud.New()
+		other.AddTagN("the", 3)
+		other.AddTagN("go", 2)
+
+		result := tc.Without(other)
+
+		assert.Equal(t, 0, result.Len())
+		assert.Equal(t, 0, result.Total())
+		assert.False(t, result.Contains("the"))
+	})
+
+	t.Run("ok, nil other", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag("go")
+
+		assert.Equal(t, tc.TopN(10), tc.Without(nil).TopN(10))
+	})
+}
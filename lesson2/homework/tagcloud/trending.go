@@ -0,0 +1,60 @@
+package tagcloud
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// TrendStat describes the change of a tag count between two clouds.
+// Ratio is After / Before, it's +Inf for a new tag and zero for a vanished one
+type TrendStat struct {
+	Tag    string
+	Before int
+	After  int
+	Delta  int
+	Ratio  float64
+}
+
+// Trending returns at most n tags which counts grew the most from before to after and at most n tags
+// which counts dropped the most, tags with equal change are ordered by tag.
+// tags present in only one cloud count as zero in the other one, unchanged tags are in neither list.
+// nil clouds are treated as empty, n <= 0 gives empty slices
+func Trending(before, after *TagCloud, n int) (up, down []TrendStat) {
+	up, down = []TrendStat{}, []TrendStat{}
+	if n <= 0 {
+		return up, down
+	}
+	beforeTags, afterTags := before.countsCopy(), after.countsCopy()
+	for tag, count := range afterTags {
+		if delta := count - beforeTags[tag]; delta > 0 {
+			up = append(up, newTrendStat(tag, beforeTags[tag], count))
+		}
+	}
+	for tag, count := range beforeTags {
+		if delta := afterTags[tag] - count; delta < 0 {
+			down = append(down, newTrendStat(tag, count, afterTags[tag]))
+		}
+	}
+	slices.SortFunc(up, func(a, b TrendStat) int {
+		if c := cmp.Compare(b.Delta, a.Delta); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Tag, b.Tag)
+	})
+	slices.SortFunc(down, func(a, b TrendStat) int {
+		if c := cmp.Compare(a.Delta, b.Delta); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Tag, b.Tag)
+	})
+	return up[:min(n, len(up))], down[:min(n, len(down))]
+}
+
+func newTrendStat(tag string, before, after int) TrendStat {
+	ratio := math.Inf(1)
+	if before > 0 {
+		ratio = float64(after) / float64(before)
+	}
+	return TrendStat{Tag: tag, Before: before, After: after, Delta: after - before, Ratio: ratio}
+}
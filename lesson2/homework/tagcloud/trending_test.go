@@ -0,0 +1,59 @@
+package tagcloud_test
+
+import (
+	"lecture02_homework/tagcloud"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrending(t *testing.T) {
+	yesterday := tagcloud.New()
+	yesterday.AddTagN("go", 10)
+	yesterday.AddTagN("rust", 4)
+	yesterday.AddTagN("java", 8)
+	yesterday.AddTagN("perl", 3)
+	yesterday.AddTagN("c", 5)
+	today := tagcloud.New()
+	today.AddTagN("go", 15)
+	today.AddTagN("rust", 9)
+	today.AddTagN("java", 2)
+	today.AddTagN("zig", 5)
+	today.AddTagN("c", 5)
+
+	t.Run("ok, new, vanished and unchanged tags", func(t *testing.T) {
+		up, down := tagcloud.Trending(yesterday, today, 10)
+
+		assert.Equal(t, []tagcloud.TrendStat{
+			{Tag: "go", Before: 10, After: 15, Delta: 5, Ratio: 1.5},
+			{Tag: "rust", Before: 4, After: 9, Delta: 5, Ratio: 2.25},
+			{Tag: "zig", After: 5, Delta: 5, Ratio: math.Inf(1)},
+		}, up)
+		assert.Equal(t, []tagcloud.TrendStat{
+			{Tag: "java", Before: 8, After: 2, Delta: -6, Ratio: 0.25},
+			{Tag: "perl", Before: 3, Delta: -3, Ratio: 0},
+		}, down)
+	})
+
+	t.Run("ok, limited", func(t *testing.T) {
+		up, down := tagcloud.Trending(yesterday, today, 1)
+
+		assert.Equal(t, []tagcloud.TrendStat{{Tag: "go", Before: 10, After: 15, Delta: 5, Ratio: 1.5}}, up)
+		assert.Equal(t, []tagcloud.TrendStat{{Tag: "java", Before: 8, After: 2, Delta: -6, Ratio: 0.25}}, down)
+	})
+
+	t.Run("ok, nil and equal clouds", func(t *testing.T) {
+		up, down := tagcloud.Trending(nil, yesterday, 1)
+		assert.Equal(t, []tagcloud.TrendStat{{Tag: "go", After: 10, Delta: 10, Ratio: math.Inf(1)}}, up)
+		assert.Equal(t, []tagcloud.TrendStat{}, down)
+
+		up, down = tagcloud.Trending(today, today.Clone(), 5)
+		assert.Equal(t, []tagcloud.TrendStat{}, up)
+		assert.Equal(t, []tagcloud.TrendStat{}, down)
+
+		up, down = tagcloud.Trending(yesterday, today, 0)
+		assert.Equal(t, []tagcloud.TrendStat{}, up)
+		assert.Equal(t, []tagcloud.TrendStat{}, down)
+	})
+}
@@ -0,0 +1,128 @@
+package tagcloud
+
+import (
+	"container/list"
+	"time"
+)
+
+// WithTTL makes tags expire when they aren't added for d. every count increase refreshes the tag,
+// an expired tag is removed with its whole count, so it's excluded from all queries.
+// expired tags are removed lazily by the next method call or by Expire. non-positive d means no expiration
+func WithTTL(d time.Duration) Option {
+	return func(cloud *TagCloud) {
+		cloud.ttl = d
+	}
+}
+
+// WithClock sets the function giving the current time for WithTTL, time.Now by default
+func WithClock(now func() time.Time) Option {
+	return func(cloud *TagCloud) {
+		cloud.clock = now
+	}
+}
+
+// Expire removes tags which expired at now and returns their number, a cloud without WithTTL has nothing to expire
+func (cloud *TagCloud) Expire(now time.Time) int {
+	// lock would purge tags expired at the clock time, so they wouldn't be counted
+	if cloud.concurrent {
+		cloud.mu.Lock()
+	}
+	defer cloud.unlock()
+	return cloud.purge(now)
+}
+
+func (cloud *TagCloud) now() time.Time {
+	if cloud.clock != nil {
+		return cloud.clock()
+	}
+	return time.Now()
+}
+
+// purge removes tags expired at now, the least recently added tag is always the first to expire
+func (cloud *TagCloud) purge(now time.Time) int {
+	purged := 0
+	for cloud.recent != nil && cloud.recent.expired(cloud.ttl, now) {
+		cloud.setCount(cloud.recent.oldest().tag, 0)
+		purged++
+	}
+	return purged
+}
+
+// purgeBeforeRead removes expired tags before the cloud is read locked,
+// a concurrent cloud takes the exclusive lock only if there are expired tags
+func (cloud *TagCloud) purgeBeforeRead() {
+	if cloud.ttl <= 0 {
+		return
+	}
+	if cloud.concurrent {
+		cloud.mu.RLock()
+		expired := cloud.recent != nil && cloud.recent.expired(cloud.ttl, cloud.now())
+		cloud.mu.RUnlock()
+		if !expired {
+			return
+		}
+	}
+	cloud.lock()
+	cloud.unlock()
+}
+
+// touch refreshes the tag after its count is increased
+func (cloud *TagCloud) touch(tag string) {
+	if cloud.ttl <= 0 {
+		return
+	}
+	if cloud.recent == nil {
+		cloud.recent = newRecency()
+	}
+	cloud.recent.Touch(tag, cloud.now())
+}
+
+// recency keeps tags ordered by the time they were last added
+type recency struct {
+	order    *list.List
+	elements map[string]*list.Element
+}
+
+type recencyEntry struct {
+	tag  string
+	seen time.Time
+}
+
+func newRecency() *recency {
+	return &recency{order: list.New(), elements: map[string]*list.Element{}}
+}
+
+func (r *recency) Touch(tag string, now time.Time) {
+	if element, ok := r.elements[tag]; ok {
+		// entries are stored by pointer, so touching a known tag doesn't allocate
+		element.Value.(*recencyEntry).seen = now
+		r.order.MoveToBack(element)
+		return
+	}
+	r.elements[tag] = r.order.PushBack(&recencyEntry{tag: tag, seen: now})
+}
+
+func (r *recency) Forget(tag string) {
+	if element, ok := r.elements[tag]; ok {
+		r.order.Remove(element)
+		delete(r.elements, tag)
+	}
+}
+
+func (r *recency) oldest() recencyEntry {
+	return *r.order.Front().Value.(*recencyEntry)
+}
+
+func (r *recency) expired(ttl time.Duration, now time.Time) bool {
+	return r.order.Len() > 0 && now.Sub(r.oldest().seen) >= ttl
+}
+
+// copy returns a recency with the same order
+func (r *recency) copy() *recency {
+	result := newRecency()
+	for element := r.order.Front(); element != nil; element = element.Next() {
+		entry := *element.Value.(*recencyEntry)
+		result.elements[entry.tag] = result.order.PushBack(&entry)
+	}
+	return result
+}
@@ -0,0 +1,121 @@
+package tagcloud_test
+
+import (
+	"lecture02_homework/tagcloud"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock is a clock advanced by tests
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestWithTTL(t *testing.T) {
+	for name, newCloud := range map[string]func(...tagcloud.Option) *tagcloud.TagCloud{
+		"new":        tagcloud.New,
+		"concurrent": tagcloud.NewConcurrent,
+		"ordered":    tagcloud.NewOrdered,
+	} {
+		t.Run("ok, expired tags disappear, "+name, func(t *testing.T) {
+			clock := newFakeClock()
+			tc := newCloud(tagcloud.WithTTL(time.Minute), tagcloud.WithClock(clock.Now))
+			tc.AddTagN("go", 5)
+			clock.Advance(30 * time.Second)
+			tc.AddTag("rust")
+
+			clock.Advance(30 * time.Second)
+
+			assert.Equal(t, []tagcloud.TagStat{{Tag: "rust", OccurrenceCount: 1}}, tc.TopN(10))
+			assert.Equal(t, 0, tc.Count("go"))
+			assert.Equal(t, 1, tc.Len())
+			assert.Equal(t, 1, tc.Total())
+
+			clock.Advance(30 * time.Second)
+
+			assert.Equal(t, 0, tc.Len())
+			assert.Empty(t, tc.TopN(10))
+		})
+	}
+
+	t.Run("ok, adding refreshes the tag", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := tagcloud.New(tagcloud.WithTTL(time.Minute), tagcloud.WithClock(clock.Now))
+		tc.AddTag("go")
+		clock.Advance(50 * time.Second)
+		tc.AddTag("go")
+		tc.RemoveTag("go")
+
+		clock.Advance(50 * time.Second)
+
+		assert.Equal(t, 1, tc.Count("go"))
+	})
+
+	t.Run("ok, expired count isn't continued", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := tagcloud.New(tagcloud.WithTTL(time.Minute), tagcloud.WithClock(clock.Now))
+		tc.AddTagN("go", 3)
+		clock.Advance(time.Minute)
+
+		tc.AddTag("go")
+
+		assert.Equal(t, 1, tc.Count("go"))
+	})
+
+	t.Run("ok, clone keeps expiration", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := tagcloud.New(tagcloud.WithTTL(time.Minute), tagcloud.WithClock(clock.Now))
+		tc.AddTag("go")
+		clock.Advance(30 * time.Second)
+		tc.AddTag("rust")
+		clone := tc.Clone()
+
+		clock.Advance(40 * time.Second)
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "rust", OccurrenceCount: 1}}, clone.TopN(10))
+	})
+}
+
+func TestExpire(t *testing.T) {
+	t.Run("ok, returns number of purged tags", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := tagcloud.New(tagcloud.WithTTL(time.Minute), tagcloud.WithClock(clock.Now))
+		tc.AddTags("go", "rust")
+		clock.Advance(30 * time.Second)
+		tc.AddTag("c")
+		start := clock.Now()
+
+		assert.Equal(t, 0, tc.Expire(start))
+		assert.Equal(t, 2, tc.Expire(start.Add(30*time.Second)))
+		assert.Equal(t, []string{"c"}, tc.TagsWithCount(1))
+		assert.Equal(t, 1, tc.Expire(start.Add(time.Hour)))
+	})
+
+	t.Run("ok, cloud without TTL", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag("go")
+
+		assert.Equal(t, 0, tc.Expire(time.Now().Add(time.Hour)))
+		assert.Equal(t, 1, tc.Len())
+	})
+}
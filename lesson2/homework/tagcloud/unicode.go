@@ -0,0 +1,81 @@
+package tagcloud
+
+import "golang.org/x/text/unicode/norm"
+
+// NormalizationForm is a Unicode normalization form, see WithUnicodeNormalization
+type NormalizationForm int
+
+const (
+	// NFC composes characters, e.g. "e" with a combining acute accent becomes "é"
+	NFC NormalizationForm = iota
+	// NFD decomposes characters
+	NFD
+	// NFKC composes characters after replacing compatibility characters, e.g. "ﬁ" becomes "fi"
+	NFKC
+	// NFKD decomposes characters after replacing compatibility characters
+	NFKD
+)
+
+// norm returns the form of the norm package, unknown forms mean NFC
+func (form NormalizationForm) norm() norm.Form {
+	switch form {
+	case NFD:
+		return norm.NFD
+	case NFKC:
+		return norm.NFKC
+	case NFKD:
+		return norm.NFKD
+	}
+	return norm.NFC
+}
+
+// WithUnicodeNormalization converts every tag to the form before normalizers run, so precomposed
+// and decomposed spellings of a tag are the same tag. the zero form is NFC
+func WithUnicodeNormalization(form NormalizationForm) Option {
+	return func(cloud *TagCloud) {
+		cloud.unicodeNormalization = true
+		cloud.unicodeForm = form.norm()
+	}
+}
+
+// NormalizeExisting converts stored tags to the form and merges tags which become equal
+// with their weights, co-occurrence and times, e.g. after WithUnicodeNormalization is enabled for a restored cloud.
+// converted tags are normalized by the cloud again. it returns the number of tags merged into others
+func (cloud *TagCloud) NormalizeExisting(form NormalizationForm) int {
+	unicodeForm := form.norm()
+	cloud.lock()
+	defer cloud.unlock()
+	before := cloud.tags.Len()
+	variants := tagData{tags: map[string]int{}, extraWeights: map[string]float64{}, pairs: map[tagPair]int{}, seen: map[string]seenTimes{}}
+	for tag, count := range cloud.tags.All() {
+		if unicodeForm.IsNormalString(tag) {
+			continue
+		}
+		normal := unicodeForm.String(tag)
+		variants.tags[normal] += count
+		if weight := cloud.extraWeights[tag]; weight != 0 {
+			variants.extraWeights[normal] += weight
+		}
+		if times, ok := cloud.seen[tag]; ok {
+			if other, ok := variants.seen[normal]; ok {
+				times = times.join(other)
+			}
+			variants.seen[normal] = times
+		}
+	}
+	if len(variants.tags) == 0 {
+		return 0
+	}
+	for pair, count := range cloud.pairs {
+		if !unicodeForm.IsNormalString(pair.first) || !unicodeForm.IsNormalString(pair.second) {
+			variants.pairs[newTagPair(unicodeForm.String(pair.first), unicodeForm.String(pair.second))] += count
+		}
+	}
+	for tag := range cloud.tags.All() {
+		if !unicodeForm.IsNormalString(tag) {
+			cloud.setCount(tag, 0)
+		}
+	}
+	cloud.mergeData(variants)
+	return before - cloud.tags.Len()
+}
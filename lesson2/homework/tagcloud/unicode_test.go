@@ -0,0 +1,73 @@
+package tagcloud_test
+
+import (
+	"lecture02_homework/tagcloud"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	precomposed = "caf\u00e9"
+	decomposed  = "cafe\u0301"
+)
+
+func TestWithUnicodeNormalization(t *testing.T) {
+	t.Run("ok, nfc by default", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithUnicodeNormalization(tagcloud.NFC), tagcloud.WithCaseInsensitive())
+		tc.AddTags(precomposed, decomposed, "CAFÉ")
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: precomposed, OccurrenceCount: 3}}, tc.TopN(10))
+		assert.Equal(t, 3, tc.Count(decomposed))
+	})
+
+	t.Run("ok, other forms", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithUnicodeNormalization(tagcloud.NFD))
+		tc.AddTags(precomposed, decomposed)
+		assert.Equal(t, []tagcloud.TagStat{{Tag: decomposed, OccurrenceCount: 2}}, tc.TopN(10))
+
+		tc = tagcloud.New(tagcloud.WithUnicodeNormalization(tagcloud.NFKC))
+		tc.AddTags("\ufb01le", "file")
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "file", OccurrenceCount: 2}}, tc.TopN(10))
+	})
+
+	t.Run("ok, without option", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTags(precomposed, decomposed)
+
+		assert.Equal(t, 2, tc.Len())
+	})
+}
+
+func TestNormalizeExisting(t *testing.T) {
+	t.Run("ok, variants are merged", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTagN(precomposed, 2)
+		tc.AddTagSet(decomposed, "go")
+		tc.AddTagSet(precomposed, "go")
+		require.NoError(t, tc.AddWeighted(decomposed, 3))
+		tc.AddTag("rust")
+
+		merged := tc.NormalizeExisting(tagcloud.NFC)
+
+		assert.Equal(t, 1, merged)
+		assert.Equal(t, []tagcloud.TagStat{
+			{Tag: precomposed, OccurrenceCount: 5},
+			{Tag: "go", OccurrenceCount: 2},
+			{Tag: "rust", OccurrenceCount: 1},
+		}, tc.TopN(10))
+		assert.Equal(t, 7.0, tc.Weight(precomposed))
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "go", OccurrenceCount: 2}}, tc.Related(precomposed, 10))
+		assert.Equal(t, 8, tc.Total())
+	})
+
+	t.Run("ok, renamed without collisions", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag(decomposed)
+
+		assert.Equal(t, 0, tc.NormalizeExisting(tagcloud.NFC))
+		assert.Equal(t, []tagcloud.TagStat{{Tag: precomposed, OccurrenceCount: 1}}, tc.TopN(10))
+		assert.Equal(t, 0, tc.NormalizeExisting(tagcloud.NFC))
+	})
+}
@@ -0,0 +1,160 @@
+package tagcloud
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// ErrEmptyTag is returned by AddTagE for tags which are empty after normalization
+	ErrEmptyTag = errors.New("empty tag")
+	// ErrTagTooLong is returned by AddTagE for tags longer than the WithMaxTagLen or MaxLen limit
+	ErrTagTooLong = errors.New("tag is too long")
+	// ErrInvalidUTF8 is returned by the ValidUTF8 validator
+	ErrInvalidUTF8 = errors.New("tag is not valid UTF-8")
+	// ErrNotPrintable is returned by the Printable validator
+	ErrNotPrintable = errors.New("tag has non-printable characters")
+)
+
+// InvalidTag is the reserved tag counting invalid tags of a cloud with WithInvalidBucket
+const InvalidTag = "<invalid>"
+
+// Validator returns an error for a tag which must not be counted, see WithValidator
+type Validator func(tag string) error
+
+// WithValidator adds a check of every normalized tag, validators run in the order the options are passed
+// after the WithMaxTagLen check, so several validators are composed by passing several options.
+// a tag failing a check is ignored, or reported by AddTagE with WithStrictValidation
+func WithValidator(validator func(string) error) Option {
+	return func(cloud *TagCloud) {
+		cloud.validators = append(cloud.validators, validator)
+	}
+}
+
+// WithInvalidBucket makes the cloud count tags failing WithMaxTagLen or WithValidator checks as InvalidTag
+// instead of ignoring them, AddTagE doesn't report them even with WithStrictValidation
+func WithInvalidBucket() Option {
+	return func(cloud *TagCloud) {
+		cloud.invalidBucket = true
+	}
+}
+
+// ValidUTF8 rejects tags which aren't valid UTF-8
+func ValidUTF8() Validator {
+	return func(tag string) error {
+		if !utf8.ValidString(tag) {
+			return fmt.Errorf("%w: %q", ErrInvalidUTF8, tag)
+		}
+		return nil
+	}
+}
+
+// MaxLen rejects tags longer than n runes
+func MaxLen(n int) Validator {
+	return func(tag string) error {
+		return checkLen(tag, n)
+	}
+}
+
+// Printable rejects tags with runes which aren't printable by unicode.IsPrint, spaces other than U+0020 included
+func Printable() Validator {
+	return func(tag string) error {
+		for i, r := range tag {
+			if !unicode.IsPrint(r) || r == utf8.RuneError {
+				return fmt.Errorf("%w: %q at byte %d", ErrNotPrintable, r, i)
+			}
+		}
+		return nil
+	}
+}
+
+func checkLen(tag string, n int) error {
+	if length := utf8.RuneCountInString(tag); length > n {
+		return fmt.Errorf("%w: %d runes, at most %d allowed", ErrTagTooLong, length, n)
+	}
+	return nil
+}
+
+// errStopWord marks stop words, they are ignored without an error even with strict validation
+var errStopWord = errors.New("stop word")
+
+// WithStrictValidation makes AddTagE return an error for an invalid tag instead of ignoring it.
+// AddTag and other methods without error result keep ignoring invalid tags
+func WithStrictValidation() Option {
+	return func(cloud *TagCloud) {
+		cloud.strict = true
+	}
+}
+
+// WithMaxTagLen makes tags longer than n runes invalid, non-positive n means no limit
+func WithMaxTagLen(n int) Option {
+	return func(cloud *TagCloud) {
+		cloud.maxTagLen = n
+	}
+}
+
+// AddTagE works as AddTag, but with strict validation an invalid tag is reported as an error.
+// by default surrounding whitespace is trimmed, tags becoming empty or exceeding the length limit are ignored
+func (cloud *TagCloud) AddTagE(tag string) error {
+	key, ok, err := cloud.acceptTag(tag)
+	if !ok {
+		return err
+	}
+	cloud.lock()
+	defer cloud.unlock()
+	cloud.setCount(key, cloud.tags.Count(key)+1)
+	cloud.noteSurface(tag, key, 1)
+	return nil
+}
+
+// acceptTag returns the key of a tag to add, ok is false if the tag must be skipped.
+// the reason is returned only with strict validation, stop words are skipped silently
+func (cloud *TagCloud) acceptTag(tag string) (key string, ok bool, err error) {
+	key, err = cloud.checkTag(tag)
+	switch {
+	case err == errStopWord || err != nil && !cloud.strict:
+		return "", false, nil
+	case err != nil:
+		return "", false, err
+	}
+	return key, true, nil
+}
+
+// checkTag returns the normalized tag or the reason why the tag must be ignored
+func (cloud *TagCloud) checkTag(tag string) (string, error) {
+	return cloud.checkKey(tag, cloud.normalize(tag))
+}
+
+// checkKey validates the normalized key of the tag
+func (cloud *TagCloud) checkKey(tag, key string) (string, error) {
+	if key == "" {
+		return "", fmt.Errorf("%w: %q", ErrEmptyTag, tag)
+	}
+	if err := cloud.validate(key); err != nil {
+		if cloud.invalidBucket {
+			return InvalidTag, nil
+		}
+		return "", err
+	}
+	if _, ok := cloud.stop[key]; ok {
+		return "", errStopWord
+	}
+	return key, nil
+}
+
+// validate runs the length limit and validators on the normalized tag
+func (cloud *TagCloud) validate(key string) error {
+	if cloud.maxTagLen > 0 {
+		if err := checkLen(key, cloud.maxTagLen); err != nil {
+			return err
+		}
+	}
+	for _, validator := range cloud.validators {
+		if err := validator(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package tagcloud_test
+
+import (
+	"errors"
+	"lecture02_homework/tagcloud"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagValidation(t *testing.T) {
+	t.Run("ok, whitespace is trimmed", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTags(" go", "go\t", "\n go \n")
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "go", OccurrenceCount: 3}}, tc.TopN(10))
+		assert.Equal(t, 3, tc.Count(" go "))
+	})
+
+	t.Run("ok, empty tags are ignored", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag("")
+		tc.AddTag("   ")
+		tc.AddTagN("\t\n", 3)
+
+		assert.NoError(t, tc.AddTagE(" "))
+		assert.Equal(t, 0, tc.Len())
+		assert.Empty(t, tc.TopN(10))
+	})
+
+	t.Run("ok, long tags are ignored", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithMaxTagLen(4))
+		tc.AddTags("тэги", "tags!", " tag ")
+
+		assert.NoError(t, tc.AddTagE("too long"))
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "tag", OccurrenceCount: 1}, {Tag: "тэги", OccurrenceCount: 1}}, tc.TopN(10))
+	})
+
+	t.Run("ok, strict validation accepts valid tags", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithStrictValidation(), tagcloud.WithStopWords("the"))
+
+		assert.NoError(t, tc.AddTagE(" go "))
+		assert.NoError(t, tc.AddTagE("the"))
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "go", OccurrenceCount: 1}}, tc.TopN(10))
+	})
+
+	t.Run("error, strict validation of empty tag", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithStrictValidation())
+
+		err := tc.AddTagE("  ")
+
+		assert.ErrorIs(t, err, tagcloud.ErrEmptyTag)
+		assert.Equal(t, 0, tc.Len())
+		// AddTag can't report the error, so the tag is ignored
+		tc.AddTag("  ")
+		assert.Equal(t, 0, tc.Len())
+	})
+
+	t.Run("error, strict validation of long tag", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithStrictValidation(), tagcloud.WithMaxTagLen(3))
+
+		err := tc.AddTagE("long")
+
+		assert.ErrorIs(t, err, tagcloud.ErrTagTooLong)
+		assert.EqualError(t, err, "tag is too long: 4 runes, at most 3 allowed")
+		assert.Equal(t, 0, tc.Len())
+	})
+}
+
+func TestWithValidator(t *testing.T) {
+	invalid := []string{"g\xffo", "go\x00", "tab\tin", "\x1b[31mred"}
+
+	t.Run("ok, invalid tags are ignored", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithValidator(tagcloud.ValidUTF8()), tagcloud.WithValidator(tagcloud.Printable()))
+		tc.AddTags(invalid...)
+		tc.AddTags("go", "машина")
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "go", OccurrenceCount: 1}, {Tag: "машина", OccurrenceCount: 1}}, tc.TopN(10))
+	})
+
+	t.Run("ok, invalid bucket", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithStrictValidation(), tagcloud.WithInvalidBucket(),
+			tagcloud.WithValidator(tagcloud.Printable()), tagcloud.WithValidator(tagcloud.MaxLen(4)))
+		for _, tag := range invalid {
+			assert.NoError(t, tc.AddTagE(tag))
+		}
+		tc.AddTags("go", "too long")
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: tagcloud.InvalidTag, OccurrenceCount: 5}, {Tag: "go", OccurrenceCount: 1}}, tc.TopN(10))
+		assert.Equal(t, 6, tc.Total())
+	})
+
+	t.Run("ok, custom validator", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithValidator(func(tag string) error {
+			if strings.HasPrefix(tag, "#") {
+				return errors.New("hashtag")
+			}
+			return nil
+		}))
+		tc.AddTags("#go", "go")
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "go", OccurrenceCount: 1}}, tc.TopN(10))
+	})
+
+	t.Run("error, invalid utf-8", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithStrictValidation(), tagcloud.WithValidator(tagcloud.ValidUTF8()))
+
+		err := tc.AddTagE("g\xffo")
+
+		assert.ErrorIs(t, err, tagcloud.ErrInvalidUTF8)
+		assert.EqualError(t, err, `tag is not valid UTF-8: "g\xffo"`)
+		assert.NoError(t, tc.AddTagE("go\x00"))
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "go\x00", OccurrenceCount: 1}}, tc.TopN(10))
+	})
+
+	t.Run("error, control characters", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithStrictValidation(), tagcloud.WithValidator(tagcloud.Printable()))
+
+		err := tc.AddTagE("go\x00")
+		assert.ErrorIs(t, err, tagcloud.ErrNotPrintable)
+		assert.EqualError(t, err, `tag has non-printable characters: '\x00' at byte 2`)
+		assert.ErrorIs(t, tc.AddTagE("tab\tin"), tagcloud.ErrNotPrintable)
+		assert.ErrorIs(t, tc.AddTagE("g\xffo"), tagcloud.ErrNotPrintable)
+		assert.NoError(t, tc.AddTagE("go lang"))
+		assert.Equal(t, 1, tc.Len())
+	})
+
+	t.Run("error, composed validators report the first failure", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithStrictValidation(),
+			tagcloud.WithValidator(tagcloud.MaxLen(3)), tagcloud.WithValidator(tagcloud.Printable()))
+
+		assert.EqualError(t, tc.AddTagE("long\x00"), "tag is too long: 5 runes, at most 3 allowed")
+		assert.ErrorIs(t, tc.AddTagE("g\x00"), tagcloud.ErrNotPrintable)
+		assert.Equal(t, 0, tc.Len())
+	})
+}
@@ -0,0 +1,80 @@
+package tagcloud
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// WithVelocity makes the cloud count additions of every tag in buckets of the given duration aligned
+// to the clock set by WithClock, see Velocity. non-positive bucket disables counting
+func WithVelocity(bucket time.Duration) Option {
+	return func(cloud *TagCloud) {
+		cloud.velocityBucket = bucket
+	}
+}
+
+// Velocity returns additions of the tag per minute in the current bucket of WithVelocity,
+// a tag without additions in the current bucket and a cloud without WithVelocity give 0
+func (cloud *TagCloud) Velocity(tag string) float64 {
+	tag = cloud.normalize(tag)
+	cloud.rlock()
+	defer cloud.runlock()
+	return cloud.velocityOf(cloud.currentVelocity()[tag])
+}
+
+// TopNByVelocity returns at most n tags added in the current bucket of WithVelocity ordered by Velocity descending
+// and then by tag, stats hold counts of tags in the cloud. n <= 0 gives an empty slice, the result is never nil
+func (cloud *TagCloud) TopNByVelocity(n int) []TagStat {
+	cloud.rlock()
+	defer cloud.runlock()
+	if n <= 0 {
+		return []TagStat{}
+	}
+	additions := cloud.currentVelocity()
+	stats := make([]TagStat, 0, len(additions))
+	for tag := range additions {
+		stats = append(stats, TagStat{Tag: tag, OccurrenceCount: cloud.tags.Count(tag)})
+	}
+	slices.SortFunc(stats, func(a, b TagStat) int {
+		if c := cmp.Compare(additions[b.Tag], additions[a.Tag]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Tag, b.Tag)
+	})
+	stats = stats[:min(n, len(stats))]
+	cloud.fillErrorBounds(stats)
+	cloud.fillDisplayForms(stats)
+	return stats
+}
+
+// currentVelocity returns additions in the current bucket, additions of a past bucket are stale
+// until the next addition starts a new one
+func (cloud *TagCloud) currentVelocity() map[string]int {
+	if cloud.velocityBucket <= 0 || !cloud.now().Truncate(cloud.velocityBucket).Equal(cloud.velocityStart) {
+		return nil
+	}
+	return cloud.velocity
+}
+
+func (cloud *TagCloud) velocityOf(additions int) float64 {
+	if additions == 0 {
+		return 0
+	}
+	return float64(additions) / cloud.velocityBucket.Minutes()
+}
+
+// markVelocity counts added occurrences of the tag in the current bucket, a new bucket forgets the past one
+func (cloud *TagCloud) markVelocity(tag string, added int) {
+	if cloud.velocityBucket <= 0 {
+		return
+	}
+	if start := cloud.now().Truncate(cloud.velocityBucket); !start.Equal(cloud.velocityStart) {
+		cloud.velocity = nil
+		cloud.velocityStart = start
+	}
+	if cloud.velocity == nil {
+		cloud.velocity = map[string]int{}
+	}
+	cloud.velocity[tag] += added
+}
@@ -0,0 +1,79 @@
+package tagcloud_test
+
+import (
+	"lecture02_homework/tagcloud"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithVelocity(t *testing.T) {
+	t.Run("ok, ranking follows recent rates", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := tagcloud.New(tagcloud.WithClock(clock.Now), tagcloud.WithVelocity(time.Minute))
+		// go is added faster in the first minute
+		for i := 0; i < 6; i++ {
+			tc.AddTagN("go", 2)
+			tc.AddTag("rust")
+			clock.Advance(5 * time.Second)
+		}
+
+		assert.Equal(t, 12.0, tc.Velocity("go"))
+		assert.Equal(t, 6.0, tc.Velocity("rust"))
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "go", OccurrenceCount: 12}, {Tag: "rust", OccurrenceCount: 6}}, tc.TopNByVelocity(10))
+
+		// rust overtakes go in the next minute
+		clock.Advance(30 * time.Second)
+		for i := 0; i < 6; i++ {
+			tc.AddTag("go")
+			tc.AddTagN("rust", 3)
+			clock.Advance(5 * time.Second)
+		}
+
+		assert.Equal(t, 6.0, tc.Velocity("go"))
+		assert.Equal(t, 18.0, tc.Velocity("rust"))
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "rust", OccurrenceCount: 24}, {Tag: "go", OccurrenceCount: 18}}, tc.TopNByVelocity(10))
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "rust", OccurrenceCount: 24}}, tc.TopNByVelocity(1))
+	})
+
+	t.Run("ok, inactive tags are excluded", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := tagcloud.New(tagcloud.WithClock(clock.Now), tagcloud.WithVelocity(30*time.Second))
+		tc.AddTagN("go", 3)
+		clock.Advance(30 * time.Second)
+
+		assert.Zero(t, tc.Velocity("go"))
+		assert.Empty(t, tc.TopNByVelocity(10))
+
+		tc.AddTag("rust")
+
+		assert.Zero(t, tc.Velocity("go"))
+		assert.Equal(t, 2.0, tc.Velocity("rust"))
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "rust", OccurrenceCount: 1}}, tc.TopNByVelocity(10))
+	})
+
+	t.Run("ok, removed tag has no velocity", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := tagcloud.New(tagcloud.WithClock(clock.Now), tagcloud.WithVelocity(time.Minute))
+		tc.AddTagN("go", 2)
+		tc.RemoveTag("go")
+
+		assert.Equal(t, 2.0, tc.Velocity("go"))
+
+		tc.DeleteTag("go")
+
+		assert.Zero(t, tc.Velocity("go"))
+		assert.Empty(t, tc.TopNByVelocity(10))
+	})
+
+	t.Run("ok, no velocity without option", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag("go")
+
+		assert.Zero(t, tc.Velocity("go"))
+		assert.NotNil(t, tc.TopNByVelocity(10))
+		assert.Empty(t, tc.TopNByVelocity(10))
+		assert.Empty(t, tc.TopNByVelocity(0))
+	})
+}
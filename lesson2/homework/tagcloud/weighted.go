@@ -0,0 +1,82 @@
+package tagcloud
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// WeightedTagStat represents weight and occurrence count of a single tag
+type WeightedTagStat struct {
+	Tag             string
+	Weight          float64
+	OccurrenceCount int
+}
+
+// AddWeighted adds one occurrence of the tag with the given weight, AddTag is the same as weight 1.
+// weights may be negative, a tag stays present even if its weight drops to zero or below.
+// RemoveTag takes away weight 1 together with the occurrence.
+// NaN and infinite weights are rejected, invalid tags are handled as in AddTagE
+func (cloud *TagCloud) AddWeighted(tag string, weight float64) error {
+	if math.IsNaN(weight) || math.IsInf(weight, 0) {
+		return fmt.Errorf("invalid weight %v for tag %q", weight, tag)
+	}
+	key, ok, err := cloud.acceptTag(tag)
+	if !ok {
+		return err
+	}
+	cloud.lock()
+	defer cloud.unlock()
+	cloud.setCount(key, cloud.tags.Count(key)+1)
+	cloud.noteSurface(tag, key, 1)
+	cloud.addExtraWeight(key, weight-1)
+	return nil
+}
+
+// Weight returns the weight of the tag, zero if the tag is absent
+func (cloud *TagCloud) Weight(tag string) float64 {
+	tag = cloud.normalize(tag)
+	cloud.rlock()
+	defer cloud.runlock()
+	return cloud.weight(tag)
+}
+
+// TopNByWeight returns n tags with the greatest weight in descending order,
+// tags with the same weight are ordered lexicographically.
+// if n is greater than TagCloud size then all elements are returned, n <= 0 gives an empty slice
+func (cloud *TagCloud) TopNByWeight(n int) []WeightedTagStat {
+	cloud.rlock()
+	stats := make([]WeightedTagStat, 0, cloud.tags.Len())
+	for tag, count := range cloud.tags.All() {
+		stats = append(stats, WeightedTagStat{Tag: tag, Weight: cloud.weight(tag), OccurrenceCount: count})
+	}
+	cloud.runlock()
+	slices.SortFunc(stats, func(a, b WeightedTagStat) int {
+		if c := cmp.Compare(b.Weight, a.Weight); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Tag, b.Tag)
+	})
+	if len(stats) < n {
+		n = len(stats)
+	}
+	if n < 0 {
+		n = 0
+	}
+	return stats[:n]
+}
+
+func (cloud *TagCloud) weight(tag string) float64 {
+	return float64(cloud.tags.Count(tag)) + cloud.extraWeights[tag]
+}
+
+func (cloud *TagCloud) addExtraWeight(tag string, weight float64) {
+	if cloud.extraWeights == nil {
+		cloud.extraWeights = map[string]float64{}
+	}
+	cloud.extraWeights[tag] += weight
+	if cloud.extraWeights[tag] == 0 {
+		delete(cloud.extraWeights, tag)
+	}
+}
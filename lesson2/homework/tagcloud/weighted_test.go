@@ -0,0 +1,97 @@
+package tagcloud_test
+
+import (
+	"lecture02_homework/tagcloud"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddWeighted(t *testing.T) {
+	t.Run("ok, mixed with AddTag", func(t *testing.T) {
+		tc := tagcloud.New()
+		tc.AddTag("go")
+		assert.NoError(t, tc.AddWeighted("go", 2.5))
+		assert.NoError(t, tc.AddWeighted("rust", 0.5))
+		tc.AddTagN("rust", 2)
+
+		assert.Equal(t, 3.5, tc.Weight("go"))
+		assert.Equal(t, 2.5, tc.Weight("rust"))
+		assert.Equal(t, 0.0, tc.Weight("missing"))
+		assert.Equal(t, []tagcloud.WeightedTagStat{
+			{Tag: "go", Weight: 3.5, OccurrenceCount: 2},
+			{Tag: "rust", Weight: 2.5, OccurrenceCount: 3},
+		}, tc.TopNByWeight(10))
+		// TopN orders by occurrence count
+		assert.Equal(t, "rust", tc.TopN(1)[0].Tag)
+	})
+
+	t.Run("ok, order by weight with ties by tag", func(t *testing.T) {
+		tc := tagcloud.New()
+		assert.NoError(t, tc.AddWeighted("c", 2))
+		assert.NoError(t, tc.AddWeighted("b", 0.25))
+		assert.NoError(t, tc.AddWeighted("a", 2))
+		tc.AddTag("d")
+
+		assert.Equal(t, []tagcloud.WeightedTagStat{
+			{Tag: "a", Weight: 2, OccurrenceCount: 1},
+			{Tag: "c", Weight: 2, OccurrenceCount: 1},
+			{Tag: "d", Weight: 1, OccurrenceCount: 1},
+		}, tc.TopNByWeight(3))
+		assert.Empty(t, tc.TopNByWeight(0))
+		assert.NotNil(t, tc.TopNByWeight(-1))
+	})
+
+	t.Run("ok, negative weights", func(t *testing.T) {
+		tc := tagcloud.New()
+		assert.NoError(t, tc.AddWeighted("spam", 3))
+		assert.NoError(t, tc.AddWeighted("spam", -3))
+		assert.NoError(t, tc.AddWeighted("bad", -1))
+		tc.AddTag("good")
+
+		assert.True(t, tc.Contains("spam"))
+		assert.Equal(t, []tagcloud.WeightedTagStat{
+			{Tag: "good", Weight: 1, OccurrenceCount: 1},
+			{Tag: "spam", Weight: 0, OccurrenceCount: 2},
+			{Tag: "bad", Weight: -1, OccurrenceCount: 1},
+		}, tc.TopNByWeight(10))
+	})
+
+	t.Run("ok, remove, clone and merge keep weights", func(t *testing.T) {
+		tc := tagcloud.New()
+		assert.NoError(t, tc.AddWeighted("go", 5))
+		tc.AddTag("go")
+		tc.RemoveTag("go")
+		assert.Equal(t, 5.0, tc.Weight("go"))
+
+		clone := tc.Clone()
+		merged := tc.Merge(clone)
+		assert.Equal(t, 10.0, merged.Weight("go"))
+
+		tc.RemoveTag("go")
+		assert.Equal(t, 0.0, tc.Weight("go"))
+		assert.NoError(t, tc.AddWeighted("go", 2))
+		assert.Equal(t, 2.0, tc.Weight("go"))
+		assert.Equal(t, 5.0, clone.Weight("go"))
+
+		clone.Reset()
+		clone.AddTag("go")
+		assert.Equal(t, 1.0, clone.Weight("go"))
+	})
+
+	t.Run("error, invalid weights", func(t *testing.T) {
+		tc := tagcloud.New()
+
+		assert.EqualError(t, tc.AddWeighted("go", math.NaN()), `invalid weight NaN for tag "go"`)
+		assert.Error(t, tc.AddWeighted("go", math.Inf(1)))
+		assert.Error(t, tc.AddWeighted("go", math.Inf(-1)))
+		assert.False(t, tc.Contains("go"))
+	})
+
+	t.Run("error, strict validation", func(t *testing.T) {
+		tc := tagcloud.New(tagcloud.WithStrictValidation())
+
+		assert.ErrorIs(t, tc.AddWeighted(" ", 2), tagcloud.ErrEmptyTag)
+	})
+}
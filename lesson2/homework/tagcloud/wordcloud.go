@@ -0,0 +1,104 @@
+package tagcloud
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"unicode/utf8"
+)
+
+const (
+	// spiralGap is the distance in pixels between turns of the placement spiral
+	spiralGap = 6
+	// spiralStep is the distance in pixels between tried positions along the spiral
+	spiralStep = 3
+	// rotatedShare is the share of tags rotated by 90 degrees
+	rotatedShare = 0.25
+)
+
+// PlacedTag is a tag positioned on a word cloud canvas.
+// X, Y, Width and Height are the bounding box of the tag in pixels after rotation, Y grows downwards.
+// Rotation is 0 or 90 degrees clockwise
+type PlacedTag struct {
+	Tag      string
+	Count    int
+	FontSize float64
+	X        float64
+	Y        float64
+	Width    float64
+	Height   float64
+	Rotation int
+}
+
+// Layout places at most n top tags on a width x height canvas, non-positive n means all tags.
+// font sizes are computed as by RenderSVG with default sizes, tags are placed from the most frequent one
+// along an archimedean spiral around the center until the bounding box doesn't overlap placed tags.
+// tags which can't be placed within the canvas are returned as unplaced in TopN order.
+// the result is the same for the same cloud and seed, the seed chooses spiral directions and rotations
+func (cloud *TagCloud) Layout(width, height, n int, seed int64) (placed []PlacedTag, unplaced []TagStat, err error) {
+	if width <= 0 || height <= 0 {
+		return nil, nil, fmt.Errorf("invalid layout size %dx%d", width, height)
+	}
+	tags, err := cloud.layout(RenderOptions{N: n})
+	if err != nil {
+		return nil, nil, err
+	}
+	rnd := rand.New(rand.NewSource(seed))
+	placed, unplaced = []PlacedTag{}, []TagStat{}
+	for _, tag := range tags {
+		box := PlacedTag{
+			Tag:      tag.Tag,
+			Count:    tag.OccurrenceCount,
+			FontSize: tag.FontSize,
+			Width:    float64(utf8.RuneCountInString(tag.Tag)) * tag.FontSize * svgCharWidth,
+			Height:   tag.FontSize * svgLineHeight,
+		}
+		if rnd.Float64() < rotatedShare {
+			box.Rotation = 90
+			box.Width, box.Height = box.Height, box.Width
+		}
+		if placeOnSpiral(&box, placed, float64(width), float64(height), rnd.Float64()*2*math.Pi) {
+			placed = append(placed, box)
+		} else {
+			unplaced = append(unplaced, tag.TagStat)
+		}
+	}
+	return placed, unplaced, nil
+}
+
+// placeOnSpiral moves the box along the spiral starting at angle phase until it fits into the canvas
+// without overlapping placed boxes, false means the spiral left the canvas first
+func placeOnSpiral(box *PlacedTag, placed []PlacedTag, width, height, phase float64) bool {
+	if box.Width > width || box.Height > height {
+		return false
+	}
+	centerX, centerY := width/2, height/2
+	maxRadius := math.Hypot(centerX, centerY)
+	for theta := 0.0; ; {
+		radius := spiralGap * theta / (2 * math.Pi)
+		if radius > maxRadius {
+			return false
+		}
+		box.X = centerX + radius*math.Cos(theta+phase) - box.Width/2
+		box.Y = centerY + radius*math.Sin(theta+phase) - box.Height/2
+		if box.X >= 0 && box.Y >= 0 && box.X+box.Width <= width && box.Y+box.Height <= height && !overlapsAny(*box, placed) {
+			return true
+		}
+		theta += spiralStep / max(radius, spiralStep)
+	}
+}
+
+func overlapsAny(box PlacedTag, placed []PlacedTag) bool {
+	for _, other := range placed {
+		if box.Overlaps(other) {
+			return true
+		}
+	}
+	return false
+}
+
+// Overlaps reports whether bounding boxes of the tags intersect, touching boxes don't overlap
+func (tag PlacedTag) Overlaps(other PlacedTag) bool {
+	return tag.X < other.X+other.Width && other.X < tag.X+tag.Width &&
+		tag.Y < other.Y+other.Height && other.Y < tag.Y+tag.Height
+}
@@ -0,0 +1,80 @@
+package tagcloud_test
+
+import (
+	"fmt"
+	"lecture02_homework/tagcloud"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLayout(t *testing.T) {
+	tc := tagcloud.New()
+	for i := 0; i < 60; i++ {
+		tc.AddTagN(fmt.Sprintf("tag-%d", i), 60-i)
+	}
+
+	t.Run("ok, no overlaps", func(t *testing.T) {
+		placed, unplaced, err := tc.Layout(800, 600, 0, 1)
+
+		require.NoError(t, err)
+		assert.Len(t, placed, 60)
+		assert.Empty(t, unplaced)
+		assert.Equal(t, "tag-0", placed[0].Tag)
+		assert.Equal(t, 60, placed[0].Count)
+		assert.Equal(t, float64(tagcloud.DefaultMaxFontSize), placed[0].FontSize)
+		for i, tag := range placed {
+			assert.GreaterOrEqual(t, tag.X, 0.0)
+			assert.GreaterOrEqual(t, tag.Y, 0.0)
+			assert.LessOrEqual(t, tag.X+tag.Width, 800.0)
+			assert.LessOrEqual(t, tag.Y+tag.Height, 600.0)
+			assert.Contains(t, []int{0, 90}, tag.Rotation)
+			for _, other := range placed[:i] {
+				assert.False(t, tag.Overlaps(other), "%+v overlaps %+v", tag, other)
+			}
+		}
+	})
+
+	t.Run("ok, deterministic for seed", func(t *testing.T) {
+		first, _, err := tc.Layout(800, 600, 30, 42)
+		require.NoError(t, err)
+		second, _, err := tc.Clone().Layout(800, 600, 30, 42)
+		require.NoError(t, err)
+		other, _, err := tc.Layout(800, 600, 30, 43)
+		require.NoError(t, err)
+
+		assert.Len(t, first, 30)
+		assert.Equal(t, first, second)
+		assert.NotEqual(t, first, other)
+	})
+
+	t.Run("ok, tags which don't fit are unplaced", func(t *testing.T) {
+		placed, unplaced, err := tc.Layout(150, 100, 0, 1)
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, placed)
+		assert.NotEmpty(t, unplaced)
+		assert.Equal(t, 60, len(placed)+len(unplaced))
+		for i, stat := range unplaced {
+			assert.Equal(t, tc.Count(stat.Tag), stat.OccurrenceCount)
+			if i > 0 {
+				assert.GreaterOrEqual(t, unplaced[i-1].OccurrenceCount, stat.OccurrenceCount)
+			}
+		}
+	})
+
+	t.Run("ok, empty cloud", func(t *testing.T) {
+		placed, unplaced, err := tagcloud.New().Layout(100, 100, 10, 1)
+
+		require.NoError(t, err)
+		assert.Empty(t, placed)
+		assert.Empty(t, unplaced)
+	})
+
+	t.Run("error, invalid size", func(t *testing.T) {
+		_, _, err := tc.Layout(0, 100, 10, 1)
+
+		assert.EqualError(t, err, "invalid layout size 0x100")
+	})
+}
@@ -0,0 +1,168 @@
+package tagcloud_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"lecture02_homework/tagcloud"
+	"math/rand"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// zeroValueCalls call every method of a cloud, they must work on a zero value TagCloud
+var zeroValueCalls = map[string]func(tc *tagcloud.TagCloud){
+	"AddAlias":          func(tc *tagcloud.TagCloud) { _ = tc.AddAlias("golang", "go") },
+	"AddFromMap":        func(tc *tagcloud.TagCloud) { tc.AddFromMap(map[string]int{"go": 2}) },
+	"AddFromReader":     func(tc *tagcloud.TagCloud) { _, _ = tc.AddFromReader(strings.NewReader("go rust")) },
+	"AddFromReaders":    func(tc *tagcloud.TagCloud) { _, _ = tc.AddFromReaders([]io.Reader{strings.NewReader("go")}, 1) },
+	"AddFromRuneReader": func(tc *tagcloud.TagCloud) { _, _ = tc.AddFromRuneReader(strings.NewReader("go rust")) },
+	"AddTag":            func(tc *tagcloud.TagCloud) { tc.AddTag("go") },
+	"AddTagE":           func(tc *tagcloud.TagCloud) { _ = tc.AddTagE("go") },
+	"AddTagN":           func(tc *tagcloud.TagCloud) { tc.AddTagN("go", 3) },
+	"AddTagSet":         func(tc *tagcloud.TagCloud) { tc.AddTagSet("go", "rust") },
+	"AddTags":           func(tc *tagcloud.TagCloud) { tc.AddTags("go", "rust") },
+	"AddWeighted":       func(tc *tagcloud.TagCloud) { _ = tc.AddWeighted("go", 2.5) },
+	"All": func(tc *tagcloud.TagCloud) {
+		for range tc.All() {
+		}
+	},
+	"AppendTopN":        func(tc *tagcloud.TagCloud) { tc.AppendTopN(nil, 3) },
+	"Apply":             func(tc *tagcloud.TagCloud) { _ = tc.Apply([]tagcloud.Op{{Kind: tagcloud.OpAdd, Tag: "go", N: 2}}) },
+	"BottomN":           func(tc *tagcloud.TagCloud) { tc.BottomN(3) },
+	"Buckets":           func(tc *tagcloud.TagCloud) { _, _ = tc.Buckets(3, tagcloud.LinearScale) },
+	"ByNamespace":       func(tc *tagcloud.TagCloud) { tc.ByNamespace("lang") },
+	"CaseCollisions":    func(tc *tagcloud.TagCloud) { tc.CaseCollisions() },
+	"Clone":             func(tc *tagcloud.TagCloud) { tc.Clone().AddTag("go") },
+	"Collector":         func(tc *tagcloud.TagCloud) { tc.Collector().Collect() },
+	"Compact":           func(tc *tagcloud.TagCloud) { tc.Compact() },
+	"Contains":          func(tc *tagcloud.TagCloud) { tc.Contains("go") },
+	"Count":             func(tc *tagcloud.TagCloud) { tc.Count("go") },
+	"CountDistribution": func(tc *tagcloud.TagCloud) { tc.CountDistribution() },
+	"CountQuantiles":    func(tc *tagcloud.TagCloud) { _, _ = tc.CountQuantiles(0.5) },
+	"CoverageOf":        func(tc *tagcloud.TagCloud) { tc.CoverageOf(1) },
+	"DeleteTag":         func(tc *tagcloud.TagCloud) { tc.DeleteTag("go") },
+	"Diff":              func(tc *tagcloud.TagCloud) { tc.Diff(&tagcloud.TagCloud{}) },
+	"DropPeaks":         func(tc *tagcloud.TagCloud) { tc.DropPeaks() },
+	"Entropy":           func(tc *tagcloud.TagCloud) { tc.Entropy() },
+	"Equal":             func(tc *tagcloud.TagCloud) { tc.Equal(&tagcloud.TagCloud{}) },
+	"Expire":            func(tc *tagcloud.TagCloud) { tc.Expire(time.Now()) },
+	"ExpvarFunc":        func(tc *tagcloud.TagCloud) { tc.ExpvarFunc()() },
+	"Filter":            func(tc *tagcloud.TagCloud) { tc.Filter(func(tagcloud.TagStat) bool { return true }).AddTag("go") },
+	"Flush":             func(tc *tagcloud.TagCloud) { tc.Flush() },
+	"FoldCase":          func(tc *tagcloud.TagCloud) { tc.FoldCase().AddTag("go") },
+	"Generic":           func(tc *tagcloud.TagCloud) { tc.Generic() },
+	"Histogram":         func(tc *tagcloud.TagCloud) { _, _ = tc.Histogram([]int{1, 10}) },
+	"Layout":            func(tc *tagcloud.TagCloud) { _, _, _ = tc.Layout(400, 300, 10, 1) },
+	"Len":               func(tc *tagcloud.TagCloud) { tc.Len() },
+	"MapTags":           func(tc *tagcloud.TagCloud) { tc.MapTags(strings.ToUpper).AddTag("go") },
+	"MarshalBinary":     func(tc *tagcloud.TagCloud) { _, _ = tc.MarshalBinary() },
+	"MarshalJSON":       func(tc *tagcloud.TagCloud) { _, _ = tc.MarshalJSON() },
+	"MarshalText":       func(tc *tagcloud.TagCloud) { _, _ = tc.MarshalText() },
+	"Mean":              func(tc *tagcloud.TagCloud) { tc.Mean() },
+	"Median":            func(tc *tagcloud.TagCloud) { tc.Median() },
+	"MemoryHint":        func(tc *tagcloud.TagCloud) { tc.MemoryHint() },
+	"Merge":             func(tc *tagcloud.TagCloud) { tc.Merge(&tagcloud.TagCloud{}).AddTag("go") },
+	"MergeInto":         func(tc *tagcloud.TagCloud) { tc.MergeInto(tagcloud.New()) },
+	"MergeIntoCtx":      func(tc *tagcloud.TagCloud) { _ = tc.MergeIntoCtx(context.Background(), tagcloud.New(), 0) },
+	"Metrics":           func(tc *tagcloud.TagCloud) { tc.Metrics() },
+	"Namespaces":        func(tc *tagcloud.TagCloud) { tc.Namespaces() },
+	"NormalizeExisting": func(tc *tagcloud.TagCloud) { tc.NormalizeExisting(tagcloud.NFC) },
+	"OnThreshold":       func(tc *tagcloud.TagCloud) { tc.OnThreshold(2, func(string, int) {}) },
+	"Peak":              func(tc *tagcloud.TagCloud) { tc.Peak("go") },
+	"Percentile":        func(tc *tagcloud.TagCloud) { _, _ = tc.Percentile(50) },
+	"Prune":             func(tc *tagcloud.TagCloud) { tc.Prune(2) },
+	"PruneCtx":          func(tc *tagcloud.TagCloud) { _, _ = tc.PruneCtx(context.Background(), 2, 0) },
+	"Related":           func(tc *tagcloud.TagCloud) { tc.Related("go", 3) },
+	"RemoveTag":         func(tc *tagcloud.TagCloud) { tc.RemoveTag("go") },
+	"RenderHTML":        func(tc *tagcloud.TagCloud) { _ = tc.RenderHTML(io.Discard, tagcloud.RenderOptions{}) },
+	"RenderSVG":         func(tc *tagcloud.TagCloud) { _ = tc.RenderSVG(io.Discard, 400, 300, tagcloud.RenderOptions{}) },
+	"Reset":             func(tc *tagcloud.TagCloud) { tc.Reset() },
+	"ResolveAlias":      func(tc *tagcloud.TagCloud) { tc.ResolveAlias("golang") },
+	"Save":              func(tc *tagcloud.TagCloud) { _ = tc.Save(io.Discard) },
+	"SimilarTags":       func(tc *tagcloud.TagCloud) { tc.SimilarTags("go", 1, 3) },
+	"Snapshot":          func(tc *tagcloud.TagCloud) { tc.Snapshot().TopN(3) },
+	"SortedAll": func(tc *tagcloud.TagCloud) {
+		for range tc.SortedAll() {
+		}
+	},
+	"String":            func(tc *tagcloud.TagCloud) { _ = tc.String() },
+	"SuggestMerges":     func(tc *tagcloud.TagCloud) { tc.SuggestMerges(1) },
+	"TagsAbove":         func(tc *tagcloud.TagCloud) { tc.TagsAbove(1) },
+	"TagsBetween":       func(tc *tagcloud.TagCloud) { tc.TagsBetween(1, 3) },
+	"TagsMatching":      func(tc *tagcloud.TagCloud) { tc.TagsMatching(regexp.MustCompile("^g"), 3) },
+	"TagsWithCount":     func(tc *tagcloud.TagCloud) { tc.TagsWithCount(1) },
+	"TagsWithPrefix":    func(tc *tagcloud.TagCloud) { tc.TagsWithPrefix("g", 3) },
+	"ToMap":             func(tc *tagcloud.TagCloud) { tc.ToMap() },
+	"ToSortedSlice":     func(tc *tagcloud.TagCloud) { tc.ToSortedSlice() },
+	"TopN":              func(tc *tagcloud.TagCloud) { tc.TopN(3) },
+	"TopNByVelocity":    func(tc *tagcloud.TagCloud) { tc.TopNByVelocity(3) },
+	"TopNByWeight":      func(tc *tagcloud.TagCloud) { tc.TopNByWeight(3) },
+	"TopNFrom":          func(tc *tagcloud.TagCloud) { tc.TopNFrom(1, 3) },
+	"TopNFull":          func(tc *tagcloud.TagCloud) { tc.TopNFull(3) },
+	"TopNFunc":          func(tc *tagcloud.TagCloud) { tc.TopNFunc(3, nil) },
+	"TopNWithFrequency": func(tc *tagcloud.TagCloud) { tc.TopNWithFrequency(3) },
+	"TopNWithMin":       func(tc *tagcloud.TagCloud) { tc.TopNWithMin(3, 1) },
+	"TopPercent":        func(tc *tagcloud.TagCloud) { _, _ = tc.TopPercent(50) },
+	"Total":             func(tc *tagcloud.TagCloud) { tc.Total() },
+	"UnmarshalBinary":   func(tc *tagcloud.TagCloud) { _ = tc.UnmarshalBinary([]byte("TGCL\x01\x01\x02go\x02")) },
+	"UnmarshalJSON":     func(tc *tagcloud.TagCloud) { _ = tc.UnmarshalJSON([]byte(`[{"tag":"go","count":2}]`)) },
+	"UnmarshalText":     func(tc *tagcloud.TagCloud) { _ = tc.UnmarshalText([]byte("go\t2\n")) },
+	"Velocity":          func(tc *tagcloud.TagCloud) { tc.Velocity("go") },
+	"Weight":            func(tc *tagcloud.TagCloud) { tc.Weight("go") },
+	"WeightedSample":    func(tc *tagcloud.TagCloud) { tc.WeightedSample(2, rand.New(rand.NewSource(1))) },
+	"Without":           func(tc *tagcloud.TagCloud) { tc.Without(&tagcloud.TagCloud{}).AddTag("go") },
+	"WriteDOT":          func(tc *tagcloud.TagCloud) { _ = tc.WriteDOT(io.Discard, 1) },
+	"WriteIndexed":      func(tc *tagcloud.TagCloud) { _ = tc.WriteIndexed(tempFile(), 3) },
+	"WriteTable":        func(tc *tagcloud.TagCloud) { _ = tc.WriteTable(io.Discard, 3) },
+	"WriteTopN":         func(tc *tagcloud.TagCloud) { _ = tc.WriteTopN(io.Discard, 3, tagcloud.JSONLines) },
+}
+
+func TestZeroValue(t *testing.T) {
+	for name, call := range zeroValueCalls {
+		t.Run("ok, empty zero value, "+name, func(t *testing.T) {
+			var tc tagcloud.TagCloud
+
+			assert.NotPanics(t, func() { call(&tc) })
+		})
+
+		t.Run("ok, filled zero value, "+name, func(t *testing.T) {
+			var tc tagcloud.TagCloud
+			tc.AddTagSet("go", "rust")
+			tc.AddTagN("go", 2)
+
+			assert.NotPanics(t, func() { call(&tc) })
+			tc.AddTag("c")
+			assert.True(t, tc.Contains("c"))
+		})
+	}
+
+	t.Run("ok, embedded cloud", func(t *testing.T) {
+		var stats struct {
+			name string
+			tagcloud.TagCloud
+		}
+
+		stats.AddTags("go", "go", "rust")
+
+		assert.Equal(t, []tagcloud.TagStat{{Tag: "go", OccurrenceCount: 2}, {Tag: "rust", OccurrenceCount: 1}}, stats.TopN(10))
+		assert.Equal(t, 3, stats.Total())
+		var buf bytes.Buffer
+		assert.NoError(t, stats.Save(&buf))
+	})
+}
+
+// tempFile returns a file removed when the test binary exits
+func tempFile() *os.File {
+	file, err := os.CreateTemp("", "zero")
+	if err != nil {
+		panic(err)
+	}
+	_ = os.Remove(file.Name())
+	return file
+}
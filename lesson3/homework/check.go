@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bufio"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Hashes maps supported -hash names to their implementation
+var Hashes = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+var errChecksumMismatch = errors.New("checksum verification failed")
+
+// bsdChecksumLine matches tagged lines like "SHA256 (file.txt) = 2c26b4..."
+var bsdChecksumLine = regexp.MustCompile(`^([A-Za-z0-9]+) \((.*)\) = ([0-9A-Fa-f]+)$`)
+
+func supportedHashes() []string {
+	names := make([]string, 0, len(Hashes))
+	for name := range Hashes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// hashByDigest infers the algorithm from the length of hex digest
+func hashByDigest(digest string) (string, bool) {
+	for name, newHash := range Hashes {
+		if newHash().Size()*2 == len(digest) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+type checksumLine struct {
+	Algorithm string
+	Digest    string
+	Path      string
+}
+
+// parseChecksumLine parses "HASH  file" and "HASH *file" lines of sha256sum and tagged "SHA256 (file) = HASH" lines.
+// algorithm is taken from the tag, the override or the length of digest
+func parseChecksumLine(line string, override string) (checksumLine, error) {
+	var result checksumLine
+	if match := bsdChecksumLine.FindStringSubmatch(line); match != nil {
+		result = checksumLine{Algorithm: strings.ToLower(match[1]), Path: match[2], Digest: match[3]}
+	} else {
+		digest, path, ok := strings.Cut(line, " ")
+		if !ok || path == "" || (path[0] != ' ' && path[0] != '*') || len(path) == 1 {
+			return result, fmt.Errorf("expected \"HASH  file\" or \"ALGORITHM (file) = HASH\"")
+		}
+		result = checksumLine{Digest: digest, Path: path[1:]}
+	}
+	if _, err := hex.DecodeString(result.Digest); err != nil {
+		return result, fmt.Errorf("digest %q is not hexadecimal", result.Digest)
+	}
+	result.Digest = strings.ToLower(result.Digest)
+	if override != "" {
+		result.Algorithm = override
+	}
+	if result.Algorithm == "" {
+		algorithm, ok := hashByDigest(result.Digest)
+		if !ok {
+			return result, fmt.Errorf("can't infer algorithm of %d hex digits digest, use -hash", len(result.Digest))
+		}
+		result.Algorithm = algorithm
+	}
+	newHash, ok := Hashes[result.Algorithm]
+	if !ok {
+		return result, fmt.Errorf("unknown hash %s, supported: %s", result.Algorithm, strings.Join(supportedHashes(), ", "))
+	}
+	if newHash().Size()*2 != len(result.Digest) {
+		return result, fmt.Errorf("%s digest must have %d hex digits, got %d", result.Algorithm, newHash().Size()*2, len(result.Digest))
+	}
+	return result, nil
+}
+
+// fileDigest streams the file through the hash by blocks of blockSize bytes, zero size reads blocks of any size
+func fileDigest(path string, newHash func() hash.Hash, blockSize uint) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	var reader io.Reader = file
+	if blockSize > 0 {
+		reader = blockReader{reader: file, size: blockSize}
+	}
+	h := newHash()
+	if _, err := io.Copy(h, reader); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (o *Options) validateCheck() error {
+	if o.Hash != "" {
+		if _, ok := Hashes[o.Hash]; !ok {
+			return fmt.Errorf("unknown -hash %s, available: %s", o.Hash, strings.Join(supportedHashes(), ", "))
+		}
+	}
+	if o.Check != "" && (o.From != "" || o.To != "") {
+		return fmt.Errorf("-check can't be used with -from and -to")
+	}
+	return nil
+}
+
+// newOutputHash returns the hash of copied output, nil unless -hash is used without -check
+func newOutputHash(opts *Options) hash.Hash {
+	if opts.Hash == "" || opts.Check != "" {
+		return nil
+	}
+	return Hashes[opts.Hash]()
+}
+
+// writeChecksumLine writes "HASH  file" line of the output which -check can verify, stdout is named "-" like by sha256sum
+func writeChecksumLine(w io.Writer, opts *Options, digest string) error {
+	name := opts.To
+	if name == "" {
+		name = "-"
+	}
+	_, err := fmt.Fprintf(w, "%s  %s\n", digest, name)
+	return err
+}
+
+// runCheck verifies files listed in the checksum file and reports OK or FAILED per line to stdout like sha256sum -c.
+// improperly formatted lines are reported to stderr. the result is errChecksumMismatch if any line fails
+func runCheck(opts *Options, stdout io.Writer, stderr io.Writer) error {
+	sums, err := os.Open(opts.Check)
+	if err != nil {
+		return err
+	}
+	defer sums.Close()
+	var failed, unreadable, malformed int
+	scanner := bufio.NewScanner(sums)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		parsed, err := parseChecksumLine(line, opts.Hash)
+		if err != nil {
+			malformed++
+			_, _ = fmt.Fprintf(stderr, "%s: line %d: improperly formatted checksum line: %v\n", opts.Check, lineNumber, err)
+			continue
+		}
+		digest, err := fileDigest(parsed.Path, Hashes[parsed.Algorithm], opts.BlockSize)
+		switch {
+		case err != nil:
+			unreadable++
+			_, _ = fmt.Fprintf(stdout, "%s: FAILED open or read\n", parsed.Path)
+		case digest != parsed.Digest:
+			failed++
+			_, _ = fmt.Fprintf(stdout, "%s: FAILED\n", parsed.Path)
+		default:
+			_, _ = fmt.Fprintf(stdout, "%s: OK\n", parsed.Path)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error while reading %s: %v", opts.Check, err)
+	}
+	if failed+unreadable+malformed > 0 {
+		return fmt.Errorf("%w: %d mismatched, %d unreadable, %d improperly formatted lines",
+			errChecksumMismatch, failed, unreadable, malformed)
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	helloSHA256 = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	helloMD5    = "5d41402abc4b2a76b9719d911017c592"
+)
+
+func TestParseChecksumLine(t *testing.T) {
+	t.Run("ok, formats", func(t *testing.T) {
+		for line, want := range map[string]checksumLine{
+			helloSHA256 + "  a b.txt":                        {Algorithm: "sha256", Digest: helloSHA256, Path: "a b.txt"},
+			strings.ToUpper(helloMD5) + " *hello.bin":        {Algorithm: "md5", Digest: helloMD5, Path: "hello.bin"},
+			"SHA256 (dir/hello (1).txt) = " + helloSHA256:    {Algorithm: "sha256", Digest: helloSHA256, Path: "dir/hello (1).txt"},
+			"MD5 (hello.txt) = " + strings.ToUpper(helloMD5): {Algorithm: "md5", Digest: helloMD5, Path: "hello.txt"},
+		} {
+			parsed, err := parseChecksumLine(line, "")
+
+			require.NoError(t, err, line)
+			assert.Equal(t, want, parsed, line)
+		}
+	})
+
+	t.Run("ok, hash override", func(t *testing.T) {
+		parsed, err := parseChecksumLine(helloSHA256+"  hello.txt", "sha256")
+
+		require.NoError(t, err)
+		assert.Equal(t, "sha256", parsed.Algorithm)
+	})
+
+	t.Run("error, malformed lines", func(t *testing.T) {
+		for _, line := range []string{
+			helloSHA256,
+			helloSHA256 + " hello.txt",
+			helloSHA256 + "  ",
+			"xyz  hello.txt",
+			"abcd  hello.txt",
+			"CRC32 (hello.txt) = abcd0123",
+			"MD5 (hello.txt) = " + helloSHA256,
+		} {
+			_, err := parseChecksumLine(line, "")
+
+			assert.Error(t, err, line)
+		}
+		_, err := parseChecksumLine(helloSHA256+"  hello.txt", "md5")
+		assert.EqualError(t, err, "md5 digest must have 32 hex digits, got 64")
+	})
+}
+
+func TestRunCheck(t *testing.T) {
+	dir := t.TempDir()
+	hello := filepath.Join(dir, "hello.txt")
+	corrupted := filepath.Join(dir, "corrupted.txt")
+	missing := filepath.Join(dir, "missing.txt")
+	require.NoError(t, os.WriteFile(hello, []byte("hello"), 0666))
+	require.NoError(t, os.WriteFile(corrupted, []byte("hellO"), 0666))
+	writeSums := func(t *testing.T, lines ...string) string {
+		path := filepath.Join(t.TempDir(), "sums.txt")
+		require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0666))
+		return path
+	}
+	run := func(opts *Options) (string, string, error) {
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		err := runCheck(opts, stdout, stderr)
+		return stdout.String(), stderr.String(), err
+	}
+
+	t.Run("ok, passing files", func(t *testing.T) {
+		sums := writeSums(t, helloSHA256+"  "+hello, "", "MD5 ("+hello+") = "+helloMD5)
+
+		for _, blockSize := range []uint{0, 1, 3, 1000} {
+			stdout, stderr, err := run(&Options{Check: sums, BlockSize: blockSize})
+
+			assert.NoError(t, err)
+			assert.Equal(t, hello+": OK\n"+hello+": OK\n", stdout)
+			assert.Empty(t, stderr)
+		}
+	})
+
+	t.Run("error, corrupted file", func(t *testing.T) {
+		sums := writeSums(t, helloSHA256+"  "+hello, helloSHA256+"  "+corrupted)
+
+		stdout, _, err := run(&Options{Check: sums, BlockSize: 10})
+
+		assert.True(t, errors.Is(err, errChecksumMismatch), err)
+		assert.EqualError(t, err, "checksum verification failed: 1 mismatched, 0 unreadable, 0 improperly formatted lines")
+		assert.Equal(t, hello+": OK\n"+corrupted+": FAILED\n", stdout)
+	})
+
+	t.Run("error, missing file", func(t *testing.T) {
+		sums := writeSums(t, helloMD5+"  "+missing, helloMD5+"  "+hello)
+
+		stdout, _, err := run(&Options{Check: sums, BlockSize: 10})
+
+		assert.EqualError(t, err, "checksum verification failed: 0 mismatched, 1 unreadable, 0 improperly formatted lines")
+		assert.Equal(t, missing+": FAILED open or read\n"+hello+": OK\n", stdout)
+	})
+
+	t.Run("error, malformed lines", func(t *testing.T) {
+		sums := writeSums(t, "not a checksum", helloSHA256+"  "+hello, helloMD5+" "+hello)
+
+		stdout, stderr, err := run(&Options{Check: sums, BlockSize: 10})
+
+		assert.EqualError(t, err, "checksum verification failed: 0 mismatched, 0 unreadable, 2 improperly formatted lines")
+		assert.Equal(t, hello+": OK\n", stdout)
+		assert.Equal(t, sums+`: line 1: improperly formatted checksum line: expected "HASH  file" or "ALGORITHM (file) = HASH"
+`+sums+`: line 3: improperly formatted checksum line: expected "HASH  file" or "ALGORITHM (file) = HASH"
+`, stderr)
+	})
+
+	t.Run("error, hash override mismatches digests", func(t *testing.T) {
+		sums := writeSums(t, helloMD5+"  "+hello)
+
+		_, stderr, err := run(&Options{Check: sums, Hash: "sha1", BlockSize: 10})
+
+		assert.True(t, errors.Is(err, errChecksumMismatch), err)
+		assert.Contains(t, stderr, "sha1 digest must have 40 hex digits, got 32")
+	})
+
+	t.Run("error, missing checksum file", func(t *testing.T) {
+		_, _, err := run(&Options{Check: missing, BlockSize: 10})
+
+		assert.Error(t, err)
+		assert.False(t, errors.Is(err, errChecksumMismatch))
+	})
+}
+
+func TestCheckValidation(t *testing.T) {
+	assert.NoError(t, (&Options{Hash: "sha256"}).Validate())
+	assert.Error(t, (&Options{Hash: "crc32"}).Validate())
+	assert.Error(t, (&Options{Check: "sums.txt", Hash: "crc32"}).Validate())
+	assert.Error(t, (&Options{Check: "sums.txt", To: "out.txt"}).Validate())
+	assert.NoError(t, (&Options{Check: "sums.txt", Hash: "sha512"}).Validate())
+}
+
+func TestCheckIntegration(t *testing.T) {
+	binPath := composeBinaryPath()
+	cmd := exec.Command("go", "build", "-o", binPath, "./")
+	assert.NoError(t, cmd.Run())
+	defer func() {
+		assert.NoError(t, os.Remove(binPath))
+	}()
+	dir := t.TempDir()
+	hello := filepath.Join(dir, "hello.txt")
+	require.NoError(t, os.WriteFile(hello, []byte("hello"), 0666))
+
+	t.Run("ok with passing file", func(t *testing.T) {
+		sums := filepath.Join(dir, "ok.sha256")
+		require.NoError(t, os.WriteFile(sums, []byte(helloSHA256+"  "+hello+"\n"), 0666))
+		cmd = exec.Command(binPath, "-check", sums)
+		stdout := &strings.Builder{}
+		cmd.Stdout = stdout
+		stderr := &strings.Builder{}
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+
+		assert.NoError(t, err)
+		assert.Zero(t, stderr.Len(), stderr.String())
+		assert.Equal(t, hello+": OK\n", stdout.String())
+	})
+
+	t.Run("ok, emitted checksum is verified", func(t *testing.T) {
+		out := filepath.Join(dir, "out.txt")
+		cmd = exec.Command(binPath, "-from", hello, "-to", out, "-conv", "upper_case", "-hash", "md5")
+		stderr := &strings.Builder{}
+		cmd.Stderr = stderr
+		require.NoError(t, cmd.Run())
+		sums := filepath.Join(dir, "out.md5")
+		require.NoError(t, os.WriteFile(sums, []byte(stderr.String()), 0666))
+		cmd = exec.Command(binPath, "-check", sums)
+		stdout := &strings.Builder{}
+		cmd.Stdout = stdout
+
+		err := cmd.Run()
+
+		assert.NoError(t, err)
+		assert.Regexp(t, "^[0-9a-f]{32}  "+regexp.QuoteMeta(out)+"\n$", stderr.String())
+		assert.Equal(t, out+": OK\n", stdout.String())
+	})
+
+	t.Run("error with missing file", func(t *testing.T) {
+		sums := filepath.Join(dir, "missing.sha256")
+		require.NoError(t, os.WriteFile(sums, []byte(helloSHA256+"  "+hello+"\n"+helloSHA256+"  "+hello+".bak\n"), 0666))
+		cmd = exec.Command(binPath, "-check", sums)
+		stdout := &strings.Builder{}
+		cmd.Stdout = stdout
+		stderr := &strings.Builder{}
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+
+		assert.Error(t, err)
+		assert.Contains(t, stderr.String(), "1 unreadable")
+		assert.Equal(t, hello+": OK\n"+hello+".bak: FAILED open or read\n", stdout.String())
+	})
+}
+
+func TestOutputDigest(t *testing.T) {
+	dir := t.TempDir()
+	opts := &Options{From: filepath.Join(dir, "in.txt"), To: filepath.Join(dir, "out.txt"), BlockSize: 2, Conv: "upper_case", Hash: "sha256"}
+	require.NoError(t, os.WriteFile(opts.From, []byte("hello"), 0666))
+
+	stats, err := initFilesAndProcess(opts)
+
+	require.NoError(t, err)
+	sum := sha256.Sum256([]byte("HELLO"))
+	assert.Equal(t, hex.EncodeToString(sum[:]), stats.Digest)
+	out := &strings.Builder{}
+	require.NoError(t, writeChecksumLine(out, &Options{}, helloSHA256))
+	assert.Equal(t, helloSHA256+"  -\n", out.String())
+}
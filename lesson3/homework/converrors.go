@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+	"unicode/utf8"
+
+	"golang.org/x/text/transform"
+)
+
+type ConvErrorPolicy string
+
+const (
+	// ConvErrorAbort aborts the copy on the first invalid unit of a conversion
+	ConvErrorAbort ConvErrorPolicy = "abort"
+	// ConvErrorSkip drops invalid units
+	ConvErrorSkip ConvErrorPolicy = "skip"
+	// ConvErrorReplace substitutes invalid units with U+FFFD
+	ConvErrorReplace ConvErrorPolicy = "replace"
+)
+
+// jsonReplacement is written instead of invalid escape sequences of JSON strings
+const jsonReplacement = "\uFFFD"
+
+func (o *Options) validateConvErrors() error {
+	switch o.OnConvError {
+	case "", ConvErrorAbort, ConvErrorSkip, ConvErrorReplace:
+		return nil
+	}
+	return fmt.Errorf("unknown -on-conv-error policy %s, available: %s, %s, %s", o.OnConvError, ConvErrorAbort, ConvErrorSkip, ConvErrorReplace)
+}
+
+// convErrors counts conversion errors tolerated by -on-conv-error, it's shared by conversion stages
+type convErrors struct {
+	policy ConvErrorPolicy
+	// max is a count of tolerated errors, zero is unlimited
+	max   int64
+	count atomic.Int64
+}
+
+// newConvErrors returns nil for the abort policy
+func newConvErrors(opts *Options) *convErrors {
+	if opts.OnConvError == "" || opts.OnConvError == ConvErrorAbort {
+		return nil
+	}
+	return &convErrors{policy: opts.OnConvError, max: int64(opts.MaxConvErrors)}
+}
+
+// Add counts an error and fails once more than max errors are counted
+func (e *convErrors) Add(unit []byte) error {
+	count := e.count.Add(1)
+	if e.max > 0 && count > e.max {
+		return fmt.Errorf("more than %d conversion errors, last invalid unit %q", e.max, unit)
+	}
+	return nil
+}
+
+func (e *convErrors) Count() int {
+	if e == nil {
+		return 0
+	}
+	return int(e.count.Load())
+}
+
+// jsonEscapeFixer skips or replaces invalid escape sequences of JSON strings, the rest of input is copied as is
+type jsonEscapeFixer struct {
+	errors   *convErrors
+	inString bool
+}
+
+func (f *jsonEscapeFixer) Reset() {
+	f.inString = false
+}
+
+func (f *jsonEscapeFixer) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	for nSrc < len(src) {
+		c := src[nSrc]
+		size, valid := 1, true
+		inString := f.inString
+		switch {
+		case !inString:
+			inString = c == '"'
+		case c == '"':
+			inString = false
+		case c == '\\':
+			size, valid = jsonEscapeSize(src[nSrc:])
+			if size == 0 {
+				if !atEOF {
+					return nDst, nSrc, transform.ErrShortSrc
+				}
+				// the decoder reports unexpected end of input
+				size, valid = len(src)-nSrc, true
+			}
+		}
+		out := src[nSrc : nSrc+size]
+		if !valid {
+			out = nil
+			if f.errors.policy == ConvErrorReplace {
+				out = []byte(jsonReplacement)
+			}
+		}
+		if nDst+len(out) > len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		if !valid {
+			if err := f.errors.Add(src[nSrc : nSrc+size]); err != nil {
+				return nDst, nSrc, err
+			}
+		}
+		nDst += copy(dst[nDst:], out)
+		nSrc += size
+		f.inString = inString
+	}
+	return nDst, nSrc, nil
+}
+
+// fixJSONEscapes wraps reader to skip or replace invalid escapes, it's a no-op for the abort policy
+func fixJSONEscapes(reader io.Reader, convErrs *convErrors) io.Reader {
+	if convErrs == nil {
+		return reader
+	}
+	return transform.NewReader(reader, &jsonEscapeFixer{errors: convErrs})
+}
+
+// jsonEscapeSize returns the length of escape sequence starting with backslash and whether it's valid.
+// invalid \u sequence ends before the first non-hex digit, zero size means the sequence is incomplete
+func jsonEscapeSize(escape []byte) (int, bool) {
+	if len(escape) < 2 {
+		return 0, false
+	}
+	switch escape[1] {
+	case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+		return 2, true
+	case 'u':
+		for i := 2; i < 6; i++ {
+			if i >= len(escape) {
+				return 0, false
+			}
+			if !isHexDigit(escape[i]) {
+				return i, false
+			}
+		}
+		return 6, true
+	}
+	// the invalid escape takes the whole rune after backslash, so no continuation bytes are left
+	if !utf8.FullRune(escape[1:]) {
+		return 0, false
+	}
+	_, size := utf8.DecodeRune(escape[1:])
+	return 1 + size, false
+}
+
+func isHexDigit(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
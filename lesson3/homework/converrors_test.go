@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// corruptedJSON has invalid escapes \q, \u12G4 and \z in strings along with valid ones
+const corruptedJSON = `{"a\q": "x\u12G4y", "ok": "\u0041\n\\q"}
+["\z"]
+`
+
+func TestOnConvError(t *testing.T) {
+	run := func(input string, opts *Options) (string, Stats, error) {
+		out := &bytes.Buffer{}
+		stats, err := transcodeAndProcess(strings.NewReader(input), out, opts)
+		return out.String(), stats, err
+	}
+
+	for _, blockSize := range []uint{1, 3, 1000} {
+		t.Run("error, abort by default", func(t *testing.T) {
+			_, stats, err := run(corruptedJSON, &Options{Conv: "json_minify", BlockSize: blockSize})
+
+			assert.ErrorContains(t, err, "invalid JSON at offset")
+			assert.Zero(t, stats.ConvErrors)
+		})
+
+		t.Run("ok, skip", func(t *testing.T) {
+			out, stats, err := run(corruptedJSON, &Options{Conv: "json_minify", BlockSize: blockSize, OnConvError: ConvErrorSkip})
+
+			assert.NoError(t, err)
+			assert.Equal(t, `{"a":"xG4y","ok":"\u0041\n\\q"}`+"\n"+`[""]`+"\n", out)
+			assert.Equal(t, 3, stats.ConvErrors)
+		})
+
+		t.Run("ok, replace", func(t *testing.T) {
+			out, stats, err := run(corruptedJSON, &Options{Conv: "json_pretty", BlockSize: blockSize, OnConvError: ConvErrorReplace})
+
+			assert.NoError(t, err)
+			assert.Equal(t, "{\n  \"a�\": \"x�G4y\",\n  \"ok\": \"\\u0041\\n\\\\q\"\n}\n[\n  \"�\"\n]\n", out)
+			assert.Equal(t, 3, stats.ConvErrors)
+		})
+
+		t.Run("ok, escaped multibyte rune is a single error", func(t *testing.T) {
+			input := `["a\жb", "\€"]`
+			out, stats, err := run(input, &Options{Conv: "json_minify", BlockSize: blockSize, OnConvError: ConvErrorReplace})
+
+			assert.NoError(t, err)
+			assert.Equal(t, "[\"a\uFFFDb\",\"\uFFFD\"]\n", out)
+			assert.Equal(t, 2, stats.ConvErrors)
+
+			out, _, err = run(input, &Options{Conv: "json_minify", BlockSize: blockSize, OnConvError: ConvErrorSkip})
+
+			assert.NoError(t, err)
+			assert.Equal(t, `["ab",""]`+"\n", out)
+		})
+	}
+
+	t.Run("ok, cap is not reached", func(t *testing.T) {
+		_, stats, err := run(corruptedJSON, &Options{Conv: "json_minify", BlockSize: 4, OnConvError: ConvErrorSkip, MaxConvErrors: 3})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, stats.ConvErrors)
+	})
+
+	t.Run("error, cap triggers", func(t *testing.T) {
+		out, stats, err := run(corruptedJSON, &Options{Conv: "json_minify", BlockSize: 4, OnConvError: ConvErrorReplace, MaxConvErrors: 2})
+
+		assert.EqualError(t, err, `error while reading: more than 2 conversion errors, last invalid unit "\\z"`)
+		assert.Equal(t, "{\"a�\":\"x�G4y\",\"ok\":\"\\u0041\\n\\\\q\"}\n", out)
+		assert.Equal(t, 3, stats.ConvErrors)
+	})
+
+	t.Run("error, truncated escape is left to the decoder", func(t *testing.T) {
+		_, _, err := run(`["\u00`, &Options{Conv: "json_minify", BlockSize: 4, OnConvError: ConvErrorSkip})
+
+		assert.EqualError(t, err, "error while reading: unexpected end of JSON input at offset 6")
+	})
+
+	t.Run("error, unknown policy", func(t *testing.T) {
+		assert.Error(t, (&Options{OnConvError: "ignore"}).Validate())
+	})
+}
+
+func TestOnConvErrorIntegration(t *testing.T) {
+	binPath := composeBinaryPath()
+	cmd := exec.Command("go", "build", "-o", binPath, "./")
+	assert.NoError(t, cmd.Run())
+	defer func() {
+		assert.NoError(t, os.Remove(binPath))
+	}()
+
+	t.Run("ok with skip and summary", func(t *testing.T) {
+		cmd = exec.Command(binPath, "-conv", "json_minify", "-on-conv-error", "skip")
+		cmd.Stdin = strings.NewReader(corruptedJSON)
+		stdout := &strings.Builder{}
+		cmd.Stdout = stdout
+		stderr := &strings.Builder{}
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "conversion errors: 3\n", stderr.String())
+		assert.Equal(t, `{"a":"xG4y","ok":"\u0041\n\\q"}`+"\n"+`[""]`+"\n", stdout.String())
+	})
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type CSVMissing string
+
+const (
+	// CSVMissingError aborts the copy on a row without requested column
+	CSVMissingError CSVMissing = "error"
+	// CSVMissingEmpty writes an empty field instead of absent column
+	CSVMissingEmpty CSVMissing = "empty"
+)
+
+// blockReader never reads more than size bytes at once
+type blockReader struct {
+	reader io.Reader
+	size   uint
+}
+
+func (b blockReader) Read(p []byte) (int, error) {
+	if uint(len(p)) > b.size {
+		p = p[:b.size]
+	}
+	return b.reader.Read(p)
+}
+
+// blockWriter never writes more than size bytes at once
+type blockWriter struct {
+	writer io.Writer
+	size   uint
+}
+
+func (b blockWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		chunk := p
+		if uint(len(chunk)) > b.size {
+			chunk = chunk[:b.size]
+		}
+		n, err := b.writer.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+func (o *Options) csvColumns() []string {
+	return strings.Split(o.CSVColumns, ",")
+}
+
+func (o *Options) csvDelimiter() rune {
+	if o.CSVDelimiter == "" {
+		return ','
+	}
+	r, _ := utf8.DecodeRuneInString(o.CSVDelimiter)
+	return r
+}
+
+func (o *Options) validateCSV() error {
+	if o.CSVColumns == "" {
+		return nil
+	}
+	for _, column := range o.csvColumns() {
+		if column == "" {
+			return fmt.Errorf("empty column in -csv-columns")
+		}
+		if o.CSVHeader {
+			continue
+		}
+		if idx, err := strconv.Atoi(column); err != nil || idx < 0 {
+			return fmt.Errorf("invalid column index %s in -csv-columns, use -csv-header to select columns by name", column)
+		}
+	}
+	if o.CSVDelimiter != "" && utf8.RuneCountInString(o.CSVDelimiter) != 1 {
+		return fmt.Errorf("-csv-delimiter must be a single character, got %q", o.CSVDelimiter)
+	}
+	switch o.csvDelimiter() {
+	case '"', '\r', '\n', utf8.RuneError:
+		return fmt.Errorf("invalid -csv-delimiter %q", o.CSVDelimiter)
+	}
+	if o.CSVMissing != "" && o.CSVMissing != CSVMissingError && o.CSVMissing != CSVMissingEmpty {
+		return fmt.Errorf("unknown -csv-missing policy %s, available: %s, %s", o.CSVMissing, CSVMissingError, CSVMissingEmpty)
+	}
+	conv, err := o.ParseConv()
+	if err != nil {
+		return err
+	}
+	for _, c := range conv {
+		if c != UpperCase && c != LowerCase {
+			return fmt.Errorf("-csv-columns supports only upper_case and lower_case conversions")
+		}
+	}
+	if len(o.Redact) > 0 {
+		return fmt.Errorf("-redact can't be used with -csv-columns")
+	}
+	return nil
+}
+
+// headerIndexes resolves requested column names using the header row
+func headerIndexes(header []string, names []string) ([]int, error) {
+	positions := make(map[string]int, len(header))
+	for idx, name := range header {
+		if _, ok := positions[name]; !ok {
+			positions[name] = idx
+		}
+	}
+	indexes := make([]int, 0, len(names))
+	for _, name := range names {
+		idx, ok := positions[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown csv column %s", name)
+		}
+		indexes = append(indexes, idx)
+	}
+	return indexes, nil
+}
+
+// processCSV copies requested columns of csv input in the requested order.
+// only case conversions are supported, they are applied to every field
+func processCSV(reader io.Reader, writer io.Writer, opts *Options) (Stats, error) {
+	var stats Stats
+	parsedConv, err := opts.ParseConv()
+	if err != nil {
+		return stats, err
+	}
+	convert := func(field string) string {
+		for _, conv := range parsedConv {
+			switch conv {
+			case UpperCase:
+				field = strings.ToUpper(field)
+			case LowerCase:
+				field = strings.ToLower(field)
+			}
+		}
+		return field
+	}
+	if opts.Limit > 0 {
+		reader = io.LimitReader(reader, int64(opts.Limit))
+	}
+	csvReader := csv.NewReader(blockReader{reader: reader, size: opts.BlockSize})
+	csvReader.Comma = opts.csvDelimiter()
+	csvReader.FieldsPerRecord = -1
+	csvReader.ReuseRecord = true
+	csvWriter := csv.NewWriter(blockWriter{writer: writer, size: opts.BlockSize})
+	csvWriter.Comma = csvReader.Comma
+
+	names := opts.csvColumns()
+	var indexes []int
+	if !opts.CSVHeader {
+		for _, name := range names {
+			idx, _ := strconv.Atoi(name)
+			indexes = append(indexes, idx)
+		}
+	}
+	fields := make([]string, len(names))
+	for row := 1; ; row++ {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return stats, fmt.Errorf("error while reading csv: %v", err)
+		}
+		if indexes == nil {
+			indexes, err = headerIndexes(record, names)
+			if err != nil {
+				return stats, err
+			}
+		}
+		for i, idx := range indexes {
+			if idx >= len(record) {
+				if opts.CSVMissing != CSVMissingEmpty {
+					return stats, fmt.Errorf("csv row %d has %d columns, column %s requested", row, len(record), names[i])
+				}
+				fields[i] = ""
+				continue
+			}
+			fields[i] = convert(record[idx])
+		}
+		err = csvWriter.Write(fields)
+		if err != nil {
+			return stats, err
+		}
+	}
+	csvWriter.Flush()
+	return stats, csvWriter.Error()
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const csvInput = `id,name,comment,city
+1,Alice,"likes commas, really",Berlin
+2,Bob,"multi
+line ""quoted"" comment",Москва
+3,Carol,,Paris
+`
+
+func TestProcessCSV(t *testing.T) {
+	run := func(input string, opts *Options) (string, error) {
+		if opts.BlockSize == 0 {
+			opts.BlockSize = 1000
+		}
+		out := &bytes.Buffer{}
+		_, err := processCSV(strings.NewReader(input), out, opts)
+		return out.String(), err
+	}
+
+	t.Run("ok, select and reorder columns by index", func(t *testing.T) {
+		out, err := run(csvInput, &Options{CSVColumns: "3,2,0", BlockSize: 1})
+
+		assert.NoError(t, err)
+		assert.Equal(t, `city,comment,id
+Berlin,"likes commas, really",1
+Москва,"multi
+line ""quoted"" comment",2
+Paris,,3
+`, out)
+	})
+
+	t.Run("ok, select columns by header name with case conversion", func(t *testing.T) {
+		out, err := run(csvInput, &Options{CSVColumns: "city,name", CSVHeader: true, Conv: "upper_case", BlockSize: 5})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "CITY,NAME\nBERLIN,ALICE\nМОСКВА,BOB\nPARIS,CAROL\n", out)
+	})
+
+	t.Run("ok, column may be repeated", func(t *testing.T) {
+		out, err := run("a,b\n", &Options{CSVColumns: "1,1,0"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "b,b,a\n", out)
+	})
+
+	t.Run("ok, custom delimiter", func(t *testing.T) {
+		out, err := run("a;b;c\n\"x;y\";z;w\n", &Options{CSVColumns: "2,0", CSVDelimiter: ";"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "c;a\nw;\"x;y\"\n", out)
+	})
+
+	t.Run("ok, missing columns are empty", func(t *testing.T) {
+		out, err := run("a,b,c\nd\n", &Options{CSVColumns: "0,2", CSVMissing: CSVMissingEmpty})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "a,c\nd,\n", out)
+	})
+
+	t.Run("error, missing columns", func(t *testing.T) {
+		_, err := run("a,b,c\nd\n", &Options{CSVColumns: "0,2", CSVMissing: CSVMissingError})
+
+		assert.ErrorContains(t, err, "row 2")
+	})
+
+	t.Run("error, unknown header name", func(t *testing.T) {
+		_, err := run(csvInput, &Options{CSVColumns: "country", CSVHeader: true})
+
+		assert.ErrorContains(t, err, "country")
+	})
+
+	t.Run("error, malformed csv", func(t *testing.T) {
+		_, err := run("a,\"b\n", &Options{CSVColumns: "0"})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestCSVValidation(t *testing.T) {
+	for _, opts := range []*Options{
+		{CSVColumns: "name"},
+		{CSVColumns: "0,,1"},
+		{CSVColumns: "-1"},
+		{CSVColumns: "0", CSVDelimiter: ";;"},
+		{CSVColumns: "0", CSVDelimiter: "\""},
+		{CSVColumns: "0", CSVMissing: "skip"},
+		{CSVColumns: "0", Conv: "trim_spaces"},
+		{CSVColumns: "0", Redact: stringsFlag{"a"}},
+	} {
+		assert.Error(t, opts.Validate(), "%+v", opts)
+	}
+	assert.NoError(t, (&Options{CSVColumns: "name", CSVHeader: true, Conv: "lower_case"}).Validate())
+}
+
+func TestCSVIntegration(t *testing.T) {
+	binPath := composeBinaryPath()
+	cmd := exec.Command("go", "build", "-o", binPath, "./")
+	assert.NoError(t, cmd.Run())
+	defer func() {
+		assert.NoError(t, os.Remove(binPath))
+	}()
+
+	t.Run("ok with csv columns and limit", func(t *testing.T) {
+		cmd = exec.Command(binPath, "-csv-columns=1,0", "-limit", "59", "-block-size", "3")
+		cmd.Stdin = strings.NewReader(csvInput)
+		stdout := &strings.Builder{}
+		cmd.Stdout = stdout
+		stderr := &strings.Builder{}
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+
+		assert.NoError(t, err)
+		assert.Zero(t, stderr.Len(), stderr.String())
+		assert.Equal(t, "name,id\nAlice,1\n", stdout.String())
+	})
+}
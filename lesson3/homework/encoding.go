@@ -0,0 +1,212 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+const DefaultEncoding = "utf-8"
+
+const byteOrderMark = '\uFEFF'
+
+// Encodings maps supported -input-encoding and -output-encoding names to their implementation.
+// utf-8 is the native encoding of conversions and is copied as is.
+// utf-16 input detects endianness from the BOM and falls back to the endianness of the name
+var Encodings = map[string]encoding.Encoding{
+	DefaultEncoding: nil,
+	"utf-16":        unicode.UTF16(unicode.LittleEndian, unicode.UseBOM),
+	"utf-16le":      unicode.UTF16(unicode.LittleEndian, unicode.UseBOM),
+	"utf-16be":      unicode.UTF16(unicode.BigEndian, unicode.UseBOM),
+	"windows-1250":  charmap.Windows1250,
+	"windows-1251":  charmap.Windows1251,
+	"windows-1252":  charmap.Windows1252,
+	"iso-8859-1":    charmap.ISO8859_1,
+	"iso-8859-2":    charmap.ISO8859_2,
+	"iso-8859-5":    charmap.ISO8859_5,
+	"iso-8859-15":   charmap.ISO8859_15,
+	"koi8-r":        charmap.KOI8R,
+	"koi8-u":        charmap.KOI8U,
+	"ibm866":        charmap.CodePage866,
+	"macintosh":     charmap.Macintosh,
+}
+
+// utf16Endianness is used to write utf-16 output with or without BOM
+var utf16Endianness = map[string]unicode.Endianness{
+	"utf-16":   unicode.LittleEndian,
+	"utf-16le": unicode.LittleEndian,
+	"utf-16be": unicode.BigEndian,
+}
+
+type EncodingErrors string
+
+const (
+	// EncodingErrorsReplace substitutes undecodable bytes with U+FFFD and unencodable runes with the encoding replacement byte
+	EncodingErrorsReplace EncodingErrors = "replace"
+	// EncodingErrorsStrict aborts the copy on the first undecodable byte or unencodable rune
+	EncodingErrorsStrict EncodingErrors = "strict"
+)
+
+var errUndecodable = errors.New("input contains bytes undecodable in the input encoding")
+
+func supportedEncodings() []string {
+	names := make([]string, 0, len(Encodings))
+	for name := range Encodings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func lookupEncoding(name string) (encoding.Encoding, error) {
+	if name == "" {
+		name = DefaultEncoding
+	}
+	enc, ok := Encodings[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown encoding %s, supported: %s", name, strings.Join(supportedEncodings(), ", "))
+	}
+	return enc, nil
+}
+
+// lookupOutputEncoding is like lookupEncoding, but utf-16 output starts with BOM only if bom is set
+func lookupOutputEncoding(name string, bom bool) (encoding.Encoding, error) {
+	endianness, ok := utf16Endianness[strings.ToLower(name)]
+	if !ok {
+		return lookupEncoding(name)
+	}
+	policy := unicode.IgnoreBOM
+	if bom {
+		policy = unicode.UseBOM
+	}
+	return unicode.UTF16(endianness, policy), nil
+}
+
+func isUTF16(name string) bool {
+	_, ok := utf16Endianness[strings.ToLower(name)]
+	return ok
+}
+
+// strictTransformer fails on replacement characters produced by a decoder from undecodable bytes
+type strictTransformer struct {
+	transform.NopResetter
+}
+
+func (strictTransformer) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	for nSrc < len(src) {
+		if !atEOF && !utf8.FullRune(src[nSrc:]) {
+			return nDst, nSrc, transform.ErrShortSrc
+		}
+		r, size := utf8.DecodeRune(src[nSrc:])
+		if r == utf8.RuneError {
+			return nDst, nSrc, errUndecodable
+		}
+		if nDst+size > len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		nDst += copy(dst[nDst:], src[nSrc:nSrc+size])
+		nSrc += size
+	}
+	return nDst, nSrc, nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// decodeInput wraps reader to produce utf-8 from the input encoding
+func decodeInput(reader io.Reader, enc encoding.Encoding, policy EncodingErrors) io.Reader {
+	if enc == nil {
+		return reader
+	}
+	var decoder transform.Transformer = enc.NewDecoder()
+	if policy == EncodingErrorsStrict {
+		decoder = transform.Chain(decoder, strictTransformer{})
+	}
+	return transform.NewReader(reader, decoder)
+}
+
+// encodeOutput wraps writer to convert utf-8 into the output encoding.
+// returned writer must be closed to flush buffered data
+func encodeOutput(writer io.Writer, enc encoding.Encoding, policy EncodingErrors) io.WriteCloser {
+	if enc == nil {
+		return nopWriteCloser{writer}
+	}
+	encoder := enc.NewEncoder()
+	if policy != EncodingErrorsStrict {
+		encoder = encoding.ReplaceUnsupported(encoder)
+	}
+	return transform.NewWriter(writer, encoder)
+}
+
+// transcodeAndProcess runs process over utf-8 data decoded from input and encoded back to output.
+// -offset and -limit always count bytes of the raw input
+func transcodeAndProcess(reader io.Reader, writer io.Writer, opts *Options) (Stats, error) {
+	inputEncoding, err := lookupEncoding(opts.InputEncoding)
+	if err != nil {
+		return Stats{}, err
+	}
+	outputEncoding, err := lookupOutputEncoding(opts.OutputEncoding, opts.BOM)
+	if err != nil {
+		return Stats{}, err
+	}
+	conv, err := opts.ParseConv()
+	if err != nil {
+		return Stats{}, err
+	}
+	convErrs := newConvErrors(opts)
+	// stageErrs counts errors of conversions run by process
+	stageErrs := convErrs
+	var thenConvErrs *convErrors
+	processOpts := *opts
+	var firstPass func(io.Reader, io.Writer) error
+	if opts.ThenConv != "" {
+		// -conv runs in background and -then-conv runs over its output
+		firstPass = firstPassStage(opts, convErrs)
+		if conv, err = opts.ParseThenConv(); err != nil {
+			return Stats{}, err
+		}
+		thenConvErrs = newConvErrors(opts)
+		stageErrs = thenConvErrs
+		processOpts.Conv, processOpts.StripBOM = opts.ThenConv, false
+	}
+	stage := documentStage(conv, opts.BlockSize, stageErrs)
+	if (inputEncoding != nil || stage != nil || firstPass != nil) && opts.Limit > 0 {
+		reader = io.LimitReader(reader, int64(opts.Limit))
+		processOpts.Limit = 0
+	}
+	reader = decodeInput(reader, inputEncoding, opts.EncodingErrors)
+	if firstPass != nil {
+		converted := pipeStage(reader, firstPass)
+		defer converted.Close()
+		reader = converted
+	}
+	if stage != nil {
+		staged := pipeStage(reader, stage)
+		defer staged.Close()
+		reader = staged
+	}
+	encoded := encodeOutput(writer, outputEncoding, opts.EncodingErrors)
+	run := process
+	if opts.CSVColumns != "" {
+		run = processCSV
+	}
+	stats, err := run(reader, encoded, &processOpts)
+	stats.ConvErrors, stats.ThenConvErrors = convErrs.Count(), thenConvErrs.Count()
+	if err != nil {
+		return stats, err
+	}
+	return stats, encoded.Close()
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranscodeAndProcess(t *testing.T) {
+	cp1251, err := os.ReadFile("testdata/cp1251.txt")
+	assert.NoError(t, err)
+	cp1251Upper, err := os.ReadFile("testdata/cp1251_upper.txt")
+	assert.NoError(t, err)
+	utf8Upper, err := os.ReadFile("testdata/utf8_upper.txt")
+	assert.NoError(t, err)
+	utf16LE, err := os.ReadFile("testdata/utf16le_bom.txt")
+	assert.NoError(t, err)
+	utf16BEUpper, err := os.ReadFile("testdata/utf16be_bom_upper.txt")
+	assert.NoError(t, err)
+	utf8Emoji, err := os.ReadFile("testdata/utf8_upper_emoji.txt")
+	assert.NoError(t, err)
+
+	transcode := func(input []byte, opts *Options) ([]byte, error) {
+		out := &bytes.Buffer{}
+		if opts.BlockSize == 0 {
+			opts.BlockSize = 1000
+		}
+		_, err := transcodeAndProcess(bytes.NewReader(input), out, opts)
+		return out.Bytes(), err
+	}
+
+	t.Run("ok, windows-1251 input to utf-8 with upper case", func(t *testing.T) {
+		out, err := transcode(cp1251, &Options{InputEncoding: "windows-1251", Conv: "upper_case", BlockSize: 3})
+
+		assert.NoError(t, err)
+		assert.Equal(t, string(utf8Upper), string(out))
+	})
+
+	t.Run("ok, windows-1251 round trip with upper case", func(t *testing.T) {
+		out, err := transcode(cp1251, &Options{InputEncoding: "windows-1251", OutputEncoding: "Windows-1251", Conv: "upper_case"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, cp1251Upper, out)
+	})
+
+	t.Run("ok, utf-8 input to windows-1251", func(t *testing.T) {
+		out, err := transcode(utf8Upper, &Options{OutputEncoding: "windows-1251", BlockSize: 1})
+
+		assert.NoError(t, err)
+		assert.Equal(t, cp1251Upper, out)
+	})
+
+	t.Run("ok, utf-16 input with BOM to utf-8 with upper case", func(t *testing.T) {
+		out, err := transcode(utf16LE, &Options{InputEncoding: "utf-16", Conv: "upper_case", BlockSize: 1})
+
+		assert.NoError(t, err)
+		assert.Equal(t, string(utf8Emoji), string(out))
+	})
+
+	t.Run("ok, utf-16 input without BOM defaults to little endian", func(t *testing.T) {
+		out, err := transcode(utf16LE[2:], &Options{InputEncoding: "utf-16", Conv: "upper_case", BlockSize: 3})
+
+		assert.NoError(t, err)
+		assert.Equal(t, string(utf8Emoji), string(out))
+	})
+
+	t.Run("ok, utf-16 input detects big endian BOM", func(t *testing.T) {
+		out, err := transcode(utf16BEUpper, &Options{InputEncoding: "utf-16", BlockSize: 5})
+
+		assert.NoError(t, err)
+		assert.Equal(t, string(utf8Emoji), string(out))
+	})
+
+	t.Run("ok, utf-8 input to utf-16be with BOM", func(t *testing.T) {
+		out, err := transcode(utf8Emoji, &Options{OutputEncoding: "utf-16be", BOM: true, BlockSize: 1})
+
+		assert.NoError(t, err)
+		assert.Equal(t, utf16BEUpper, out)
+	})
+
+	t.Run("ok, utf-8 input to utf-16be without BOM", func(t *testing.T) {
+		out, err := transcode(utf8Emoji, &Options{OutputEncoding: "utf-16be"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, utf16BEUpper[2:], out)
+	})
+
+	t.Run("ok, limit counts raw input bytes", func(t *testing.T) {
+		out, err := transcode(cp1251, &Options{InputEncoding: "windows-1251", Limit: 6})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Привет", string(out))
+	})
+
+	t.Run("ok, undecodable byte replaced", func(t *testing.T) {
+		out, err := transcode([]byte("a\x98b"), &Options{InputEncoding: "windows-1251"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "a�b", string(out))
+	})
+
+	t.Run("error, undecodable byte in strict mode", func(t *testing.T) {
+		_, err := transcode([]byte("a\x98b"), &Options{InputEncoding: "windows-1251", EncodingErrors: EncodingErrorsStrict})
+
+		assert.ErrorContains(t, err, errUndecodable.Error())
+	})
+
+	t.Run("ok, unencodable rune replaced", func(t *testing.T) {
+		out, err := transcode([]byte("a😊b"), &Options{OutputEncoding: "windows-1251"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("a\x1ab"), out)
+	})
+
+	t.Run("error, unencodable rune in strict mode", func(t *testing.T) {
+		_, err := transcode([]byte("a😊b"), &Options{OutputEncoding: "windows-1251", EncodingErrors: EncodingErrorsStrict})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestEncodingValidation(t *testing.T) {
+	t.Run("error, unknown input encoding lists supported", func(t *testing.T) {
+		err := (&Options{InputEncoding: "cp-unknown"}).Validate()
+
+		assert.ErrorContains(t, err, "cp-unknown")
+		assert.ErrorContains(t, err, strings.Join(supportedEncodings(), ", "))
+	})
+
+	t.Run("error, unknown output encoding", func(t *testing.T) {
+		err := (&Options{OutputEncoding: "cp-unknown"}).Validate()
+
+		assert.ErrorContains(t, err, "-output-encoding")
+	})
+
+	t.Run("error, bom without utf-16 output", func(t *testing.T) {
+		err := (&Options{OutputEncoding: "windows-1251", BOM: true}).Validate()
+
+		assert.Error(t, err)
+	})
+
+	t.Run("error, unknown encoding errors policy", func(t *testing.T) {
+		err := (&Options{EncodingErrors: "ignore"}).Validate()
+
+		assert.Error(t, err)
+	})
+}
+
+func TestEncodingIntegration(t *testing.T) {
+	binPath := composeBinaryPath()
+	cmd := exec.Command("go", "build", "-o", binPath, "./")
+	assert.NoError(t, cmd.Run())
+	defer func() {
+		assert.NoError(t, os.Remove(binPath))
+	}()
+
+	t.Run("ok with windows-1251 file input and conv options", func(t *testing.T) {
+		cmd = exec.Command(binPath, "-from", "testdata/cp1251.txt", "-input-encoding=windows-1251", "-conv=upper_case")
+		stdout := &strings.Builder{}
+		cmd.Stdout = stdout
+		stderr := &strings.Builder{}
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+
+		assert.NoError(t, err)
+		assert.Zero(t, stderr.Len(), stderr.String())
+		expected, err := os.ReadFile("testdata/utf8_upper.txt")
+		assert.NoError(t, err)
+		assert.Equal(t, string(expected), stdout.String())
+	})
+
+	t.Run("error with unknown encoding", func(t *testing.T) {
+		cmd = exec.Command(binPath, "-input-encoding=ebcdic")
+		cmd.Stdin = strings.NewReader(testInput)
+		stdout := &strings.Builder{}
+		cmd.Stdout = stdout
+		stderr := &strings.Builder{}
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+
+		assert.Error(t, err)
+		assert.Contains(t, stderr.String(), "windows-1251")
+		assert.Zero(t, stdout.Len())
+	})
+}
+
+func TestBOM(t *testing.T) {
+	run := func(input string, opts *Options) string {
+		out := &bytes.Buffer{}
+		_, err := process(strings.NewReader(input), out, opts)
+		assert.NoError(t, err)
+		return out.String()
+	}
+
+	for _, blockSize := range []uint{1, 2, 3, 1000} {
+		t.Run("ok, strip leading BOM", func(t *testing.T) {
+			out := run("\uFEFFhello", &Options{BlockSize: blockSize, StripBOM: true})
+
+			assert.Equal(t, "hello", out, "block size %d", blockSize)
+		})
+
+		t.Run("ok, strip BOM without BOM in input", func(t *testing.T) {
+			out := run("hello\uFEFF", &Options{BlockSize: blockSize, StripBOM: true})
+
+			assert.Equal(t, "hello\uFEFF", out, "block size %d", blockSize)
+		})
+
+		t.Run("ok, strip BOM with trim spaces", func(t *testing.T) {
+			out := run("\uFEFF \t hello \n", &Options{BlockSize: blockSize, StripBOM: true, Conv: "trim_spaces"})
+
+			assert.Equal(t, "hello", out, "block size %d", blockSize)
+		})
+
+		t.Run("ok, BOM is kept without strip option", func(t *testing.T) {
+			out := run("\uFEFF hello ", &Options{BlockSize: blockSize, Conv: "trim_spaces"})
+
+			assert.Equal(t, "\uFEFF hello", out, "block size %d", blockSize)
+		})
+
+		t.Run("ok, BOM after offset is not leading", func(t *testing.T) {
+			out := run("\uFEFFhello", &Options{BlockSize: blockSize, StripBOM: true, Offset: 2})
+
+			assert.Equal(t, "\uFEFFhello", out, "block size %d", blockSize)
+		})
+
+		t.Run("ok, add BOM", func(t *testing.T) {
+			out := run("hello", &Options{BlockSize: blockSize, AddBOM: true})
+
+			assert.Equal(t, "\uFEFFhello", out, "block size %d", blockSize)
+		})
+
+		t.Run("ok, strip and add BOM", func(t *testing.T) {
+			out := run("\uFEFFhello", &Options{BlockSize: blockSize, StripBOM: true, AddBOM: true})
+
+			assert.Equal(t, "\uFEFFhello", out, "block size %d", blockSize)
+		})
+	}
+
+	t.Run("ok, add BOM to empty input", func(t *testing.T) {
+		out := run("", &Options{BlockSize: 1, AddBOM: true})
+
+		assert.Equal(t, "\uFEFF", out)
+	})
+
+	t.Run("error, add BOM with single byte output encoding", func(t *testing.T) {
+		err := (&Options{OutputEncoding: "windows-1251", AddBOM: true}).Validate()
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,125 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+// makeFIFO creates a named pipe and writes data to it in background
+func makeFIFO(t *testing.T, data string) string {
+	path := filepath.Join(t.TempDir(), "input.fifo")
+	require.NoError(t, unix.Mkfifo(path, 0666))
+	go func() {
+		// opening blocks until the pipe is opened for reading
+		fifo, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return
+		}
+		defer fifo.Close()
+		_, _ = fifo.WriteString(data)
+	}()
+	return path
+}
+
+func TestSpecialFilesValidation(t *testing.T) {
+	t.Run("ok, offset of fifo input", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "input.fifo")
+		require.NoError(t, unix.Mkfifo(path, 0666))
+
+		assert.NoError(t, (&Options{From: path, Offset: 100}).Validate())
+	})
+
+	t.Run("ok, character device output", func(t *testing.T) {
+		assert.NoError(t, (&Options{To: os.DevNull}).Validate())
+		assert.NoError(t, (&Options{BlocksReport: os.DevNull}).Validate())
+	})
+
+	t.Run("ok, no confirmation for character device", func(t *testing.T) {
+		terminal := &fakeTerminal{terminal: true, answer: "n\n"}
+
+		overwrite, err := confirmOverwrite(&Options{To: os.DevNull, Force: true}, terminal, &strings.Builder{})
+
+		assert.NoError(t, err)
+		assert.True(t, overwrite)
+		assert.False(t, terminal.asked)
+	})
+
+	t.Run("error, offset of regular file is still checked", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "input.txt")
+		require.NoError(t, os.WriteFile(path, []byte("data"), 0666))
+
+		assert.Error(t, (&Options{From: path, Offset: 100}).Validate())
+	})
+}
+
+func TestFIFOIntegration(t *testing.T) {
+	binPath := composeBinaryPath()
+	cmd := exec.Command("go", "build", "-o", binPath, "./")
+	assert.NoError(t, cmd.Run())
+	defer func() {
+		assert.NoError(t, os.Remove(binPath))
+	}()
+
+	t.Run("ok with fifo input and offset", func(t *testing.T) {
+		cmd = exec.Command(binPath, "-from", makeFIFO(t, testInput), "-offset", "1200", "-limit", "100")
+		stdout := &strings.Builder{}
+		cmd.Stdout = stdout
+		stderr := &strings.Builder{}
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+
+		assert.NoError(t, err)
+		assert.Zero(t, stderr.Len(), stderr.String())
+		assert.Equal(t, testInput[1200:1300], stdout.String())
+	})
+
+	t.Run("ok with fifo input and ranges", func(t *testing.T) {
+		cmd = exec.Command(binPath, "-from", makeFIFO(t, testInput), "-ranges", "0-4,1200-1299")
+		stdout := &strings.Builder{}
+		cmd.Stdout = stdout
+		stderr := &strings.Builder{}
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+
+		assert.NoError(t, err)
+		assert.Zero(t, stderr.Len(), stderr.String())
+		assert.Equal(t, testInput[:5]+testInput[1200:1300], stdout.String())
+	})
+
+	t.Run("ok with /dev/null output", func(t *testing.T) {
+		cmd = exec.Command(binPath, "-to", os.DevNull)
+		cmd.Stdin = strings.NewReader(testInput)
+		stderr := &strings.Builder{}
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+
+		assert.NoError(t, err)
+		assert.Zero(t, stderr.Len(), stderr.String())
+	})
+
+	t.Run("error with fifo offset beyond input", func(t *testing.T) {
+		cmd = exec.Command(binPath, "-from", makeFIFO(t, "short"), "-offset", "100")
+		stdout := &strings.Builder{}
+		cmd.Stdout = stdout
+		stderr := &strings.Builder{}
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+
+		assert.Error(t, err)
+		assert.Contains(t, stderr.String(), "apply offset failed")
+		assert.Zero(t, stdout.Len())
+	})
+}
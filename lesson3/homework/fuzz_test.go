@@ -0,0 +1,110 @@
+//go:build fuzz
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+// run with: go test -tags fuzz -run '^$' -fuzz FuzzProcess
+func FuzzProcess(f *testing.F) {
+	seeds := []string{
+		"",
+		"hello",
+		"  hELlO evEryOnE!  ",
+		"\t\n Машинное обучение \r\n",
+		"😊🎉💋😍😋",
+		" 😊 ",
+		"é ä ñ",
+		"　 text ",
+		"\xff",
+		"\xffa ",
+		" \xfe\xff ",
+		"\xe2\x82",
+		"abc\xf0\x9f\x98",
+		"\xf0\x9f\x98 \xf0\x9f",
+		"ſ ı ǅ K",
+	}
+	for _, seed := range seeds {
+		for _, blockSize := range []uint8{0, 1, 2, 3, 15} {
+			f.Add([]byte(seed), blockSize, uint8(0))
+			f.Add([]byte(seed), blockSize, uint8(7))
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, input []byte, blockSize uint8, convMask uint8) {
+		size := uint(blockSize%16) + 1
+		conv := fuzzConv(convMask)
+		out := fuzzProcess(t, input, size, conv)
+
+		if reference := fuzzProcess(t, input, 1000, conv); !bytes.Equal(out, reference) {
+			t.Fatalf("block size %d changed output for conv %q: %q != %q", size, conv, out, reference)
+		}
+		if utf8.Valid(input) && !utf8.Valid(out) {
+			t.Fatalf("valid input %q produced invalid output %q for conv %q", input, out, conv)
+		}
+		if conv == "" && !bytes.Equal(out, input) {
+			t.Fatalf("output without conv differs from input: %q != %q", out, input)
+		}
+		if strings.Contains(conv, string(TrimSpaces)) {
+			if trimmed := bytes.TrimFunc(out, unicode.IsSpace); !bytes.Equal(trimmed, out) {
+				t.Fatalf("trim_spaces output has surrounding spaces: %q", out)
+			}
+			if conv == string(TrimSpaces) && !bytes.Equal(out, bytes.TrimFunc(input, unicode.IsSpace)) {
+				t.Fatalf("trim_spaces output differs from trimmed input: %q", out)
+			}
+		}
+		if caseRoundTrips(input) {
+			lower := fuzzProcess(t, input, size, string(LowerCase))
+			upperThenLower := fuzzProcess(t, fuzzProcess(t, input, size, string(UpperCase)), size, string(LowerCase))
+			if !bytes.Equal(upperThenLower, lower) {
+				t.Fatalf("upper then lower differs from lower: %q != %q", upperThenLower, lower)
+			}
+		}
+	})
+}
+
+func fuzzConv(mask uint8) string {
+	var conv []string
+	if mask&1 != 0 {
+		conv = append(conv, string(TrimSpaces))
+	}
+	switch (mask >> 1) % 3 {
+	case 1:
+		conv = append(conv, string(UpperCase))
+	case 2:
+		conv = append(conv, string(LowerCase))
+	}
+	if mask&8 != 0 {
+		// conversion order must not matter
+		for i, j := 0, len(conv)-1; i < j; i, j = i+1, j-1 {
+			conv[i], conv[j] = conv[j], conv[i]
+		}
+	}
+	return strings.Join(conv, ",")
+}
+
+func fuzzProcess(t *testing.T, input []byte, blockSize uint, conv string) []byte {
+	t.Helper()
+	out := &bytes.Buffer{}
+	opts := &Options{BlockSize: blockSize, Conv: conv}
+	if _, err := process(bytes.NewReader(input), out, opts); err != nil {
+		t.Fatalf("process(%q, block size %d, conv %q) failed: %v", input, blockSize, conv, err)
+	}
+	return out.Bytes()
+}
+
+// caseRoundTrips reports whether lowering the upper case form of every rune gives its lower case form.
+// it doesn't hold for runes like 'ſ' or 'ı' whose upper case form lowers to a different letter
+func caseRoundTrips(input []byte) bool {
+	for _, r := range string(input) {
+		if unicode.ToLower(unicode.ToUpper(r)) != unicode.ToLower(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"html"
+	"io"
+)
+
+const StripHTML ConvOption = "strip_html"
+
+// maxHTMLEntity is the longest entity name, longer text after '&' isn't an entity
+const maxHTMLEntity = 32
+
+type htmlState int
+
+const (
+	htmlText htmlState = iota
+	htmlTag
+	htmlComment
+	htmlEntity
+)
+
+// htmlBlockTags separate words of surrounding text
+var htmlBlockTags = map[string]bool{
+	"br": true, "p": true, "div": true, "li": true, "ul": true, "ol": true, "tr": true, "td": true, "th": true,
+	"table": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "title": true,
+	"header": true, "footer": true, "section": true, "article": true, "hr": true, "pre": true, "blockquote": true,
+}
+
+// htmlStripper extracts text from html streamed block by block.
+// tags and comments are removed, entities are decoded, content of script and style is dropped
+// and whitespace runs are collapsed into a single space
+type htmlStripper struct {
+	state  htmlState
+	tag    []byte
+	quote  byte
+	entity []byte
+	// skipUntil is the name of script or style element which content is dropped
+	skipUntil string
+	// space is a pending whitespace which is written before next text
+	space   bool
+	started bool
+}
+
+func isHTMLSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\f'
+}
+
+func isEntityByte(b byte) bool {
+	return b == '#' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || '0' <= b && b <= '9'
+}
+
+// Strip appends text of the next html block to out.
+// if final is set, unfinished entity is written as is
+func (s *htmlStripper) Strip(out []byte, data []byte, final bool) []byte {
+	for i := 0; i < len(data); i++ {
+		b := data[i]
+		switch s.state {
+		case htmlText:
+			switch {
+			case b == '<':
+				s.state = htmlTag
+				s.tag = s.tag[:0]
+			case s.skipUntil != "":
+			case b == '&':
+				s.state = htmlEntity
+				s.entity = s.entity[:0]
+			default:
+				out = s.text(out, b)
+			}
+		case htmlTag:
+			switch {
+			case s.quote != 0:
+				if b == s.quote {
+					s.quote = 0
+				}
+			case (b == '"' || b == '\'') && s.skipUntil == "":
+				s.quote = b
+			case b == '>':
+				s.endTag()
+			default:
+				s.tag = append(s.tag, b)
+				if len(s.tag) == 3 && string(s.tag) == "!--" {
+					s.state = htmlComment
+				}
+			}
+		case htmlComment:
+			s.tag = append(s.tag, b)
+			if bytes.HasSuffix(s.tag, []byte("-->")) {
+				s.state = htmlText
+			}
+			if len(s.tag) > 3 {
+				s.tag = append(s.tag[:0], s.tag[len(s.tag)-3:]...)
+			}
+		case htmlEntity:
+			if b == ';' {
+				out = s.writeEntity(out, true)
+				continue
+			}
+			if isEntityByte(b) && len(s.entity) < maxHTMLEntity {
+				s.entity = append(s.entity, b)
+				continue
+			}
+			// not an entity, process the byte as a text again
+			out = s.writeEntity(out, false)
+			i--
+		}
+	}
+	if final && s.state == htmlEntity {
+		out = s.writeEntity(out, false)
+	}
+	return out
+}
+
+func (s *htmlStripper) text(out []byte, b byte) []byte {
+	if isHTMLSpace(b) {
+		s.space = s.started
+		return out
+	}
+	if s.space {
+		out = append(out, ' ')
+		s.space = false
+	}
+	s.started = true
+	return append(out, b)
+}
+
+func (s *htmlStripper) writeEntity(out []byte, terminated bool) []byte {
+	s.state = htmlText
+	text := "&" + string(s.entity)
+	if terminated {
+		text = html.UnescapeString(text + ";")
+	}
+	for i := 0; i < len(text); i++ {
+		out = s.text(out, text[i])
+	}
+	return out
+}
+
+func (s *htmlStripper) endTag() {
+	s.state = htmlText
+	tag := s.tag
+	closing := len(tag) > 0 && tag[0] == '/'
+	if closing {
+		tag = tag[1:]
+	}
+	end := bytes.IndexFunc(tag, func(r rune) bool {
+		return r == '/' || r < 128 && isHTMLSpace(byte(r))
+	})
+	if end >= 0 {
+		tag = tag[:end]
+	}
+	name := string(bytes.ToLower(tag))
+	if s.skipUntil != "" {
+		if closing && name == s.skipUntil {
+			s.skipUntil = ""
+		}
+		return
+	}
+	selfClosing := bytes.HasSuffix(s.tag, []byte("/"))
+	if !closing && !selfClosing && (name == "script" || name == "style") {
+		s.skipUntil = name
+	}
+	if htmlBlockTags[name] {
+		s.space = s.started
+	}
+}
+
+// stripHTML extracts text of the html document reading it by blocks
+func stripHTML(reader io.Reader, writer io.Writer, blockSize uint) error {
+	var stripper htmlStripper
+	buffer := make([]byte, blockSize)
+	var out []byte
+	for {
+		count, err := reader.Read(buffer)
+		if err != nil && err != io.EOF {
+			return err
+		}
+		final := err == io.EOF
+		out = stripper.Strip(out[:0], buffer[:count], final)
+		if len(out) > 0 {
+			if _, err := writer.Write(out); err != nil {
+				return err
+			}
+		}
+		if final {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripHTML(t *testing.T) {
+	page, err := os.ReadFile("testdata/html/page.html")
+	assert.NoError(t, err)
+	expected, err := os.ReadFile("testdata/html/page.txt")
+	assert.NoError(t, err)
+
+	run := func(input []byte, opts *Options) string {
+		out := &bytes.Buffer{}
+		_, err := transcodeAndProcess(bytes.NewReader(input), out, opts)
+		assert.NoError(t, err)
+		return out.String()
+	}
+
+	t.Run("ok, fixture page with every block size", func(t *testing.T) {
+		for blockSize := uint(1); blockSize <= 64; blockSize++ {
+			out := run(page, &Options{Conv: "strip_html", BlockSize: blockSize})
+
+			assert.Equal(t, string(expected), out, "block size %d", blockSize)
+		}
+	})
+
+	t.Run("ok, tag split across blocks", func(t *testing.T) {
+		var stripper htmlStripper
+
+		out := stripper.Strip(nil, []byte("a <b cla"), false)
+		out = stripper.Strip(out, []byte(`ss="x>y">bold</`), false)
+		out = stripper.Strip(out, []byte("b> c"), true)
+
+		assert.Equal(t, "a bold c", string(out))
+	})
+
+	t.Run("ok, entity split across blocks", func(t *testing.T) {
+		var stripper htmlStripper
+
+		out := stripper.Strip(nil, []byte("fish &am"), false)
+		out = stripper.Strip(out, []byte("p; chips &#x1F6"), false)
+		out = stripper.Strip(out, []byte("00;"), true)
+
+		assert.Equal(t, "fish & chips 😀", string(out))
+	})
+
+	t.Run("ok, unfinished entity at the end", func(t *testing.T) {
+		var stripper htmlStripper
+
+		out := stripper.Strip(nil, []byte("a &amp"), true)
+
+		assert.Equal(t, "a &amp", string(out))
+	})
+
+	t.Run("ok, strip html with rune conversions", func(t *testing.T) {
+		out := run([]byte("<p> Caf&eacute; </p>\n"), &Options{Conv: "strip_html,upper_case", BlockSize: 3})
+
+		assert.Equal(t, "CAFÉ", out)
+	})
+
+	t.Run("error, strip html with json", func(t *testing.T) {
+		err := (&Options{Conv: "strip_html,json_pretty"}).Validate()
+
+		assert.Error(t, err)
+	})
+}
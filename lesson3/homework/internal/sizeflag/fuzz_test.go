@@ -0,0 +1,32 @@
+//go:build fuzz
+
+package sizeflag_test
+
+import (
+	"testing"
+
+	"lecture03_homework/internal/sizeflag"
+)
+
+// run with: go test -tags fuzz -run '^$' -fuzz FuzzRoundTrip ./internal/sizeflag
+func FuzzRoundTrip(f *testing.F) {
+	for shift := 0; shift < 63; shift += 7 {
+		f.Add(int64(1) << shift)
+		f.Add(int64(1)<<shift - 1)
+	}
+	f.Add(int64(1000))
+
+	f.Fuzz(func(t *testing.T, size int64) {
+		if size < 0 {
+			size = -(size + 1)
+		}
+		text := sizeflag.Format(size)
+		parsed, err := sizeflag.Parse(text)
+		if err != nil {
+			t.Fatalf("Parse(%q) of Format(%d) failed: %v", text, size, err)
+		}
+		if parsed != size {
+			t.Fatalf("Parse(Format(%d)) = Parse(%q) = %d", size, text, parsed)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+// Package sizeflag parses and formats human-readable sizes in bytes like 10MiB and provides a flag.Value for them
+package sizeflag
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// binaryUnits are written by Format, the i-th unit is 1024^i bytes
+var binaryUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+// multipliers maps lower case units accepted by Parse to their sizes in bytes
+var multipliers = map[string]int64{"": 1, "b": 1}
+
+func init() {
+	binary, decimal := int64(1), int64(1)
+	for _, prefix := range "kmgtpe" {
+		binary *= 1024
+		decimal *= 1000
+		multipliers[string(prefix)] = binary
+		multipliers[string(prefix)+"ib"] = binary
+		multipliers[string(prefix)+"b"] = decimal
+	}
+}
+
+// Parse parses a non-negative integer number of bytes with an optional unit, spaces around the unit are allowed.
+// units are case-insensitive: B is a byte, K, M, G, T, P, E and KiB, MiB, ... are powers of 1024,
+// KB, MB, ... are powers of 1000. e.g. "10MiB", "10 M" and "10485760" are the same size
+func Parse(s string) (int64, error) {
+	text := strings.TrimSpace(s)
+	end := 0
+	for end < len(text) && '0' <= text[end] && text[end] <= '9' {
+		end++
+	}
+	if end == 0 {
+		return 0, fmt.Errorf("invalid size %q: expected a number of bytes with an optional unit, e.g. 10MiB", s)
+	}
+	unit := strings.TrimSpace(text[end:])
+	multiplier, ok := multipliers[strings.ToLower(unit)]
+	if !ok {
+		return 0, fmt.Errorf("invalid size %q: unknown unit %q", s, unit)
+	}
+	n, err := strconv.ParseInt(text[:end], 10, 64)
+	if err != nil || n > math.MaxInt64/multiplier {
+		return 0, fmt.Errorf("invalid size %q: out of range", s)
+	}
+	return n * multiplier, nil
+}
+
+// Format writes the size with the largest binary unit dividing it exactly, e.g. 10485760 is "10 MiB"
+// and 1000 is "1000 B", so Parse(Format(n)) == n for every non-negative n
+func Format(n int64) string {
+	unit := 0
+	for n != 0 && n%1024 == 0 && unit < len(binaryUnits)-1 {
+		n /= 1024
+		unit++
+	}
+	return strconv.FormatInt(n, 10) + " " + binaryUnits[unit]
+}
+
+// Value is a size in bytes implementing flag.Value and flag.Getter, e.g.
+//
+//	flags.Var((*sizeflag.Value)(&opts.MaxSize), "max-size", "largest size, e.g. 10MiB")
+type Value int64
+
+// Var defines a size flag of the flag set with the default value stored into p
+func Var(flags *flag.FlagSet, p *int64, name string, value int64, usage string) {
+	*p = value
+	flags.Var((*Value)(p), name, usage)
+}
+
+func (v *Value) String() string {
+	return Format(int64(*v))
+}
+
+func (v *Value) Set(s string) error {
+	n, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*v = Value(n)
+	return nil
+}
+
+// Get returns the size as int64
+func (v *Value) Get() any {
+	return int64(*v)
+}
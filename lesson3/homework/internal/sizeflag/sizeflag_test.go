@@ -0,0 +1,136 @@
+package sizeflag_test
+
+import (
+	"flag"
+	"io"
+	"math"
+	"math/rand"
+	"testing"
+
+	"lecture03_homework/internal/sizeflag"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ flag.Value  = (*sizeflag.Value)(nil)
+	_ flag.Getter = (*sizeflag.Value)(nil)
+)
+
+func TestParse(t *testing.T) {
+	t.Run("ok, units", func(t *testing.T) {
+		for text, want := range map[string]int64{
+			"0":                 0,
+			"1000":              1000,
+			"12B":               12,
+			"10MiB":             10 << 20,
+			"10 MiB":            10 << 20,
+			" 10m ":             10 << 20,
+			"3K":                3 << 10,
+			"3kib":              3 << 10,
+			"3KB":               3000,
+			"2GB":               2000000000,
+			"1EiB":              1 << 60,
+			"7EiB":              7 << 60,
+			"8796093022207 MiB": 8796093022207 << 20,
+		} {
+			size, err := sizeflag.Parse(text)
+
+			require.NoError(t, err, text)
+			assert.Equal(t, want, size, text)
+		}
+	})
+
+	t.Run("error, invalid sizes", func(t *testing.T) {
+		for text, want := range map[string]string{
+			"":                     `invalid size "": expected a number of bytes with an optional unit, e.g. 10MiB`,
+			"MiB":                  `invalid size "MiB": expected a number of bytes with an optional unit, e.g. 10MiB`,
+			"-1":                   `invalid size "-1": expected a number of bytes with an optional unit, e.g. 10MiB`,
+			"1.5MiB":               `invalid size "1.5MiB": unknown unit ".5MiB"`,
+			"10 parsec":            `invalid size "10 parsec": unknown unit "parsec"`,
+			"8EiB":                 `invalid size "8EiB": out of range`,
+			"99999999999999999999": `invalid size "99999999999999999999": out of range`,
+		} {
+			_, err := sizeflag.Parse(text)
+
+			assert.EqualError(t, err, want, text)
+		}
+	})
+}
+
+func TestFormat(t *testing.T) {
+	for n, want := range map[int64]string{
+		0:                    "0 B",
+		1000:                 "1000 B",
+		1024:                 "1 KiB",
+		1536:                 "1536 B",
+		10 << 20:             "10 MiB",
+		3 << 30:              "3 GiB",
+		1 << 62:              "4 EiB",
+		math.MaxInt64:        "9223372036854775807 B",
+		math.MaxInt64 - 1023: "9007199254740991 KiB",
+	} {
+		assert.Equal(t, want, sizeflag.Format(n), n)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	t.Run("ok, powers of two", func(t *testing.T) {
+		for shift := 0; shift < 63; shift++ {
+			n := int64(1) << shift
+
+			for _, size := range []int64{n, n - 1, n + 1, 3 * n} {
+				if size < 0 {
+					continue
+				}
+				parsed, err := sizeflag.Parse(sizeflag.Format(size))
+
+				require.NoError(t, err, size)
+				assert.Equal(t, size, parsed, size)
+			}
+		}
+	})
+
+	t.Run("ok, arbitrary values", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(1))
+		for i := 0; i < 10000; i++ {
+			size := rnd.Int63() >> rnd.Intn(63)
+
+			parsed, err := sizeflag.Parse(sizeflag.Format(size))
+
+			require.NoError(t, err, size)
+			assert.Equal(t, size, parsed, size)
+		}
+	})
+}
+
+func TestValue(t *testing.T) {
+	t.Run("ok, flag set", func(t *testing.T) {
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		var limit, maxSize int64
+		sizeflag.Var(flags, &limit, "limit", 4<<10, "limit")
+		flags.Var((*sizeflag.Value)(&maxSize), "max-size", "max size")
+
+		err := flags.Parse([]string{"-max-size", "2MiB"})
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(4<<10), limit)
+		assert.Equal(t, int64(2<<20), maxSize)
+		assert.Equal(t, "2 MiB", flags.Lookup("max-size").Value.String())
+		assert.Equal(t, int64(2<<20), flags.Lookup("max-size").Value.(flag.Getter).Get())
+		assert.Equal(t, "4 KiB", flags.Lookup("limit").DefValue)
+	})
+
+	t.Run("error, invalid size keeps value", func(t *testing.T) {
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags.SetOutput(io.Discard)
+		var limit int64
+		sizeflag.Var(flags, &limit, "limit", 10, "limit")
+
+		err := flags.Parse([]string{"-limit", "10 parsec"})
+
+		assert.EqualError(t, err, `invalid value "10 parsec" for flag -limit: invalid size "10 parsec": unknown unit "parsec"`)
+		assert.Equal(t, int64(10), limit)
+	})
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	JSONPretty ConvOption = "json_pretty"
+	JSONMinify ConvOption = "json_minify"
+)
+
+const jsonIndent = "  "
+
+// countingReader counts bytes read from the underlying reader
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.reader.Read(p)
+	c.count += int64(n)
+	return n, err
+}
+
+// reformatJSON re-serializes every JSON document of input, so NDJSON is handled document by document.
+// documents are decoded as raw messages, which keeps key order and number precision as is
+func reformatJSON(reader io.Reader, writer io.Writer, pretty bool) error {
+	counter := &countingReader{reader: reader}
+	decoder := json.NewDecoder(counter)
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+	if pretty {
+		encoder.SetIndent("", jsonIndent)
+	}
+	for {
+		var document json.RawMessage
+		err := decoder.Decode(&document)
+		if err == io.EOF {
+			return nil
+		}
+		var syntaxErr *json.SyntaxError
+		switch {
+		case errors.Is(err, io.ErrUnexpectedEOF):
+			return fmt.Errorf("unexpected end of JSON input at offset %d", counter.count)
+		case errors.As(err, &syntaxErr):
+			return fmt.Errorf("invalid JSON at offset %d: %v", syntaxErr.Offset, err)
+		case err != nil:
+			return err
+		}
+		err = encoder.Encode(document)
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONConversions(t *testing.T) {
+	run := func(input []byte, opts *Options) (string, error) {
+		out := &bytes.Buffer{}
+		_, err := transcodeAndProcess(bytes.NewReader(input), out, opts)
+		return out.String(), err
+	}
+	golden := []struct {
+		input, conv, expected string
+	}{
+		{"testdata/json/nested.json", "json_pretty", "testdata/json/nested.pretty.json"},
+		{"testdata/json/nested.json", "json_minify", "testdata/json/nested.min.json"},
+		{"testdata/json/nested.pretty.json", "json_minify", "testdata/json/nested.min.json"},
+		{"testdata/json/records.ndjson", "json_pretty", "testdata/json/records.pretty.ndjson"},
+		{"testdata/json/records.ndjson", "json_minify", "testdata/json/records.min.ndjson"},
+	}
+	for _, g := range golden {
+		input, err := os.ReadFile(g.input)
+		assert.NoError(t, err)
+		expected, err := os.ReadFile(g.expected)
+		assert.NoError(t, err)
+
+		for _, blockSize := range []uint{1, 16, 1000} {
+			out, err := run(input, &Options{Conv: g.conv, BlockSize: blockSize})
+
+			assert.NoError(t, err, "%s with %s", g.input, g.conv)
+			assert.Equal(t, string(expected), out, "%s with %s and block size %d", g.input, g.conv, blockSize)
+		}
+	}
+
+	t.Run("ok, json with rune conversions", func(t *testing.T) {
+		out, err := run([]byte(` {"key": "value"} `), &Options{Conv: "upper_case,json_minify", BlockSize: 4})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "{\"KEY\":\"VALUE\"}\n", out)
+	})
+
+	t.Run("error, limit truncates json", func(t *testing.T) {
+		_, err := run([]byte(`{"key": "value"}`), &Options{Conv: "json_pretty", BlockSize: 4, Limit: 10})
+
+		assert.EqualError(t, err, "error while reading: unexpected end of JSON input at offset 10")
+	})
+
+	t.Run("error, invalid json", func(t *testing.T) {
+		_, err := run([]byte("{\"key\": 1}\n{\"key\" 2}"), &Options{Conv: "json_minify", BlockSize: 1000})
+
+		assert.ErrorContains(t, err, "invalid JSON at offset")
+	})
+
+	t.Run("error, both json conversions", func(t *testing.T) {
+		err := (&Options{Conv: "json_pretty,json_minify"}).Validate()
+
+		assert.Error(t, err)
+	})
+}
+
+func TestJSONIntegration(t *testing.T) {
+	binPath := composeBinaryPath()
+	cmd := exec.Command("go", "build", "-o", binPath, "./")
+	assert.NoError(t, cmd.Run())
+	defer func() {
+		assert.NoError(t, os.Remove(binPath))
+	}()
+
+	t.Run("error with truncated json", func(t *testing.T) {
+		cmd = exec.Command(binPath, "-conv=json_pretty", "-limit=5")
+		cmd.Stdin = strings.NewReader(`{"key": "value"}`)
+		stdout := &strings.Builder{}
+		cmd.Stdout = stdout
+		stderr := &strings.Builder{}
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+
+		assert.Error(t, err)
+		assert.Contains(t, stderr.String(), "unexpected end of JSON input at offset 5")
+	})
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"lecture03_homework/internal/sizeflag"
+)
+
+// exitOutputTooLarge is the exit code of a copy aborted by -max-output-size
+const exitOutputTooLarge = 3
+
+// exitTruncated is the exit code of a copy stopped by -limit before the end of input with -exit-nonzero-on-truncate
+const exitTruncated = 6
+
+var errOutputTooLarge = errors.New("output exceeds -max-output-size")
+
+// sizeLimitWriter fails a write which would make the output larger than limit.
+// the failed write is dropped as a whole, so the output holds only complete writes
+type sizeLimitWriter struct {
+	writer  io.Writer
+	limit   int64
+	written int64
+}
+
+func (w *sizeLimitWriter) Write(p []byte) (int, error) {
+	if w.written+int64(len(p)) > w.limit {
+		return 0, fmt.Errorf("%w: %d bytes written, writing %d more bytes would exceed %s",
+			errOutputTooLarge, w.written, len(p), sizeflag.Format(w.limit))
+	}
+	n, err := w.writer.Write(p)
+	w.written += int64(n)
+	return n, err
+}
+
+// limitOutput wraps the raw output to enforce -max-output-size, zero size means unlimited
+func limitOutput(writer io.Writer, maxSize int64) io.Writer {
+	if maxSize <= 0 {
+		return writer
+	}
+	return &sizeLimitWriter{writer: writer, limit: maxSize}
+}
+
+// needsTruncation reports whether Stats.Truncated is used. detecting truncation reads past -limit,
+// which would block on input typed in terminal, so it's done only if needed
+func (o *Options) needsTruncation() bool {
+	return o.ExitNonzeroOnTruncate || o.SummaryFormat != ""
+}
+
+// hasMoreInput tries to read one more byte after the copy stopped by -limit
+func hasMoreInput(reader io.Reader) bool {
+	var probe [1]byte
+	_, err := io.ReadFull(reader, probe[:])
+	return err == nil
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxOutputSize(t *testing.T) {
+	run := func(input string, maxSize int64, opts *Options) (string, error) {
+		out := &bytes.Buffer{}
+		_, err := transcodeAndProcess(strings.NewReader(input), limitOutput(out, maxSize), opts)
+		return out.String(), err
+	}
+
+	t.Run("ok, output fits exactly", func(t *testing.T) {
+		out, err := run("abcdefghij", 10, &Options{BlockSize: 4})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "abcdefghij", out)
+	})
+
+	t.Run("ok, zero is unlimited", func(t *testing.T) {
+		out, err := run("abcdefghij", 0, &Options{BlockSize: 4})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "abcdefghij", out)
+	})
+
+	t.Run("error, limit on block boundary", func(t *testing.T) {
+		out, err := run("abcdefghij", 8, &Options{BlockSize: 4})
+
+		assert.True(t, errors.Is(err, errOutputTooLarge), err)
+		assert.EqualError(t, err, "output exceeds -max-output-size: 8 bytes written, writing 2 more bytes would exceed 8 B")
+		assert.Equal(t, "abcdefgh", out)
+	})
+
+	t.Run("error, limit in the middle of block", func(t *testing.T) {
+		out, err := run("abcdefghij", 6, &Options{BlockSize: 4})
+
+		assert.True(t, errors.Is(err, errOutputTooLarge), err)
+		assert.Equal(t, "abcd", out)
+	})
+
+	t.Run("error, bytes flushed by output encoding are counted", func(t *testing.T) {
+		out, err := run("abcdefghij", 19, &Options{BlockSize: 4, OutputEncoding: "utf-16le"})
+
+		assert.True(t, errors.Is(err, errOutputTooLarge), err)
+		assert.Equal(t, 16, len(out))
+
+		out, err = run("abcdefghij", 20, &Options{BlockSize: 4, OutputEncoding: "utf-16le"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 20, len(out))
+	})
+
+	t.Run("error, negative size", func(t *testing.T) {
+		assert.Error(t, (&Options{MaxOutputSize: -1}).Validate())
+	})
+}
+
+func TestMaxOutputSizeIntegration(t *testing.T) {
+	binPath := composeBinaryPath()
+	cmd := exec.Command("go", "build", "-o", binPath, "./")
+	assert.NoError(t, cmd.Run())
+	defer func() {
+		assert.NoError(t, os.Remove(binPath))
+	}()
+
+	t.Run("ok with size suffix", func(t *testing.T) {
+		cmd = exec.Command(binPath, "-max-output-size", "1MiB")
+		cmd.Stdin = strings.NewReader(testInput)
+		stdout := &strings.Builder{}
+		cmd.Stdout = stdout
+		stderr := &strings.Builder{}
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+
+		assert.NoError(t, err)
+		assert.Zero(t, stderr.Len(), stderr.String())
+		assert.Equal(t, testInput, stdout.String())
+	})
+
+	t.Run("error with too large output", func(t *testing.T) {
+		outPath := filepath.Join(t.TempDir(), "out.txt")
+		cmd = exec.Command(binPath, "-max-output-size", "1000", "-block-size", "300", "-to", outPath)
+		cmd.Stdin = strings.NewReader(testInput)
+		stderr := &strings.Builder{}
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+
+		var exitErr *exec.ExitError
+		require.True(t, errors.As(err, &exitErr), err)
+		assert.Equal(t, exitOutputTooLarge, exitErr.ExitCode())
+		assert.Contains(t, stderr.String(), "output exceeds -max-output-size")
+		data, err := os.ReadFile(outPath)
+		require.NoError(t, err)
+		// complete blocks are kept, the block crossing the limit is not written
+		assert.LessOrEqual(t, len(data), 1000)
+		assert.Greater(t, len(data), 1000-300)
+		assert.True(t, strings.HasPrefix(testInput, string(data)))
+	})
+
+	t.Run("error with invalid size", func(t *testing.T) {
+		cmd = exec.Command(binPath, "-max-output-size", "10 parsec")
+		stderr := &strings.Builder{}
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+
+		assert.Error(t, err)
+		assert.Contains(t, stderr.String(), `unknown unit "parsec"`)
+	})
+}
+
+func TestTruncation(t *testing.T) {
+	run := func(t *testing.T, input string, opts *Options) Stats {
+		dir := t.TempDir()
+		opts.From = filepath.Join(dir, "in.txt")
+		opts.To = filepath.Join(dir, "out.txt")
+		opts.BlockSize = 4
+		opts.ExitNonzeroOnTruncate = true
+		require.NoError(t, os.WriteFile(opts.From, []byte(input), 0o644))
+		stats, err := initFilesAndProcess(opts)
+		require.NoError(t, err)
+		return stats
+	}
+
+	t.Run("ok, limit equal to input size", func(t *testing.T) {
+		assert.False(t, run(t, "0123456789", &Options{Limit: 10}).Truncated)
+	})
+
+	t.Run("ok, limit larger than input size", func(t *testing.T) {
+		assert.False(t, run(t, "0123456789", &Options{Limit: 11}).Truncated)
+	})
+
+	t.Run("ok, no limit", func(t *testing.T) {
+		assert.False(t, run(t, "0123456789", &Options{}).Truncated)
+	})
+
+	t.Run("ok, limit smaller than input size", func(t *testing.T) {
+		assert.True(t, run(t, "0123456789", &Options{Limit: 9}).Truncated)
+	})
+
+	t.Run("ok, limit after offset", func(t *testing.T) {
+		assert.False(t, run(t, "0123456789", &Options{Offset: 4, Limit: 6}).Truncated)
+		assert.True(t, run(t, "0123456789", &Options{Offset: 4, Limit: 5}).Truncated)
+	})
+
+	t.Run("ok, limit of document conversion", func(t *testing.T) {
+		assert.False(t, run(t, `{"a": 1}`, &Options{Conv: "json_minify", Limit: 8}).Truncated)
+		assert.True(t, run(t, `{"a": 1} {}`, &Options{Conv: "json_minify", Limit: 8}).Truncated)
+	})
+}
+
+func TestTruncationIntegration(t *testing.T) {
+	binPath := composeBinaryPath()
+	cmd := exec.Command("go", "build", "-o", binPath, "./")
+	assert.NoError(t, cmd.Run())
+	defer func() {
+		assert.NoError(t, os.Remove(binPath))
+	}()
+
+	t.Run("ok with whole input copied", func(t *testing.T) {
+		cmd = exec.Command(binPath, "-exit-nonzero-on-truncate", "-limit", strconv.Itoa(len(testInput)))
+		cmd.Stdin = strings.NewReader(testInput)
+		stdout := &strings.Builder{}
+		cmd.Stdout = stdout
+
+		err := cmd.Run()
+
+		assert.NoError(t, err)
+		assert.Equal(t, testInput, stdout.String())
+	})
+
+	t.Run("ok with truncation reported by summary", func(t *testing.T) {
+		cmd = exec.Command(binPath, "-limit", "10", "-summary-format", "truncated={{.Truncated}}")
+		cmd.Stdin = strings.NewReader(testInput)
+		stderr := &strings.Builder{}
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "truncated=true\n", stderr.String())
+	})
+
+	t.Run("error with truncated input", func(t *testing.T) {
+		cmd = exec.Command(binPath, "-exit-nonzero-on-truncate", "-limit", "10")
+		cmd.Stdin = strings.NewReader(testInput)
+		stdout := &strings.Builder{}
+		cmd.Stdout = stdout
+		stderr := &strings.Builder{}
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+
+		var exitErr *exec.ExitError
+		require.True(t, errors.As(err, &exitErr), err)
+		assert.Equal(t, exitTruncated, exitErr.ExitCode())
+		assert.Contains(t, stderr.String(), "input is truncated by -limit")
+		assert.Equal(t, testInput[:10], stdout.String())
+	})
+}
+
+// limitReference converts the first limit bytes of input like process does, incomplete runes are copied as is
+func limitReference(input string, limit int, conv []ConvOption) string {
+	if limit > 0 && limit < len(input) {
+		input = input[:limit]
+	}
+	var out []byte
+	for len(input) > 0 {
+		r, size := utf8.DecodeRuneInString(input)
+		switch {
+		case r == utf8.RuneError && size == 1:
+			out = append(out, input[0])
+		case hasConv(conv, UpperCase):
+			out = utf8.AppendRune(out, unicode.ToUpper(r))
+		case hasConv(conv, LowerCase):
+			out = utf8.AppendRune(out, unicode.ToLower(r))
+		default:
+			out = utf8.AppendRune(out, r)
+		}
+		input = input[size:]
+	}
+	if hasConv(conv, TrimSpaces) {
+		out = bytes.TrimFunc(out, unicode.IsSpace)
+	}
+	return string(out)
+}
+
+func TestLimit(t *testing.T) {
+	// 8 bytes with two-byte runes, so small limits cut runes in the middle
+	const input = " Bé ж "
+	require.Len(t, input, 8)
+	convs := []string{"", "lower_case", "upper_case", "trim_spaces", "lower_case,trim_spaces", "upper_case,trim_spaces"}
+
+	for _, conv := range convs {
+		parsed, err := parseConv(conv)
+		require.NoError(t, err)
+		for _, blockSize := range []uint{1, 3, 8, 1000} {
+			for limit := uint(0); limit <= 10; limit++ {
+				t.Run(fmt.Sprintf("ok, conv %q, block size %d, limit %d", conv, blockSize, limit), func(t *testing.T) {
+					reader := strings.NewReader(input)
+					out := &bytes.Buffer{}
+
+					_, err := process(reader, out, &Options{Conv: conv, BlockSize: blockSize, Limit: limit})
+
+					require.NoError(t, err)
+					consumed := len(input) - reader.Len()
+					if limit > 0 {
+						assert.LessOrEqual(t, consumed, int(limit))
+					}
+					expected := limitReference(input, int(limit), parsed)
+					assert.Equal(t, expected, out.String())
+					assert.Len(t, out.Bytes(), len(expected))
+				})
+			}
+		}
+	}
+
+	t.Run("ok, limit counts bytes after offset", func(t *testing.T) {
+		dir := t.TempDir()
+		opts := &Options{From: filepath.Join(dir, "in.txt"), To: filepath.Join(dir, "out.txt"), BlockSize: 1000, Offset: 2, Limit: 2}
+		require.NoError(t, os.WriteFile(opts.From, []byte(input), 0o644))
+
+		stats, err := initFilesAndProcess(opts)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), stats.BytesRead)
+		out, err := os.ReadFile(opts.To)
+		require.NoError(t, err)
+		assert.Equal(t, "é", string(out))
+	})
+
+	t.Run("ok, block size overflowing with limit", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		_, err := process(strings.NewReader(input), out, &Options{BlockSize: ^uint(0), Limit: 3})
+
+		assert.NoError(t, err)
+		assert.Equal(t, input[:3], out.String())
+	})
+}
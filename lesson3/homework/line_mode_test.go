@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineTrimmer(t *testing.T) {
+	var trimmer lineTrimmer
+
+	out, written, dropped := trimmer.Trim([]byte("  a b "), false)
+	assert.Empty(t, out)
+	assert.Zero(t, written)
+	assert.Zero(t, dropped)
+
+	out, written, dropped = trimmer.Trim([]byte(" \n\xff Ш \r\n  "), true)
+	assert.Equal(t, "a b\n\xff Ш\r\n", string(out))
+	assert.Equal(t, 3+1+2+2, written)
+	assert.Equal(t, 4+1+2, dropped)
+}
+
+func TestLineMode(t *testing.T) {
+	input, err := os.ReadFile("testdata/lines/indented.txt")
+	require.NoError(t, err)
+	lines, err := os.ReadFile("testdata/lines/indented.lines.txt")
+	require.NoError(t, err)
+	trimmed, err := os.ReadFile("testdata/lines/indented.trim.txt")
+	require.NoError(t, err)
+	run := func(opts *Options) string {
+		out := &bytes.Buffer{}
+		_, err := process(bytes.NewReader(input), out, opts)
+		require.NoError(t, err)
+		return out.String()
+	}
+
+	for _, blockSize := range []uint{1, 3, 1000} {
+		t.Run("ok, trim every line", func(t *testing.T) {
+			assert.Equal(t, string(lines), run(&Options{BlockSize: blockSize, Conv: "trim_spaces", LineMode: true}), "block size %d", blockSize)
+		})
+
+		t.Run("ok, trim the whole text without line mode", func(t *testing.T) {
+			assert.Equal(t, string(trimmed), run(&Options{BlockSize: blockSize, Conv: "trim_spaces"}), "block size %d", blockSize)
+		})
+	}
+
+	t.Run("ok, line mode with case conversion and bom", func(t *testing.T) {
+		out := run(&Options{BlockSize: 4, Conv: "upper_case,trim_spaces", LineMode: true, AddBOM: true})
+
+		assert.Equal(t, "\uFEFF"+strings.ToUpper(string(lines)), out)
+	})
+
+	t.Run("ok, report counts dropped runes of lines", func(t *testing.T) {
+		report := &bytes.Buffer{}
+		out := &bytes.Buffer{}
+
+		_, err := process(bytes.NewReader(input), out, &Options{BlockSize: 5, Conv: "trim_spaces", LineMode: true, blocksReport: report})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2+4+2+3+3, sum(reportColumn(t, report.Bytes(), "runes_dropped")))
+		assert.Equal(t, len([]rune(out.String())), sum(reportColumn(t, report.Bytes(), "runes_converted")))
+	})
+}
+
+func TestLineModeValidation(t *testing.T) {
+	assert.EqualError(t, (&Options{LineMode: true}).Validate(), "-line-mode requires trim_spaces in -conv or -then-conv")
+	assert.Error(t, (&Options{LineMode: true, Conv: "upper_case"}).Validate())
+	assert.NoError(t, (&Options{LineMode: true, Conv: "upper_case,trim_spaces"}).Validate())
+	assert.NoError(t, (&Options{LineMode: true, Conv: "json_pretty", ThenConv: "trim_spaces"}).Validate())
+}
+
+func TestLineModeIntegration(t *testing.T) {
+	binPath := composeBinaryPath()
+	cmd := exec.Command("go", "build", "-o", binPath, "./")
+	assert.NoError(t, cmd.Run())
+	defer func() {
+		assert.NoError(t, os.Remove(binPath))
+	}()
+
+	t.Run("ok with trim spaces", func(t *testing.T) {
+		cmd = exec.Command(binPath, "-from", "testdata/lines/indented.txt", "-conv", "trim_spaces", "-line-mode", "-block-size", "2")
+		stdout := &strings.Builder{}
+		cmd.Stdout = stdout
+		stderr := &strings.Builder{}
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+
+		assert.NoError(t, err)
+		assert.Zero(t, stderr.Len(), stderr.String())
+		assert.Equal(t, "\nfirst line\nsecond\tline\r\n\nthird", stdout.String())
+	})
+}
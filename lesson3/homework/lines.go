@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+// lineAssembler collects text split between blocks into complete lines
+type lineAssembler struct {
+	partial []byte
+}
+
+// Append adds converted text of a block and calls fn for every completed line including its line feed.
+// if final is set, the unterminated rest of the input is passed as the last line.
+// line passed to fn is valid only until fn returns
+func (a *lineAssembler) Append(data []byte, final bool, fn func(line []byte)) {
+	for len(data) > 0 {
+		idx := bytes.IndexByte(data, '\n')
+		if idx < 0 {
+			a.partial = append(a.partial, data...)
+			break
+		}
+		line := data[:idx+1]
+		if len(a.partial) > 0 {
+			a.partial = append(a.partial, line...)
+			line = a.partial
+		}
+		fn(line)
+		a.partial = a.partial[:0]
+		data = data[idx+1:]
+	}
+	if final && len(a.partial) > 0 {
+		fn(a.partial)
+		a.partial = a.partial[:0]
+	}
+}
+
+// lineTrimmer removes leading and trailing whitespace of every line, line endings are kept
+type lineTrimmer struct {
+	lines lineAssembler
+}
+
+// Trim returns converted text with trimmed lines, written and dropped counts of runes.
+// invalid utf-8 bytes are copied as is and aren't counted.
+// incomplete last line is held back until it's completed or final is set
+func (t *lineTrimmer) Trim(data []byte, final bool) (result []byte, written int, dropped int) {
+	t.lines.Append(data, final, func(line []byte) {
+		ending := line[len(line)-len(lineEnding(line)):]
+		text := line[:len(line)-len(ending)]
+		trimmed := bytes.TrimFunc(text, unicode.IsSpace)
+		dropped += utf8.RuneCount(text) - utf8.RuneCount(trimmed)
+		written += validRuneCount(trimmed) + len(ending)
+		result = append(result, trimmed...)
+		result = append(result, ending...)
+	})
+	return result, written, dropped
+}
+
+// lineEnding returns \r\n or \n ending the line, empty for the last unterminated line
+func lineEnding(line []byte) []byte {
+	if bytes.HasSuffix(line, []byte("\r\n")) {
+		return line[len(line)-2:]
+	}
+	if bytes.HasSuffix(line, []byte("\n")) {
+		return line[len(line)-1:]
+	}
+	return nil
+}
+
+func validRuneCount(data []byte) int {
+	count := 0
+	for len(data) > 0 {
+		r, size := utf8.DecodeRune(data)
+		if r != utf8.RuneError || size > 1 {
+			count++
+		}
+		data = data[size:]
+	}
+	return count
+}
+
+// validateLineMode rejects -line-mode which changes nothing, since only trim_spaces of either pass works per line
+func (o *Options) validateLineMode() error {
+	if !o.LineMode {
+		return nil
+	}
+	conv, _ := o.ParseConv()
+	thenConv, _ := o.ParseThenConv()
+	if !hasConv(conv, TrimSpaces) && !hasConv(thenConv, TrimSpaces) {
+		return fmt.Errorf("-line-mode requires trim_spaces in -conv or -then-conv")
+	}
+	return nil
+}
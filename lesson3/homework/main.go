@@ -1,13 +1,18 @@
 package main
 
 import (
+	"encoding/hex"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
 	"strings"
+	"time"
 	"unicode"
 	"unicode/utf8"
+
+	"lecture03_homework/internal/sizeflag"
 )
 
 type Options struct {
@@ -15,8 +20,65 @@ type Options struct {
 	To        string
 	Offset    int64
 	Limit     uint
+	Ranges    string
 	BlockSize uint
 	Conv      string
+	ThenConv  string
+	LineMode  bool
+	Force     bool
+	Yes       bool
+	StdinOK   bool
+
+	InputEncoding  string
+	OutputEncoding string
+	EncodingErrors EncodingErrors
+	BOM            bool
+	StripBOM       bool
+	AddBOM         bool
+
+	Redact     stringsFlag
+	RedactWith string
+
+	CSVColumns   string
+	CSVDelimiter string
+	CSVMissing   CSVMissing
+	CSVHeader    bool
+
+	BlocksReport string
+	// blocksReport receives -blocks-report rows written by process
+	blocksReport io.Writer
+
+	MaxOutputSize int64
+
+	Check string
+	Hash  string
+
+	OnConvError   ConvErrorPolicy
+	MaxConvErrors uint
+
+	SummaryFormat string
+
+	ExitNonzeroOnTruncate bool
+}
+
+// Stats describes the result of a copy
+type Stats struct {
+	// Redactions is a count of matches masked by -redact
+	Redactions int
+	// ConvErrors is a count of invalid units skipped or replaced by -on-conv-error
+	ConvErrors int
+	// ThenConvErrors is like ConvErrors for the second pass of -then-conv
+	ThenConvErrors int
+	// BytesRead and BytesWritten count bytes of the raw input and output
+	BytesRead    int64
+	BytesWritten int64
+	// Blocks counts blocks read by process
+	Blocks int
+	// Digest is the hex digest of the output, it's computed only with -hash
+	Digest string
+	// Truncated reports that -limit stopped the copy before the end of input,
+	// it's detected only if needed by -exit-nonzero-on-truncate or -summary-format
+	Truncated bool
 }
 
 type ConvOption string
@@ -31,15 +93,28 @@ var ConvMapper = map[string]ConvOption{
 	"upper_case":  UpperCase,
 	"lower_case":  LowerCase,
 	"trim_spaces": TrimSpaces,
+	"json_pretty": JSONPretty,
+	"json_minify": JSONMinify,
+	"strip_html":  StripHTML,
 }
 
 func (o *Options) ParseConv() ([]ConvOption, error) {
+	return parseConv(o.Conv)
+}
+
+// ParseThenConv parses conversions of the second pass
+func (o *Options) ParseThenConv() ([]ConvOption, error) {
+	return parseConv(o.ThenConv)
+}
+
+func parseConv(list string) ([]ConvOption, error) {
 	result := make([]ConvOption, 0, 2)
 	gotCase := false
-	if o.Conv == "" {
+	gotDocument := false
+	if list == "" {
 		return result, nil
 	}
-	for _, s := range strings.Split(o.Conv, ",") {
+	for _, s := range strings.Split(list, ",") {
 		parsed, ok := ConvMapper[s]
 		if !ok {
 			return nil, fmt.Errorf("got unknow options while parse -conv: %s", s)
@@ -50,24 +125,45 @@ func (o *Options) ParseConv() ([]ConvOption, error) {
 			}
 			gotCase = true
 		}
+		if parsed == JSONPretty || parsed == JSONMinify || parsed == StripHTML {
+			if gotDocument {
+				return nil, fmt.Errorf("error while parse conv: only one of json_pretty, json_minify and strip_html can be used")
+			}
+			gotDocument = true
+		}
 		result = append(result, parsed)
 	}
 	return result, nil
 }
 
+func hasConv(conv []ConvOption, option ConvOption) bool {
+	for _, c := range conv {
+		if c == option {
+			return true
+		}
+	}
+	return false
+}
+
+// isSpecialFile reports whether the file is a named pipe, a socket or a device.
+// such files have no size to check offset against and writing to them overwrites nothing
+func isSpecialFile(mode os.FileMode) bool {
+	return mode&(os.ModeNamedPipe|os.ModeSocket|os.ModeDevice|os.ModeCharDevice) != 0
+}
+
 func (o *Options) Validate() error {
 	if o.From != "" {
 		stat, err := os.Stat(o.From)
 		if err != nil {
 			return err
 		}
-		if o.Offset > stat.Size() {
+		if !isSpecialFile(stat.Mode()) && o.Offset > stat.Size() {
 			return fmt.Errorf("provided offset is bigger then file size : %d > %d", o.Offset, stat.Size())
 		}
 	}
-	if o.To != "" {
-		_, err := os.Stat(o.To)
-		if !os.IsNotExist(err) {
+	if o.To != "" && !o.Force {
+		stat, err := os.Stat(o.To)
+		if !os.IsNotExist(err) && (err != nil || !isSpecialFile(stat.Mode())) {
 			return fmt.Errorf("output %s file already exists", o.To)
 		}
 	}
@@ -77,9 +173,54 @@ func (o *Options) Validate() error {
 			return err
 		}
 	}
+	if _, err := lookupEncoding(o.InputEncoding); err != nil {
+		return fmt.Errorf("invalid -input-encoding: %v", err)
+	}
+	if _, err := lookupEncoding(o.OutputEncoding); err != nil {
+		return fmt.Errorf("invalid -output-encoding: %v", err)
+	}
+	if o.BOM && !isUTF16(o.OutputEncoding) {
+		return fmt.Errorf("-bom is supported only for utf-16 output")
+	}
+	if o.AddBOM && !strings.EqualFold(o.OutputEncoding, DefaultEncoding) && o.OutputEncoding != "" {
+		return fmt.Errorf("-add-bom is supported only for utf-8 output, use -bom for utf-16")
+	}
+	if o.EncodingErrors != "" && o.EncodingErrors != EncodingErrorsReplace && o.EncodingErrors != EncodingErrorsStrict {
+		return fmt.Errorf("unknown -encoding-errors policy %s, available: %s, %s", o.EncodingErrors, EncodingErrorsReplace, EncodingErrorsStrict)
+	}
+	if _, err := compileRedactPatterns(o.Redact); err != nil {
+		return err
+	}
+	if err := o.validateCSV(); err != nil {
+		return err
+	}
+	if err := o.validateBlocksReport(); err != nil {
+		return err
+	}
+	if err := o.validateRanges(); err != nil {
+		return err
+	}
+	if err := o.validateCheck(); err != nil {
+		return err
+	}
+	if err := o.validateConvErrors(); err != nil {
+		return err
+	}
+	if err := o.validateThenConv(); err != nil {
+		return err
+	}
+	if err := o.validateLineMode(); err != nil {
+		return err
+	}
+	if _, err := o.summaryTemplate(); err != nil {
+		return err
+	}
 	if o.Offset < 0 {
 		return fmt.Errorf("offset can't be negative")
 	}
+	if o.MaxOutputSize < 0 {
+		return fmt.Errorf("-max-output-size can't be negative")
+	}
 	return nil
 }
 
@@ -88,9 +229,36 @@ func ParseFlags() (*Options, error) {
 	flag.StringVar(&opts.From, "from", "", "file to read. by default - stdin")
 	flag.StringVar(&opts.To, "to", "", "file to write. by default - stdout")
 	flag.Int64Var(&opts.Offset, "offset", 0, "offset bytes in input file. by default - 0")
-	flag.UintVar(&opts.Limit, "limit", 0, "offset bytes in input file. read all file if zero. by default - 0")
+	flag.UintVar(&opts.Limit, "limit", 0, "max bytes to read from input after offset. read all file if zero. by default - 0")
+	flag.StringVar(&opts.Ranges, "ranges", "", "comma separated inclusive byte ranges of input to copy instead of -offset and -limit, e.g. 0-99,500-599,1000-")
 	flag.UintVar(&opts.BlockSize, "block-size", 1000, "read and write blocks bytes length. by default - 1000")
-	flag.StringVar(&opts.Conv, "conv", "", "operation on text before write. available options: lower_case, upper_case, trim_spaces")
+	flag.StringVar(&opts.Conv, "conv", "", "operation on text before write. available options: lower_case, upper_case, trim_spaces, json_pretty, json_minify, strip_html")
+	flag.StringVar(&opts.ThenConv, "then-conv", "", "conversions of the second pass over the output of -conv, same options as -conv")
+	flag.BoolVar(&opts.LineMode, "line-mode", false, "apply trim_spaces to every line instead of the whole text. by default - false")
+	flag.StringVar((*string)(&opts.OnConvError), "on-conv-error", string(ConvErrorAbort), "policy for invalid escapes of json conversions. available options: abort, skip, replace")
+	flag.UintVar(&opts.MaxConvErrors, "max-conv-errors", 0, "abort the copy after more conversion errors are skipped or replaced. unlimited if zero. by default - 0")
+	flag.BoolVar(&opts.Force, "force", false, "overwrite output file if it already exists. by default - false")
+	flag.BoolVar(&opts.Yes, "yes", false, "don't ask for confirmation before overwriting output file. by default - false")
+	encodings := strings.Join(supportedEncodings(), ", ")
+	flag.StringVar(&opts.InputEncoding, "input-encoding", DefaultEncoding, "encoding of input. available options: "+encodings)
+	flag.StringVar(&opts.OutputEncoding, "output-encoding", DefaultEncoding, "encoding of output. available options: "+encodings)
+	flag.StringVar((*string)(&opts.EncodingErrors), "encoding-errors", string(EncodingErrorsReplace), "policy for undecodable input and unencodable output. available options: replace, strict")
+	flag.BoolVar(&opts.BOM, "bom", false, "start utf-16 output with byte order mark. by default - false")
+	flag.BoolVar(&opts.StripBOM, "strip-bom", false, "drop utf-8 byte order mark from the beginning of input. by default - false")
+	flag.BoolVar(&opts.AddBOM, "add-bom", false, "start utf-8 output with byte order mark. by default - false")
+	flag.Var(&opts.Redact, "redact", "regular expression to mask in output, matched line by line. can be repeated")
+	flag.StringVar(&opts.RedactWith, "redact-with", DefaultRedactMask, "replacement for -redact matches. by default - "+DefaultRedactMask)
+	flag.StringVar(&opts.CSVColumns, "csv-columns", "", "parse input as csv and write only listed columns in the given order, e.g. 2,0,5")
+	flag.StringVar(&opts.CSVDelimiter, "csv-delimiter", ",", "field delimiter of csv input and output. by default - ,")
+	flag.StringVar((*string)(&opts.CSVMissing), "csv-missing", string(CSVMissingError), "policy for rows without requested column. available options: error, empty")
+	flag.BoolVar(&opts.CSVHeader, "csv-header", false, "select -csv-columns by names from the first row. by default - false")
+	flag.StringVar(&opts.BlocksReport, "blocks-report", "", "write csv with conversion statistics of every processed block to the file")
+	sizeflag.Var(flag.CommandLine, &opts.MaxOutputSize, "max-output-size", 0, "abort the copy if output grows larger, e.g. 10MiB. unlimited if zero. by default - 0")
+	flag.StringVar(&opts.Check, "check", "", "verify files listed in the checksum file instead of copying, like sha256sum -c")
+	flag.StringVar(&opts.Hash, "hash", "", "print digest of the output to stderr as \"HASH  file\" line, or hash of -check digests inferred from digest length if empty. available options: "+strings.Join(supportedHashes(), ", "))
+	flag.StringVar(&opts.SummaryFormat, "summary-format", "", "text/template of the line printed to stderr after the copy with fields .BytesRead, .BytesWritten, .Duration, .Rate, .Blocks, .Source, .Dest. dd selects the dd-style line. no line if empty")
+	flag.BoolVar(&opts.ExitNonzeroOnTruncate, "exit-nonzero-on-truncate", false, "exit with code 6 if -limit stopped the copy before the end of input. by default - false")
+	flag.BoolVar(&opts.StdinOK, "stdin-ok", false, "don't warn when input is read from terminal. by default - false")
 	flag.Parse()
 	err := opts.Validate()
 	if err != nil {
@@ -99,10 +267,26 @@ func ParseFlags() (*Options, error) {
 	return &opts, nil
 }
 
-func process(reader io.Reader, writer io.Writer, opts *Options) error {
+func process(reader io.Reader, writer io.Writer, opts *Options) (stats Stats, err error) {
 	parsedConv, e := opts.ParseConv()
 	if e != nil {
-		return e
+		return stats, e
+	}
+	redact, e := newRedactor(opts)
+	if e != nil {
+		return stats, e
+	}
+	// report is flushed on error too, so partial runs can be analyzed
+	report := newBlocksReport(opts.blocksReport)
+	defer func() {
+		if flushErr := report.Flush(); err == nil {
+			err = flushErr
+		}
+	}()
+	var pendingSpaces int
+	var trimmer *lineTrimmer
+	if opts.LineMode && hasConv(parsedConv, TrimSpaces) {
+		trimmer = &lineTrimmer{}
 	}
 	// main cycle
 	var prevBuffer []byte
@@ -110,11 +294,18 @@ func process(reader io.Reader, writer io.Writer, opts *Options) error {
 	var isSpaceEnded = false
 	var readingEndSpace = false
 	var totalReadBytes uint = 0
-	for {
+	// BOM is leading only if input is read from the beginning
+	checkBOM := opts.StripBOM && opts.Offset == 0
+	var outputPrefix []byte
+	if opts.AddBOM {
+		outputPrefix = utf8.AppendRune(nil, byteOrderMark)
+	}
+	for blockIndex := 0; ; blockIndex++ {
 		// read block
 		endFile := false
 		maxReadLength := opts.BlockSize
-		if opts.Limit > 0 && totalReadBytes+opts.BlockSize > opts.Limit {
+		// totalReadBytes never exceeds Limit, so the subtraction doesn't overflow unlike the sum with BlockSize
+		if opts.Limit > 0 && opts.Limit-totalReadBytes < opts.BlockSize {
 			maxReadLength = opts.Limit - totalReadBytes
 		}
 		buffer := make([]byte, maxReadLength)
@@ -122,22 +313,37 @@ func process(reader io.Reader, writer io.Writer, opts *Options) error {
 		count, err := reader.Read(buffer)
 		if err != nil {
 			if err != io.EOF {
-				return fmt.Errorf("error while reading: %v", err)
+				return stats, fmt.Errorf("error while reading: %v", err)
 			}
 			endFile = true
 		}
-		// append unparsed rune bytes
+		block := blockStats{Index: blockIndex, Offset: opts.Offset + int64(totalReadBytes), Read: count}
+		stats.Blocks++
+		totalReadBytes += (uint)(count)
+		lastBlock := endFile || (opts.Limit > 0 && totalReadBytes >= opts.Limit)
 
+		// append unparsed rune bytes
 		buffer = append(prevBuffer, buffer[:count]...)
-		var writerBuf []byte
+		writerBuf := outputPrefix
+		outputPrefix = nil
+		prefixLen := len(writerBuf)
 		// decode read bytes per rune
 	SymbolIterate:
 		for len(buffer) > 0 {
-
-			r, size := utf8.DecodeRune(buffer)
-			if len(buffer) < 3 && (size <= 0 || r == utf8.RuneError) {
+			// incomplete rune may be continued in the next block
+			if !lastBlock && !utf8.FullRune(buffer) {
 				break
 			}
+			r, size := utf8.DecodeRune(buffer)
+			invalid := r == utf8.RuneError && size == 1
+			if checkBOM {
+				checkBOM = false
+				if r == byteOrderMark {
+					block.Dropped++
+					buffer = buffer[size:]
+					continue
+				}
+			}
 
 			// handle conv operations
 			for _, conv := range parsedConv {
@@ -147,14 +353,16 @@ func process(reader io.Reader, writer io.Writer, opts *Options) error {
 				if conv == LowerCase {
 					r = unicode.To(unicode.LowerCase, r)
 				}
-				if conv == TrimSpaces {
+				if conv == TrimSpaces && trimmer == nil {
 					if unicode.IsSpace(r) {
 						if !isSpaceEnded {
+							block.Dropped++
 							buffer = buffer[size:]
 							continue SymbolIterate
 						} else {
 							readingEndSpace = true
 							endingSpaceBuffer = append(endingSpaceBuffer, buffer[:size]...)
+							pendingSpaces++
 							buffer = buffer[size:]
 							continue SymbolIterate
 						}
@@ -164,6 +372,8 @@ func process(reader io.Reader, writer io.Writer, opts *Options) error {
 							readingEndSpace = false
 							writerBuf = append(writerBuf, endingSpaceBuffer...)
 							endingSpaceBuffer = nil
+							block.Converted += pendingSpaces
+							pendingSpaces = 0
 						}
 					}
 				}
@@ -173,10 +383,29 @@ func process(reader io.Reader, writer io.Writer, opts *Options) error {
 			} else {
 				writerBuf = utf8.AppendRune(writerBuf, r)
 			}
+			if invalid {
+				block.Invalid++
+			} else if trimmer == nil {
+				block.Converted++
+			}
 			buffer = buffer[size:]
 		}
+		if lastBlock {
+			// trailing spaces are never written
+			block.Dropped += pendingSpaces
+		}
 		// save unparsed rune bytes
 		prevBuffer = buffer
+		if trimmer != nil {
+			trimmed, written, dropped := trimmer.Trim(writerBuf[prefixLen:], lastBlock)
+			writerBuf = append(writerBuf[:prefixLen], trimmed...)
+			block.Converted += written
+			block.Dropped += dropped
+		}
+		if redact != nil {
+			writerBuf = redact.Redact(writerBuf, lastBlock)
+			stats.Redactions = redact.count
+		}
 
 		// write to output
 		for len(writerBuf) > 0 {
@@ -184,31 +413,29 @@ func process(reader io.Reader, writer io.Writer, opts *Options) error {
 			if maxSize > (uint)(len(writerBuf)) {
 				maxSize = (uint)(len(writerBuf))
 			}
-			_, err = writer.Write(writerBuf[:maxSize])
+			written, err := writer.Write(writerBuf[:maxSize])
+			block.Written += written
 			if err != nil {
-				return err
+				report.Add(block)
+				return stats, err
 			}
 			writerBuf = writerBuf[maxSize:]
 		}
-		totalReadBytes += (uint)(count)
-		if endFile || (opts.Limit > 0 && totalReadBytes >= opts.Limit) {
-			_, err = writer.Write(prevBuffer)
-			if err != nil {
-				return err
-			}
+		report.Add(block)
+		if lastBlock {
 			break
 		}
 	}
-	return nil
+	return stats, nil
 }
 
-func initFilesAndProcess(opts *Options) error {
+func initFilesAndProcess(opts *Options) (Stats, error) {
 	// init writer and reader
 	var reader io.Reader
 	if opts.From != "" {
 		readFile, err := os.Open(opts.From)
 		if err != nil {
-			return err
+			return Stats{}, err
 		}
 		defer readFile.Close()
 		reader = readFile
@@ -223,18 +450,51 @@ func initFilesAndProcess(opts *Options) error {
 			0666,
 		)
 		if err != nil {
-			return err
+			return Stats{}, err
 		}
 		defer writeFile.Close()
 		writer = writeFile
 	} else {
 		writer = io.Writer(os.Stdout)
 	}
+	if opts.BlocksReport != "" {
+		reportFile, err := os.OpenFile(
+			opts.BlocksReport,
+			os.O_WRONLY|os.O_TRUNC|os.O_CREATE,
+			0666,
+		)
+		if err != nil {
+			return Stats{}, err
+		}
+		defer reportFile.Close()
+		opts.blocksReport = reportFile
+	}
 	_, err := io.CopyN(io.Discard, reader, opts.Offset)
 	if err != nil {
-		return fmt.Errorf("apply offset failed (possible offset greater then input size): %v", err)
+		return Stats{}, fmt.Errorf("apply offset failed (possible offset greater then input size): %v", err)
+	}
+	if opts.Ranges != "" {
+		ranges, err := parseRanges(opts.Ranges)
+		if err != nil {
+			return Stats{}, err
+		}
+		reader = newRangesReader(reader, ranges)
+	}
+	outputHash := newOutputHash(opts)
+	if outputHash != nil {
+		writer = io.MultiWriter(writer, outputHash)
 	}
-	return process(reader, writer, opts)
+	input := &countingReader{reader: reader}
+	output := &countingWriter{writer: limitOutput(writer, opts.MaxOutputSize)}
+	stats, err := transcodeAndProcess(input, output, opts)
+	stats.BytesRead, stats.BytesWritten = input.count, output.count
+	if outputHash != nil {
+		stats.Digest = hex.EncodeToString(outputHash.Sum(nil))
+	}
+	if err == nil && opts.needsTruncation() && opts.Limit > 0 && stats.BytesRead == int64(opts.Limit) {
+		stats.Truncated = hasMoreInput(reader)
+	}
+	return stats, err
 }
 
 func main() {
@@ -243,9 +503,51 @@ func main() {
 		_, _ = fmt.Fprintln(os.Stderr, "can not parse flags:", err)
 		os.Exit(1)
 	}
-	err = initFilesAndProcess(opts)
+	if opts.Check != "" {
+		if err := runCheck(opts, os.Stdout, os.Stderr); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, "error while checking:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	_ = warnTerminalInput(opts, systemTerminal{}, os.Stderr)
+	overwrite, err := confirmOverwrite(opts, systemTerminal{}, os.Stderr)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "can not confirm overwrite:", err)
+		os.Exit(1)
+	}
+	if !overwrite {
+		_, _ = fmt.Fprintf(os.Stderr, "output %s is left untouched, aborting\n", opts.To)
+		os.Exit(1)
+	}
+	// the template is already checked by Validate
+	summary, _ := opts.summaryTemplate()
+	start := time.Now()
+	stats, err := initFilesAndProcess(opts)
 	if err != nil {
 		_, _ = fmt.Fprintln(os.Stderr, "error while processing:", err)
+		if errors.Is(err, errOutputTooLarge) {
+			os.Exit(exitOutputTooLarge)
+		}
 		os.Exit(1)
 	}
+	if stats.Digest != "" {
+		_ = writeChecksumLine(os.Stderr, opts, stats.Digest)
+	}
+	if len(opts.Redact) > 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "redactions: %d\n", stats.Redactions)
+	}
+	if newConvErrors(opts) != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "conversion errors: %d\n", stats.ConvErrors)
+		if opts.ThenConv != "" {
+			_, _ = fmt.Fprintf(os.Stderr, "then-conv conversion errors: %d\n", stats.ThenConvErrors)
+		}
+	}
+	if summary != nil {
+		_ = writeSummary(os.Stderr, summary, newSummary(opts, stats, time.Since(start)))
+	}
+	if opts.ExitNonzeroOnTruncate && stats.Truncated {
+		_, _ = fmt.Fprintln(os.Stderr, "input is truncated by -limit")
+		os.Exit(exitTruncated)
+	}
 }
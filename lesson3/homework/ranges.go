@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// byteRange is a range of input bytes [Start, End), End is -1 for a range open up to the end of input
+type byteRange struct {
+	Start int64
+	End   int64
+}
+
+// parseRanges parses -ranges like 0-99,500-599,1000-. bounds are inclusive,
+// ranges must be ascending and non-overlapping, only the last range can be open
+func parseRanges(s string) ([]byteRange, error) {
+	parts := strings.Split(s, ",")
+	ranges := make([]byteRange, 0, len(parts))
+	for idx, part := range parts {
+		startText, endText, ok := strings.Cut(part, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid range %q in -ranges, expected start-end or start-", part)
+		}
+		start, err := strconv.ParseInt(startText, 10, 64)
+		if err != nil || start < 0 {
+			return nil, fmt.Errorf("invalid start of range %q in -ranges", part)
+		}
+		r := byteRange{Start: start, End: -1}
+		if endText != "" {
+			end, err := strconv.ParseInt(endText, 10, 64)
+			if err != nil || end < start || end == 1<<63-1 {
+				return nil, fmt.Errorf("invalid end of range %q in -ranges", part)
+			}
+			r.End = end + 1
+		} else if idx != len(parts)-1 {
+			return nil, fmt.Errorf("only the last range in -ranges can be open, got %q", part)
+		}
+		if idx > 0 && ranges[idx-1].End > start {
+			return nil, fmt.Errorf("range %q in -ranges overlaps or precedes the previous one", part)
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+func (o *Options) validateRanges() error {
+	if o.Ranges == "" {
+		return nil
+	}
+	if o.Offset != 0 || o.Limit != 0 {
+		return fmt.Errorf("-ranges can't be used with -offset and -limit")
+	}
+	_, err := parseRanges(o.Ranges)
+	return err
+}
+
+// rangesReader reads byte ranges of the input one after another.
+// gaps are skipped by seeking if the input supports it and are discarded otherwise,
+// ranges past the end of input are empty
+type rangesReader struct {
+	reader io.Reader
+	seeker io.Seeker
+	ranges []byteRange
+	pos    int64
+}
+
+func newRangesReader(reader io.Reader, ranges []byteRange) *rangesReader {
+	result := &rangesReader{reader: reader, ranges: ranges}
+	if seeker, ok := reader.(io.Seeker); ok && !isSpecialReader(reader) {
+		// seeking is probed, since a seeker may be unable to seek
+		if pos, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+			result.seeker = seeker
+			result.pos = pos
+		}
+	}
+	return result
+}
+
+// isSpecialReader reports whether the reader is a pipe, a socket or a device file, which can't skip gaps by seeking
+func isSpecialReader(reader io.Reader) bool {
+	file, ok := reader.(*os.File)
+	if !ok {
+		return false
+	}
+	stat, err := file.Stat()
+	return err == nil && isSpecialFile(stat.Mode())
+}
+
+func (r *rangesReader) Read(p []byte) (int, error) {
+	for len(r.ranges) > 0 {
+		current := r.ranges[0]
+		if current.End >= 0 && r.pos >= current.End {
+			r.ranges = r.ranges[1:]
+			continue
+		}
+		if r.pos < current.Start {
+			if err := r.skip(current.Start - r.pos); err != nil {
+				return 0, err
+			}
+		}
+		if current.End >= 0 && int64(len(p)) > current.End-r.pos {
+			p = p[:current.End-r.pos]
+		}
+		n, err := r.reader.Read(p)
+		r.pos += int64(n)
+		return n, err
+	}
+	return 0, io.EOF
+}
+
+func (r *rangesReader) skip(gap int64) error {
+	if r.seeker != nil {
+		pos, err := r.seeker.Seek(gap, io.SeekCurrent)
+		if err != nil {
+			return fmt.Errorf("seek to the next range failed: %v", err)
+		}
+		r.pos = pos
+		return nil
+	}
+	skipped, err := io.CopyN(io.Discard, r.reader, gap)
+	r.pos += skipped
+	if err == io.EOF {
+		// the rest of ranges is past the end of input
+		r.ranges = nil
+		return io.EOF
+	}
+	return err
+}
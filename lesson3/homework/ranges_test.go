@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamReader hides io.Seeker of the wrapped reader
+type streamReader struct {
+	io.Reader
+}
+
+func TestParseRanges(t *testing.T) {
+	t.Run("ok, closed and open ranges", func(t *testing.T) {
+		ranges, err := parseRanges("0-99,500-599,1000-")
+
+		require.NoError(t, err)
+		assert.Equal(t, []byteRange{{0, 100}, {500, 600}, {1000, -1}}, ranges)
+	})
+
+	t.Run("ok, adjacent single byte ranges", func(t *testing.T) {
+		ranges, err := parseRanges("3-3,4-4")
+
+		require.NoError(t, err)
+		assert.Equal(t, []byteRange{{3, 4}, {4, 5}}, ranges)
+	})
+
+	t.Run("error, invalid ranges", func(t *testing.T) {
+		for _, text := range []string{"", "5", "-5", "a-5", "5-a", "5-4", "0-9,5-20", "10-20,0-5", "0-,10-20", "0-9223372036854775807"} {
+			_, err := parseRanges(text)
+
+			assert.Error(t, err, text)
+		}
+	})
+
+	t.Run("error, ranges with offset or limit", func(t *testing.T) {
+		assert.Error(t, (&Options{Ranges: "0-9", Offset: 1}).Validate())
+		assert.Error(t, (&Options{Ranges: "0-9", Limit: 1}).Validate())
+		assert.Error(t, (&Options{Ranges: "0-9,5-"}).Validate())
+		assert.NoError(t, (&Options{Ranges: "0-9,15-"}).Validate())
+	})
+}
+
+func TestRangesReader(t *testing.T) {
+	const input = "0123456789abcdefghij"
+	readers := map[string]func() io.Reader{
+		"seekable":     func() io.Reader { return strings.NewReader(input) },
+		"non-seekable": func() io.Reader { return streamReader{strings.NewReader(input)} },
+	}
+	for name, newReader := range readers {
+		read := func(t *testing.T, ranges string) string {
+			parsed, err := parseRanges(ranges)
+			require.NoError(t, err)
+			data, err := io.ReadAll(newRangesReader(newReader(), parsed))
+			require.NoError(t, err)
+			return string(data)
+		}
+
+		t.Run("ok, "+name+" ranges", func(t *testing.T) {
+			assert.Equal(t, "0125ab", read(t, "0-2,5-5,10-11"))
+			assert.Equal(t, "0123456789abcdefghij", read(t, "0-9,10-"))
+		})
+
+		t.Run("ok, "+name+" ranges touching EOF", func(t *testing.T) {
+			assert.Equal(t, "0j", read(t, "0-0,19-19"))
+			assert.Equal(t, "ij", read(t, "18-"))
+			assert.Equal(t, "ghij", read(t, "16-30"))
+			assert.Equal(t, "j", read(t, "19-25,26-"))
+		})
+
+		t.Run("ok, "+name+" empty ranges past EOF", func(t *testing.T) {
+			assert.Equal(t, "", read(t, "20-"))
+			assert.Equal(t, "", read(t, "25-30,40-"))
+			assert.Equal(t, "9", read(t, "9-9,20-29"))
+		})
+	}
+
+	t.Run("ok, conversion state persists across ranges", func(t *testing.T) {
+		parsed, err := parseRanges("0-4,7-10")
+		require.NoError(t, err)
+		out := &bytes.Buffer{}
+
+		_, err = process(newRangesReader(strings.NewReader("  a  ..  b "), parsed), out, &Options{BlockSize: 2, Conv: "trim_spaces"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "a    b", out.String())
+	})
+}
+
+func TestRangesIntegration(t *testing.T) {
+	binPath := composeBinaryPath()
+	cmd := exec.Command("go", "build", "-o", binPath, "./")
+	assert.NoError(t, cmd.Run())
+	defer func() {
+		assert.NoError(t, os.Remove(binPath))
+	}()
+	inputPath := filepath.Join(t.TempDir(), "in.txt")
+	require.NoError(t, os.WriteFile(inputPath, []byte(testInput), 0666))
+	expected := testInput[4:20] + testInput[100:200] + testInput[len(testInput)-10:]
+	ranges := "4-19,100-199," + strconv.Itoa(len(testInput)-10) + "-"
+
+	t.Run("ok with file input", func(t *testing.T) {
+		cmd = exec.Command(binPath, "-from", inputPath, "-ranges", ranges, "-block-size", "7")
+		stdout := &strings.Builder{}
+		cmd.Stdout = stdout
+		stderr := &strings.Builder{}
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+
+		assert.NoError(t, err)
+		assert.Zero(t, stderr.Len(), stderr.String())
+		assert.Equal(t, expected, stdout.String())
+	})
+
+	t.Run("ok with stdin input", func(t *testing.T) {
+		cmd = exec.Command(binPath, "-ranges", ranges, "-block-size", "7")
+		cmd.Stdin = strings.NewReader(testInput)
+		stdout := &strings.Builder{}
+		cmd.Stdout = stdout
+		stderr := &strings.Builder{}
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+
+		assert.NoError(t, err)
+		assert.Zero(t, stderr.Len(), stderr.String())
+		assert.Equal(t, expected, stdout.String())
+	})
+
+	t.Run("error with overlapping ranges", func(t *testing.T) {
+		cmd = exec.Command(binPath, "-from", inputPath, "-ranges", "0-10,10-20")
+		stdout := &strings.Builder{}
+		cmd.Stdout = stdout
+		stderr := &strings.Builder{}
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+
+		assert.Error(t, err)
+		assert.Contains(t, stderr.String(), "overlaps")
+		assert.Zero(t, stdout.Len())
+	})
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const DefaultRedactMask = "████"
+
+// stringsFlag collects values of a repeatable flag
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ", ")
+}
+
+func (s *stringsFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+func compileRedactPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	result := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -redact pattern %q: %v", pattern, err)
+		}
+		result = append(result, re)
+	}
+	return result, nil
+}
+
+// redactor masks matches of patterns line by line, so a match never spans a line feed
+type redactor struct {
+	patterns []*regexp.Regexp
+	mask     []byte
+	lines    lineAssembler
+	count    int
+}
+
+func newRedactor(opts *Options) (*redactor, error) {
+	if len(opts.Redact) == 0 {
+		return nil, nil
+	}
+	patterns, err := compileRedactPatterns(opts.Redact)
+	if err != nil {
+		return nil, err
+	}
+	return &redactor{patterns: patterns, mask: []byte(opts.RedactWith)}, nil
+}
+
+// Redact returns converted text with masked matches.
+// incomplete last line is held back until it's completed or final is set
+func (r *redactor) Redact(data []byte, final bool) []byte {
+	var result []byte
+	r.lines.Append(data, final, func(line []byte) {
+		result = r.redactLine(result, line)
+	})
+	return result
+}
+
+// redactLine appends line with masked matches to dst.
+// matches of all patterns are applied left to right, a match overlapping the previous one is skipped
+func (r *redactor) redactLine(dst []byte, line []byte) []byte {
+	text := line
+	if len(text) > 0 && text[len(text)-1] == '\n' {
+		text = text[:len(text)-1]
+	}
+	var matches [][]int
+	for _, re := range r.patterns {
+		matches = append(matches, re.FindAllIndex(text, -1)...)
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i][0] < matches[j][0]
+	})
+	end := 0
+	for _, match := range matches {
+		if match[0] < end || match[0] == match[1] {
+			continue
+		}
+		dst = append(dst, line[end:match[0]]...)
+		dst = append(dst, r.mask...)
+		end = match[1]
+		r.count++
+	}
+	return append(dst, line[end:]...)
+}
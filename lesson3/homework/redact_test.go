@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const redactInput = `order 1: card 4111111111111111, mail john.doe@example.com
+order 2: card 5500 0000 0000 0004 is not a single number
+contacts: a@b.io, c@d.io
+last line card 4000056655665556`
+
+func TestLineAssembler(t *testing.T) {
+	var lines []string
+	collect := func(line []byte) {
+		lines = append(lines, string(line))
+	}
+	var assembler lineAssembler
+
+	assembler.Append([]byte("fir"), false, collect)
+	assembler.Append([]byte("st\nsec"), false, collect)
+	assembler.Append([]byte("ond\n\nthi"), false, collect)
+	assert.Equal(t, []string{"first\n", "second\n", "\n"}, lines)
+
+	assembler.Append([]byte("rd"), true, collect)
+	assert.Equal(t, []string{"first\n", "second\n", "\n", "third"}, lines)
+
+	assembler.Append(nil, true, collect)
+	assert.Len(t, lines, 4)
+}
+
+func TestRedact(t *testing.T) {
+	run := func(input string, opts *Options) (string, Stats) {
+		out := &bytes.Buffer{}
+		stats, err := process(strings.NewReader(input), out, opts)
+		assert.NoError(t, err)
+		return out.String(), stats
+	}
+
+	for _, blockSize := range []uint{1, 7, 1000} {
+		t.Run("ok, credit card numbers and emails", func(t *testing.T) {
+			out, stats := run(redactInput, &Options{
+				BlockSize:  blockSize,
+				Redact:     stringsFlag{`\b\d{16}\b`, `[\w.]+@[\w.]+\.\w+`},
+				RedactWith: DefaultRedactMask,
+			})
+
+			assert.Equal(t, `order 1: card ████, mail ████
+order 2: card 5500 0000 0000 0004 is not a single number
+contacts: ████, ████
+last line card ████`, out, "block size %d", blockSize)
+			assert.Equal(t, 5, stats.Redactions)
+		})
+
+		t.Run("ok, match at the end of input without line feed", func(t *testing.T) {
+			out, stats := run("secret 1234", &Options{BlockSize: blockSize, Redact: stringsFlag{`\d+$`}, RedactWith: "#"})
+
+			assert.Equal(t, "secret #", out, "block size %d", blockSize)
+			assert.Equal(t, 1, stats.Redactions)
+		})
+	}
+
+	t.Run("ok, matches don't span lines", func(t *testing.T) {
+		out, stats := run("12\n34\n", &Options{BlockSize: 1, Redact: stringsFlag{`\d+\s\d+`}, RedactWith: "#"})
+
+		assert.Equal(t, "12\n34\n", out)
+		assert.Zero(t, stats.Redactions)
+	})
+
+	t.Run("ok, overlapping patterns applied left to right", func(t *testing.T) {
+		out, stats := run("abcdef", &Options{BlockSize: 2, Redact: stringsFlag{`cde`, `bcd`, `def`}, RedactWith: "#"})
+
+		assert.Equal(t, "a#ef", out)
+		assert.Equal(t, 1, stats.Redactions)
+	})
+
+	t.Run("ok, redact after conversions", func(t *testing.T) {
+		out, _ := run("  Secret \n", &Options{BlockSize: 3, Conv: "trim_spaces,upper_case", Redact: stringsFlag{`SECRET`}, RedactWith: "*"})
+
+		assert.Equal(t, "*", out)
+	})
+
+	t.Run("error, invalid pattern", func(t *testing.T) {
+		err := (&Options{Redact: stringsFlag{`(`}}).Validate()
+
+		assert.Error(t, err)
+	})
+}
+
+func TestRedactIntegration(t *testing.T) {
+	binPath := composeBinaryPath()
+	cmd := exec.Command("go", "build", "-o", binPath, "./")
+	assert.NoError(t, cmd.Run())
+	defer func() {
+		assert.NoError(t, os.Remove(binPath))
+	}()
+
+	t.Run("ok with repeated redact options and summary", func(t *testing.T) {
+		cmd = exec.Command(binPath, `-redact=\b\d{16}\b`, "-redact", `[\w.]+@[\w.]+\.\w+`, "-redact-with", "XXX")
+		cmd.Stdin = strings.NewReader(redactInput)
+		stdout := &strings.Builder{}
+		cmd.Stdout = stdout
+		stderr := &strings.Builder{}
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "redactions: 5\n", stderr.String())
+		assert.Equal(t, 5, strings.Count(stdout.String(), "XXX"))
+	})
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// blocksReportHeader names columns of -blocks-report
+var blocksReportHeader = []string{"block", "offset", "bytes_read", "bytes_written", "runes_converted", "runes_dropped", "invalid_bytes"}
+
+// blockStats describes conversion of a single block read by process
+type blockStats struct {
+	Index int
+	// Offset and Read are counted in the utf-8 text read by process, Offset includes -offset.
+	// they match the raw input only when it's read as is: -input-encoding decodes the input,
+	// -ranges joins the selected bytes and json conversions and -then-conv reformat it before process
+	Offset int64
+	Read   int
+	// Written counts bytes passed to the output before -output-encoding
+	Written int
+	// Converted counts runes written to the output, Dropped counts runes removed by trim_spaces and -strip-bom
+	Converted int
+	Dropped   int
+	// Invalid counts bytes of invalid utf-8 copied as is
+	Invalid int
+}
+
+func (b blockStats) record() []string {
+	return []string{
+		strconv.Itoa(b.Index),
+		strconv.FormatInt(b.Offset, 10),
+		strconv.Itoa(b.Read),
+		strconv.Itoa(b.Written),
+		strconv.Itoa(b.Converted),
+		strconv.Itoa(b.Dropped),
+		strconv.Itoa(b.Invalid),
+	}
+}
+
+// blocksReport writes a csv row per processed block, nil report writes nothing
+type blocksReport struct {
+	writer *csv.Writer
+}
+
+func newBlocksReport(writer io.Writer) *blocksReport {
+	if writer == nil {
+		return nil
+	}
+	report := &blocksReport{writer: csv.NewWriter(writer)}
+	_ = report.writer.Write(blocksReportHeader)
+	return report
+}
+
+func (r *blocksReport) Add(block blockStats) {
+	if r == nil {
+		return
+	}
+	_ = r.writer.Write(block.record())
+}
+
+// Flush writes buffered rows and returns the first error of writing the report
+func (r *blocksReport) Flush() error {
+	if r == nil {
+		return nil
+	}
+	r.writer.Flush()
+	if err := r.writer.Error(); err != nil {
+		return fmt.Errorf("error while writing blocks report: %v", err)
+	}
+	return nil
+}
+
+func (o *Options) validateBlocksReport() error {
+	if o.BlocksReport == "" {
+		return nil
+	}
+	if o.CSVColumns != "" {
+		return fmt.Errorf("-blocks-report can't be used with -csv-columns")
+	}
+	if o.BlocksReport == o.To || o.BlocksReport == o.From {
+		return fmt.Errorf("-blocks-report must differ from -from and -to")
+	}
+	if !o.Force {
+		if stat, err := os.Stat(o.BlocksReport); !os.IsNotExist(err) && (err != nil || !isSpecialFile(stat.Mode())) {
+			return fmt.Errorf("blocks report %s file already exists", o.BlocksReport)
+		}
+	}
+	return nil
+}
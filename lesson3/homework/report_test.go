@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// paddedSpaces is a count of leading and trailing whitespace runes in testdata/report/padded.txt
+const paddedSpaces = 9
+
+// reportColumn parses the report and returns values of the column
+func reportColumn(t *testing.T, report []byte, column string) []int {
+	records, err := csv.NewReader(bytes.NewReader(report)).ReadAll()
+	require.NoError(t, err)
+	require.NotEmpty(t, records)
+	require.Equal(t, blocksReportHeader, records[0])
+	idx := -1
+	for i, name := range records[0] {
+		if name == column {
+			idx = i
+		}
+	}
+	require.NotEqual(t, -1, idx, column)
+	values := make([]int, 0, len(records)-1)
+	for _, record := range records[1:] {
+		value, err := strconv.Atoi(record[idx])
+		require.NoError(t, err)
+		values = append(values, value)
+	}
+	return values
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+type failingWriter struct {
+	limit int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.limit {
+		n := w.limit
+		w.limit = 0
+		return n, errors.New("disk is full")
+	}
+	w.limit -= len(p)
+	return len(p), nil
+}
+
+func TestBlocksReport(t *testing.T) {
+	input, err := os.ReadFile("testdata/report/padded.txt")
+	require.NoError(t, err)
+
+	for _, blockSize := range []uint{1, 3, 7, 1000} {
+		t.Run("ok, trim_spaces drops whitespace", func(t *testing.T) {
+			report := &bytes.Buffer{}
+			out := &bytes.Buffer{}
+
+			_, err := process(bytes.NewReader(input), out, &Options{BlockSize: blockSize, Conv: "trim_spaces", blocksReport: report})
+
+			require.NoError(t, err)
+			assert.Equal(t, "hello, world\n  Привет　мир", out.String())
+			assert.Equal(t, paddedSpaces, sum(reportColumn(t, report.Bytes(), "runes_dropped")), "block size %d", blockSize)
+			assert.Equal(t, len(input), sum(reportColumn(t, report.Bytes(), "bytes_read")))
+			assert.Equal(t, out.Len(), sum(reportColumn(t, report.Bytes(), "bytes_written")))
+			assert.Equal(t, utf8.RuneCount(out.Bytes()), sum(reportColumn(t, report.Bytes(), "runes_converted")))
+			assert.Zero(t, sum(reportColumn(t, report.Bytes(), "invalid_bytes")))
+		})
+	}
+
+	t.Run("ok, block offsets and invalid bytes", func(t *testing.T) {
+		report := &bytes.Buffer{}
+		out := &bytes.Buffer{}
+
+		_, err := process(strings.NewReader("ab\xffcd\xfe\xfdef"), out, &Options{Offset: 10, BlockSize: 4, blocksReport: report})
+
+		require.NoError(t, err)
+		assert.Equal(t, "ab\xffcd\xfe\xfdef", out.String())
+		assert.Equal(t, `block,offset,bytes_read,bytes_written,runes_converted,runes_dropped,invalid_bytes
+0,10,4,4,3,0,1
+1,14,4,4,2,0,2
+2,18,1,1,1,0,0
+3,19,0,0,0,0,0
+`, report.String())
+	})
+
+	t.Run("ok, offsets of decoded input", func(t *testing.T) {
+		report := &bytes.Buffer{}
+		out := &bytes.Buffer{}
+		// every rune takes two bytes of utf-16 and one byte of the decoded text
+		input := []byte{'a', 0, 'b', 0, 'c', 0, 'd', 0, 'e', 0}
+
+		_, err := transcodeAndProcess(bytes.NewReader(input), out, &Options{InputEncoding: "utf-16le", BlockSize: 2, blocksReport: report})
+
+		require.NoError(t, err)
+		assert.Equal(t, "abcde", out.String())
+		assert.Equal(t, []int{0, 2, 4, 5}, reportColumn(t, report.Bytes(), "offset"))
+		assert.Equal(t, 5, sum(reportColumn(t, report.Bytes(), "bytes_read")))
+	})
+
+	t.Run("ok, partial report on write error", func(t *testing.T) {
+		report := &bytes.Buffer{}
+
+		_, err := process(strings.NewReader("abcdefghij"), &failingWriter{limit: 6}, &Options{BlockSize: 4, blocksReport: report})
+
+		assert.EqualError(t, err, "disk is full")
+		assert.Equal(t, `block,offset,bytes_read,bytes_written,runes_converted,runes_dropped,invalid_bytes
+0,0,4,4,4,0,0
+1,4,4,2,4,0,0
+`, report.String())
+	})
+
+	t.Run("error, report write fails", func(t *testing.T) {
+		_, err := process(strings.NewReader("abc"), &bytes.Buffer{}, &Options{BlockSize: 4, blocksReport: &failingWriter{}})
+
+		assert.EqualError(t, err, "error while writing blocks report: disk is full")
+	})
+}
+
+func TestBlocksReportValidation(t *testing.T) {
+	assert.Error(t, (&Options{BlocksReport: "main.go"}).Validate())
+	assert.Error(t, (&Options{BlocksReport: "report.csv", CSVColumns: "0"}).Validate())
+	assert.Error(t, (&Options{BlocksReport: "out.csv", To: "out.csv"}).Validate())
+	assert.NoError(t, (&Options{BlocksReport: "main.go", Force: true}).Validate())
+}
+
+func TestBlocksReportIntegration(t *testing.T) {
+	binPath := composeBinaryPath()
+	cmd := exec.Command("go", "build", "-o", binPath, "./")
+	assert.NoError(t, cmd.Run())
+	defer func() {
+		assert.NoError(t, os.Remove(binPath))
+	}()
+
+	t.Run("ok with trim spaces", func(t *testing.T) {
+		reportPath := filepath.Join(t.TempDir(), "blocks.csv")
+		cmd = exec.Command(binPath, "-from", "testdata/report/padded.txt", "-conv", "trim_spaces", "-block-size", "5", "-blocks-report", reportPath)
+		stdout := &strings.Builder{}
+		cmd.Stdout = stdout
+		stderr := &strings.Builder{}
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+
+		assert.NoError(t, err)
+		assert.Zero(t, stderr.Len(), stderr.String())
+		assert.Equal(t, "hello, world\n  Привет　мир", stdout.String())
+		report, err := os.ReadFile(reportPath)
+		require.NoError(t, err)
+		assert.Equal(t, paddedSpaces, sum(reportColumn(t, report, "runes_dropped")))
+	})
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// documentStage returns a conversion which parses the document markup and runs before rune conversions,
+// nil if there is no such conversion. errors tolerated by -on-conv-error are counted by convErrs
+func documentStage(conv []ConvOption, blockSize uint, convErrs *convErrors) func(io.Reader, io.Writer) error {
+	for _, c := range conv {
+		switch c {
+		case JSONPretty:
+			return func(reader io.Reader, writer io.Writer) error {
+				return reformatJSON(fixJSONEscapes(blockReader{reader: reader, size: blockSize}, convErrs), writer, true)
+			}
+		case JSONMinify:
+			return func(reader io.Reader, writer io.Writer) error {
+				return reformatJSON(fixJSONEscapes(blockReader{reader: reader, size: blockSize}, convErrs), writer, false)
+			}
+		case StripHTML:
+			return func(reader io.Reader, writer io.Writer) error {
+				return stripHTML(reader, writer, blockSize)
+			}
+		}
+	}
+	return nil
+}
+
+// pipeStage runs stage in background and returns its output.
+// closing returned reader stops the stage
+func pipeStage(reader io.Reader, stage func(io.Reader, io.Writer) error) io.ReadCloser {
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		_ = pipeWriter.CloseWithError(stage(reader, pipeWriter))
+	}()
+	return pipeReader
+}
+
+// validateThenConv checks -then-conv separately from -conv, since every pass has its own order of conversions.
+// a conversion repeated by the second pass is rejected, because it wouldn't change anything
+func (o *Options) validateThenConv() error {
+	if o.ThenConv == "" {
+		return nil
+	}
+	thenConv, err := o.ParseThenConv()
+	if err != nil {
+		return fmt.Errorf("invalid -then-conv: %v", err)
+	}
+	if o.CSVColumns != "" {
+		return fmt.Errorf("-then-conv can't be used with -csv-columns")
+	}
+	conv, err := o.ParseConv()
+	if err != nil {
+		return err
+	}
+	for _, c := range thenConv {
+		if hasConv(conv, c) {
+			return fmt.Errorf("%s in -then-conv is already applied by -conv", c)
+		}
+	}
+	return nil
+}
+
+// firstPassStage returns the first pass of -then-conv, it runs -conv and leaves the output options to the second pass
+func firstPassStage(opts *Options, convErrs *convErrors) func(io.Reader, io.Writer) error {
+	conv, _ := opts.ParseConv()
+	stage := documentStage(conv, opts.BlockSize, convErrs)
+	firstOpts := *opts
+	// -limit is already applied to the raw input by transcodeAndProcess
+	firstOpts.Redact, firstOpts.AddBOM, firstOpts.blocksReport, firstOpts.Limit = nil, false, nil, 0
+	return func(reader io.Reader, writer io.Writer) error {
+		if stage != nil {
+			staged := pipeStage(reader, stage)
+			defer staged.Close()
+			reader = staged
+		}
+		_, err := process(reader, writer, &firstOpts)
+		return err
+	}
+}
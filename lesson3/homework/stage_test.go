@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThenConv(t *testing.T) {
+	run := func(input []byte, opts *Options) (string, Stats, error) {
+		out := &bytes.Buffer{}
+		stats, err := transcodeAndProcess(bytes.NewReader(input), out, opts)
+		return out.String(), stats, err
+	}
+	records, err := os.ReadFile("testdata/json/records.ndjson")
+	require.NoError(t, err)
+	pretty, err := os.ReadFile("testdata/json/records.pretty.ndjson")
+	require.NoError(t, err)
+
+	for _, blockSize := range []uint{1, 16, 1000} {
+		t.Run("ok, document conversion then trim_spaces", func(t *testing.T) {
+			out, _, err := run(records, &Options{Conv: "json_pretty", ThenConv: "trim_spaces", BlockSize: blockSize})
+
+			assert.NoError(t, err)
+			assert.Equal(t, strings.TrimSpace(string(pretty)), out, "block size %d", blockSize)
+		})
+
+		t.Run("ok, document conversion then trim_spaces of lines", func(t *testing.T) {
+			out, _, err := run(records, &Options{Conv: "json_pretty", ThenConv: "trim_spaces,upper_case", LineMode: true, BlockSize: blockSize})
+
+			var expected []string
+			for _, line := range strings.SplitAfter(string(pretty), "\n") {
+				expected = append(expected, strings.ToUpper(strings.TrimLeft(line, " ")))
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, strings.Join(expected, ""), out, "block size %d", blockSize)
+		})
+	}
+
+	t.Run("ok, rune conversion then document conversion", func(t *testing.T) {
+		out, _, err := run([]byte(`  {"KEY": "Value"}  `), &Options{Conv: "lower_case,trim_spaces", ThenConv: "json_pretty", BlockSize: 4})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "{\n  \"key\": \"value\"\n}\n", out)
+	})
+
+	t.Run("ok, limit and output options apply once", func(t *testing.T) {
+		out, stats, err := run([]byte("  secret data  tail"), &Options{
+			Conv: "trim_spaces", ThenConv: "upper_case", Limit: 15, BlockSize: 4,
+			AddBOM: true, Redact: stringsFlag{"SECRET"}, RedactWith: "***",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "\uFEFF*** DATA", out)
+		assert.Equal(t, 1, stats.Redactions)
+	})
+
+	t.Run("ok, limit applies to the input of the first pass", func(t *testing.T) {
+		input := `{"key": ["a", "b"]}  tail`
+		out, _, err := run([]byte(input), &Options{Conv: "json_pretty", ThenConv: "upper_case", Limit: 19, BlockSize: 4})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "{\n  \"KEY\": [\n    \"A\",\n    \"B\"\n  ]\n}\n", out)
+	})
+
+	t.Run("ok, conversion errors of both passes", func(t *testing.T) {
+		_, stats, err := run([]byte(`["\q", "\z"]`), &Options{Conv: "json_minify", ThenConv: "upper_case", BlockSize: 4, OnConvError: ConvErrorSkip})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, stats.ConvErrors)
+		assert.Zero(t, stats.ThenConvErrors)
+
+		out, stats, err := run([]byte(`["\q"]`), &Options{Conv: "upper_case", ThenConv: "json_minify", BlockSize: 4, OnConvError: ConvErrorReplace})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "[\"\uFFFD\"]\n", out)
+		assert.Zero(t, stats.ConvErrors)
+		assert.Equal(t, 1, stats.ThenConvErrors)
+	})
+
+	t.Run("error, second pass fails", func(t *testing.T) {
+		_, _, err := run([]byte(`{"key": }`), &Options{Conv: "upper_case", ThenConv: "json_minify", BlockSize: 4})
+
+		assert.ErrorContains(t, err, "invalid JSON at offset")
+	})
+
+	t.Run("error, invalid conversion sets", func(t *testing.T) {
+		for _, opts := range []*Options{
+			{ThenConv: "unknown"},
+			{ThenConv: "upper_case,lower_case"},
+			{Conv: "trim_spaces", ThenConv: "upper_case,trim_spaces"},
+			{Conv: "json_minify", ThenConv: "json_minify"},
+			{ThenConv: "upper_case", CSVColumns: "0"},
+		} {
+			assert.Error(t, opts.Validate(), "%+v", opts)
+		}
+		assert.NoError(t, (&Options{Conv: "lower_case", ThenConv: "upper_case"}).Validate())
+	})
+}
+
+func TestThenConvIntegration(t *testing.T) {
+	binPath := composeBinaryPath()
+	cmd := exec.Command("go", "build", "-o", binPath, "./")
+	assert.NoError(t, cmd.Run())
+	defer func() {
+		assert.NoError(t, os.Remove(binPath))
+	}()
+
+	t.Run("ok with two passes", func(t *testing.T) {
+		cmd = exec.Command(binPath, "-conv", "strip_html", "-then-conv", "upper_case", "-block-size", "5")
+		cmd.Stdin = strings.NewReader("<p>  Hello, <b>world</b>!  </p>\n")
+		stdout := &strings.Builder{}
+		cmd.Stdout = stdout
+		stderr := &strings.Builder{}
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+
+		assert.NoError(t, err)
+		assert.Zero(t, stderr.Len(), stderr.String())
+		assert.Equal(t, "HELLO, WORLD!", stdout.String())
+	})
+}
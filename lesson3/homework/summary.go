@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DDSummaryFormat is the -summary-format selecting DefaultSummaryFormat
+const DDSummaryFormat = "dd"
+
+// DefaultSummaryFormat renders the completion line like dd does
+const DefaultSummaryFormat = `{{.BytesWritten}} bytes copied, {{printf "%.6f" .Duration.Seconds}} s, {{printf "%.0f" .Rate}} B/s`
+
+// Summary holds fields of -summary-format
+type Summary struct {
+	// BytesRead counts bytes of the raw input after -offset
+	BytesRead int64
+	// BytesWritten counts bytes of the raw output
+	BytesWritten int64
+	Duration     time.Duration
+	// Rate is BytesWritten per second
+	Rate float64
+	// Blocks counts blocks read by the copy
+	Blocks int
+	// Source and Dest are paths of input and output or stdin and stdout
+	Source string
+	Dest   string
+	// Truncated reports that -limit stopped the copy before the end of input
+	Truncated bool
+}
+
+func newSummary(opts *Options, stats Stats, duration time.Duration) Summary {
+	summary := Summary{
+		BytesRead:    stats.BytesRead,
+		BytesWritten: stats.BytesWritten,
+		Duration:     duration,
+		Blocks:       stats.Blocks,
+		Source:       opts.From,
+		Dest:         opts.To,
+		Truncated:    stats.Truncated,
+	}
+	if duration > 0 {
+		summary.Rate = float64(stats.BytesWritten) / duration.Seconds()
+	}
+	if summary.Source == "" {
+		summary.Source = "stdin"
+	}
+	if summary.Dest == "" {
+		summary.Dest = "stdout"
+	}
+	return summary
+}
+
+// summaryTemplate parses -summary-format, nil template means no summary.
+// the template is rendered once with empty fields, so unknown fields are reported before the copy
+func (o *Options) summaryTemplate() (*template.Template, error) {
+	format := o.SummaryFormat
+	switch format {
+	case "":
+		return nil, nil
+	case DDSummaryFormat:
+		format = DefaultSummaryFormat
+	}
+	tmpl, err := template.New("summary").Option("missingkey=error").Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -summary-format: %v", err)
+	}
+	if err := tmpl.Execute(io.Discard, Summary{}); err != nil {
+		return nil, fmt.Errorf("invalid -summary-format: %v", err)
+	}
+	return tmpl, nil
+}
+
+// writeSummary renders the summary line, a line feed is added if the template has none
+func writeSummary(writer io.Writer, tmpl *template.Template, summary Summary) error {
+	var line strings.Builder
+	if err := tmpl.Execute(&line, summary); err != nil {
+		return err
+	}
+	if !strings.HasSuffix(line.String(), "\n") {
+		line.WriteString("\n")
+	}
+	_, err := io.WriteString(writer, line.String())
+	return err
+}
+
+// countingWriter counts bytes written to the underlying writer
+type countingWriter struct {
+	writer io.Writer
+	count  int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.writer.Write(p)
+	c.count += int64(n)
+	return n, err
+}
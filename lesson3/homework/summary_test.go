@@ -0,0 +1,117 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummaryFormat(t *testing.T) {
+	summary := Summary{BytesRead: 2048, BytesWritten: 1000, Duration: 2 * time.Second, Rate: 500, Blocks: 3, Source: "in.txt", Dest: "stdout"}
+	render := func(t *testing.T, format string) string {
+		tmpl, err := (&Options{SummaryFormat: format}).summaryTemplate()
+		require.NoError(t, err)
+		var line strings.Builder
+		require.NoError(t, writeSummary(&line, tmpl, summary))
+		return line.String()
+	}
+
+	t.Run("ok, custom template", func(t *testing.T) {
+		line := render(t, "{{.Source}} -> {{.Dest}}: {{.BytesRead}}/{{.BytesWritten}} bytes in {{.Blocks}} blocks, {{.Duration}}, {{.Rate}} B/s")
+
+		assert.Equal(t, "in.txt -> stdout: 2048/1000 bytes in 3 blocks, 2s, 500 B/s\n", line)
+	})
+
+	t.Run("ok, dd template", func(t *testing.T) {
+		assert.Equal(t, "1000 bytes copied, 2.000000 s, 500 B/s\n", render(t, DDSummaryFormat))
+	})
+
+	t.Run("ok, line feed is kept", func(t *testing.T) {
+		assert.Equal(t, "3\n", render(t, "{{.Blocks}}\n"))
+	})
+
+	t.Run("ok, no summary by default", func(t *testing.T) {
+		tmpl, err := (&Options{}).summaryTemplate()
+
+		assert.NoError(t, err)
+		assert.Nil(t, tmpl)
+	})
+
+	t.Run("ok, summary of stats", func(t *testing.T) {
+		s := newSummary(&Options{To: "out.txt"}, Stats{BytesRead: 10, BytesWritten: 8, Blocks: 2}, 4*time.Second)
+
+		assert.Equal(t, Summary{BytesRead: 10, BytesWritten: 8, Duration: 4 * time.Second, Rate: 2, Blocks: 2, Source: "stdin", Dest: "out.txt"}, s)
+	})
+
+	t.Run("error, bad templates fail in Validate", func(t *testing.T) {
+		for _, format := range []string{"{{.BytesRead", "{{.Unknown}}", "{{printf}}"} {
+			err := (&Options{SummaryFormat: format}).Validate()
+
+			assert.ErrorContains(t, err, "invalid -summary-format", format)
+		}
+	})
+}
+
+func TestSummaryFormatIntegration(t *testing.T) {
+	binPath := composeBinaryPath()
+	cmd := exec.Command("go", "build", "-o", binPath, "./")
+	assert.NoError(t, cmd.Run())
+	defer func() {
+		assert.NoError(t, os.Remove(binPath))
+	}()
+
+	t.Run("ok with custom template", func(t *testing.T) {
+		cmd = exec.Command(binPath, "-offset", "4", "-block-size", "100", "-conv", "trim_spaces",
+			"-summary-format", "{{.BytesRead}} {{.BytesWritten}} {{.Blocks}} {{.Source}} {{.Dest}}")
+		cmd.Stdin = strings.NewReader(testInput)
+		stdout := &strings.Builder{}
+		cmd.Stdout = stdout
+		stderr := &strings.Builder{}
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+
+		assert.NoError(t, err)
+		trimmed := strings.TrimSpace(testInput[4:])
+		assert.Equal(t, trimmed, stdout.String())
+		blocks := (len(testInput)-4)/100 + 2
+		assert.Equal(t, strings.Join([]string{
+			strconv.Itoa(len(testInput) - 4), strconv.Itoa(len(trimmed)), strconv.Itoa(blocks), "stdin", "stdout",
+		}, " ")+"\n", stderr.String())
+	})
+
+	t.Run("ok with dd template", func(t *testing.T) {
+		outPath := filepath.Join(t.TempDir(), "out.txt")
+		cmd = exec.Command(binPath, "-to", outPath, "-summary-format", "dd")
+		cmd.Stdin = strings.NewReader(testInput)
+		stderr := &strings.Builder{}
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+
+		assert.NoError(t, err)
+		assert.Regexp(t, regexp.MustCompile(`^`+strconv.Itoa(len(testInput))+` bytes copied, \d+\.\d{6} s, \d+ B/s\n$`), stderr.String())
+	})
+
+	t.Run("error with bad template before copy", func(t *testing.T) {
+		outPath := filepath.Join(t.TempDir(), "out.txt")
+		cmd = exec.Command(binPath, "-to", outPath, "-summary-format", "{{.Bytes}}")
+		cmd.Stdin = strings.NewReader(testInput)
+		stderr := &strings.Builder{}
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+
+		assert.Error(t, err)
+		assert.Contains(t, stderr.String(), "invalid -summary-format")
+		assert.NoFileExists(t, outPath)
+	})
+}
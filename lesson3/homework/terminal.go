@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Terminal abstracts interaction with the user terminal, so prompts can be simulated in tests
+type Terminal interface {
+	// IsTerminal reports whether the file is attached to a terminal
+	IsTerminal(file *os.File) bool
+	// ReadAnswer reads a single line typed by the user
+	ReadAnswer() (string, error)
+}
+
+type systemTerminal struct{}
+
+func (systemTerminal) IsTerminal(file *os.File) bool {
+	return term.IsTerminal(int(file.Fd()))
+}
+
+// ReadAnswer reads from /dev/tty, because stdin may be the data source
+func (systemTerminal) ReadAnswer() (string, error) {
+	tty, err := os.Open("/dev/tty")
+	if err != nil {
+		return "", err
+	}
+	defer tty.Close()
+	line, err := bufio.NewReader(tty).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return line, nil
+}
+
+// confirmOverwrite checks whether the output file may be overwritten.
+// the user is asked only if the file exists and isn't a device or a pipe, -force is set without -yes and stdout is a terminal
+func confirmOverwrite(opts *Options, terminal Terminal, stderr io.Writer) (bool, error) {
+	if opts.To == "" || !opts.Force || opts.Yes {
+		return true, nil
+	}
+	if stat, err := os.Stat(opts.To); os.IsNotExist(err) || (err == nil && isSpecialFile(stat.Mode())) {
+		return true, nil
+	}
+	if !terminal.IsTerminal(os.Stdout) {
+		return true, nil
+	}
+	_, err := fmt.Fprintf(stderr, "overwrite %s? [y/N] ", opts.To)
+	if err != nil {
+		return false, err
+	}
+	answer, err := terminal.ReadAnswer()
+	if err != nil {
+		return false, fmt.Errorf("error while reading answer: %v", err)
+	}
+	answer = strings.TrimSpace(answer)
+	return answer == "y" || answer == "Y", nil
+}
+
+// warnTerminalInput prints usage to stderr if the input would be typed by the user,
+// otherwise running without arguments silently waits for input
+func warnTerminalInput(opts *Options, terminal Terminal, stderr io.Writer) error {
+	if opts.From != "" || opts.StdinOK || !terminal.IsTerminal(os.Stdin) {
+		return nil
+	}
+	_, err := fmt.Fprintf(stderr, "Usage of %s:\n", os.Args[0])
+	if err != nil {
+		return err
+	}
+	output := flag.CommandLine.Output()
+	flag.CommandLine.SetOutput(stderr)
+	flag.PrintDefaults()
+	flag.CommandLine.SetOutput(output)
+	_, err = fmt.Fprintln(stderr, "reading from terminal; press Ctrl-D to end input or pass -from")
+	return err
+}
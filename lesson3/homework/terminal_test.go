@@ -0,0 +1,178 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTerminal struct {
+	terminal bool
+	answer   string
+	err      error
+	asked    bool
+}
+
+func (f *fakeTerminal) IsTerminal(*os.File) bool {
+	return f.terminal
+}
+
+func (f *fakeTerminal) ReadAnswer() (string, error) {
+	f.asked = true
+	return f.answer, f.err
+}
+
+func TestConfirmOverwrite(t *testing.T) {
+	existing, err := os.CreateTemp("", "existing")
+	assert.NoError(t, err)
+	defer os.Remove(existing.Name())
+	assert.NoError(t, existing.Close())
+
+	t.Run("ok, asks on terminal and accepts y", func(t *testing.T) {
+		terminal := &fakeTerminal{terminal: true, answer: "y\n"}
+		stderr := &strings.Builder{}
+
+		ok, err := confirmOverwrite(&Options{To: existing.Name(), Force: true}, terminal, stderr)
+
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.True(t, terminal.asked)
+		assert.Equal(t, "overwrite "+existing.Name()+"? [y/N] ", stderr.String())
+	})
+
+	t.Run("ok, asks on terminal and accepts Y", func(t *testing.T) {
+		terminal := &fakeTerminal{terminal: true, answer: "Y"}
+
+		ok, err := confirmOverwrite(&Options{To: existing.Name(), Force: true}, terminal, &strings.Builder{})
+
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("ok, asks on terminal and declines anything else", func(t *testing.T) {
+		for _, answer := range []string{"", "\n", "n\n", "yes\n", "no"} {
+			terminal := &fakeTerminal{terminal: true, answer: answer}
+
+			ok, err := confirmOverwrite(&Options{To: existing.Name(), Force: true}, terminal, &strings.Builder{})
+
+			assert.NoError(t, err)
+			assert.False(t, ok, "answer %q accepted", answer)
+		}
+	})
+
+	t.Run("ok, doesn't ask if stdout is not a terminal", func(t *testing.T) {
+		terminal := &fakeTerminal{terminal: false}
+		stderr := &strings.Builder{}
+
+		ok, err := confirmOverwrite(&Options{To: existing.Name(), Force: true}, terminal, stderr)
+
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.False(t, terminal.asked)
+		assert.Zero(t, stderr.Len())
+	})
+
+	t.Run("ok, doesn't ask with yes option", func(t *testing.T) {
+		terminal := &fakeTerminal{terminal: true}
+
+		ok, err := confirmOverwrite(&Options{To: existing.Name(), Force: true, Yes: true}, terminal, &strings.Builder{})
+
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.False(t, terminal.asked)
+	})
+
+	t.Run("ok, doesn't ask if output doesn't exist", func(t *testing.T) {
+		terminal := &fakeTerminal{terminal: true}
+
+		ok, err := confirmOverwrite(&Options{To: existing.Name() + ".new", Force: true}, terminal, &strings.Builder{})
+
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.False(t, terminal.asked)
+	})
+
+	t.Run("error while reading answer", func(t *testing.T) {
+		terminal := &fakeTerminal{terminal: true, err: errors.New("no tty")}
+
+		ok, err := confirmOverwrite(&Options{To: existing.Name(), Force: true}, terminal, &strings.Builder{})
+
+		assert.Error(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestWarnTerminalInput(t *testing.T) {
+	const message = "reading from terminal; press Ctrl-D to end input or pass -from\n"
+
+	t.Run("ok, warns when stdin is a terminal", func(t *testing.T) {
+		stderr := &strings.Builder{}
+
+		err := warnTerminalInput(&Options{}, &fakeTerminal{terminal: true}, stderr)
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(stderr.String(), "Usage of "), stderr.String())
+		assert.True(t, strings.HasSuffix(stderr.String(), message), stderr.String())
+	})
+
+	t.Run("ok, silent when stdin is not a terminal", func(t *testing.T) {
+		stderr := &strings.Builder{}
+
+		err := warnTerminalInput(&Options{}, &fakeTerminal{terminal: false}, stderr)
+
+		assert.NoError(t, err)
+		assert.Zero(t, stderr.Len())
+	})
+
+	t.Run("ok, silent with from option", func(t *testing.T) {
+		stderr := &strings.Builder{}
+
+		err := warnTerminalInput(&Options{From: "in.txt"}, &fakeTerminal{terminal: true}, stderr)
+
+		assert.NoError(t, err)
+		assert.Zero(t, stderr.Len())
+	})
+
+	t.Run("ok, silent with stdin-ok option", func(t *testing.T) {
+		stderr := &strings.Builder{}
+
+		err := warnTerminalInput(&Options{StdinOK: true}, &fakeTerminal{terminal: true}, stderr)
+
+		assert.NoError(t, err)
+		assert.Zero(t, stderr.Len())
+	})
+}
+
+func TestForceIntegration(t *testing.T) {
+	binPath := composeBinaryPath()
+	cmd := exec.Command("go", "build", "-o", binPath, "./")
+	assert.NoError(t, cmd.Run())
+	defer func() {
+		assert.NoError(t, os.Remove(binPath))
+	}()
+
+	t.Run("ok with existing output file and force option", func(t *testing.T) {
+		testFileName := "out.txt"
+		assert.NoError(t, os.WriteFile(testFileName, []byte("old content which is longer"), 0666))
+		defer os.Remove(testFileName)
+
+		cmd = exec.Command(binPath, "-to", testFileName, "-force")
+		cmd.Stdin = strings.NewReader(testInput)
+		stdout := &strings.Builder{}
+		cmd.Stdout = stdout
+		stderr := &strings.Builder{}
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+
+		assert.NoError(t, err)
+		assert.Zero(t, stderr.Len(), stderr.String())
+		data, err := os.ReadFile(testFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, testInput, string(data))
+	})
+}